@@ -14,4 +14,5 @@ var (
 	ErrorInvalidState         = DRMAA2Error{"Invalid state."}
 	ErrorInternal             = DRMAA2Error{"Internal error occurred."}
 	ErrorInvalidSession       = DRMAA2Error{"The session used for the method call is not valid."}
+	ErrorInvalidArgument      = DRMAA2Error{"The argument is invalid."}
 )
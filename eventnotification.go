@@ -0,0 +1,157 @@
+package drmaa2os
+
+import (
+	"sync"
+	"time"
+
+	"github.com/dgruber/drmaa2interface"
+	"github.com/dgruber/drmaa2os/pkg/helper"
+	"github.com/dgruber/drmaa2os/pkg/jobtracker"
+)
+
+// EventPollInterval defines how often job trackers which can't push
+// job events are polled for changes when event notification is
+// registered.
+var EventPollInterval = time.Second
+
+// eventBroker collects the job events of all open job sessions of a
+// SessionManager and distributes them to all event channels created
+// by RegisterEventNotification().
+type eventBroker struct {
+	sync.Mutex
+	subscribers []chan drmaa2interface.Notification
+	// sessions maps open job sessions to the function which stops
+	// the event source of the job session (nil when not subscribed)
+	sessions map[*JobSession]func()
+}
+
+func newEventBroker() *eventBroker {
+	return &eventBroker{
+		sessions: make(map[*JobSession]func()),
+	}
+}
+
+// addSession registers an open job session. When event notification
+// is registered the events of the job session are forwarded.
+func (b *eventBroker) addSession(js *JobSession) {
+	b.Lock()
+	defer b.Unlock()
+	b.sessions[js] = nil
+	if len(b.subscribers) > 0 {
+		b.sessions[js] = b.startSource(js)
+	}
+	js.onClose = func() {
+		b.removeSession(js)
+	}
+}
+
+// removeSession stops forwarding the events of a closed job session.
+func (b *eventBroker) removeSession(js *JobSession) {
+	b.Lock()
+	stop := b.sessions[js]
+	delete(b.sessions, js)
+	b.Unlock()
+	if stop != nil {
+		stop()
+	}
+}
+
+// subscribe creates a new event channel and starts collecting events
+// from all open job sessions if it is the first subscriber.
+func (b *eventBroker) subscribe() drmaa2interface.EventChannel {
+	b.Lock()
+	defer b.Unlock()
+	ch := make(chan drmaa2interface.Notification, 1024)
+	b.subscribers = append(b.subscribers, ch)
+	for js, stop := range b.sessions {
+		if stop == nil {
+			b.sessions[js] = b.startSource(js)
+		}
+	}
+	return ch
+}
+
+// unsubscribe removes and closes the event channel. The events of the
+// job sessions are not collected anymore when it was the last
+// subscriber.
+func (b *eventBroker) unsubscribe(ch drmaa2interface.EventChannel) error {
+	b.Lock()
+	defer b.Unlock()
+	for i, subscriber := range b.subscribers {
+		if drmaa2interface.EventChannel(subscriber) != ch {
+			continue
+		}
+		b.subscribers = append(b.subscribers[:i], b.subscribers[i+1:]...)
+		close(subscriber)
+		if len(b.subscribers) == 0 {
+			for js, stop := range b.sessions {
+				if stop != nil {
+					// stop asynchronously as the forwarding goroutine
+					// might wait for the lock in publish
+					go stop()
+					b.sessions[js] = nil
+				}
+			}
+		}
+		return nil
+	}
+	return ErrorInvalidArgument
+}
+
+// startSource forwards the events of the job session to all subscribers.
+// Job trackers which implement the jobtracker.EventNotifier interface
+// push their events, all others are polled. Must be called while holding
+// the lock.
+func (b *eventBroker) startSource(js *JobSession) func() {
+	var events drmaa2interface.EventChannel
+	var stopSource func()
+
+	tracker := js.tracker[0]
+	if notifier, ok := tracker.(jobtracker.EventNotifier); ok {
+		var err error
+		events, stopSource, err = notifier.RegisterEventNotification()
+		if err != nil {
+			events, stopSource = nil, nil
+		}
+	}
+	if events == nil {
+		events, stopSource = helper.PollEvents(tracker, js.name, EventPollInterval)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case notification, open := <-events:
+				if !open {
+					return
+				}
+				b.publish(notification)
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			stopSource()
+		})
+	}
+}
+
+// publish sends the notification to all subscribers. Events are dropped
+// for subscribers which don't consume their event channel so that they
+// can't block the job trackers.
+func (b *eventBroker) publish(notification drmaa2interface.Notification) {
+	b.Lock()
+	defer b.Unlock()
+	for _, subscriber := range b.subscribers {
+		select {
+		case subscriber <- notification:
+		default:
+		}
+	}
+}
@@ -56,6 +56,7 @@ require (
 require (
 	github.com/docker/go-units v0.5.0
 	github.com/shirou/gopsutil/v3 v3.24.5
+	golang.org/x/sys v0.28.0
 )
 
 require (
@@ -249,7 +250,6 @@ require (
 	golang.org/x/crypto v0.31.0 // indirect
 	golang.org/x/exp v0.0.0-20241009180824-f66d83c29e7c // indirect
 	golang.org/x/sync v0.10.0 // indirect
-	golang.org/x/sys v0.28.0 // indirect
 	golang.org/x/term v0.27.0 // indirect
 	golang.org/x/text v0.21.0 // indirect
 	golang.org/x/time v0.7.0 // indirect
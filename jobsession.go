@@ -19,6 +19,11 @@ type JobSession struct {
 	// libdrmaa stores newly created internal job session name
 	// inside contact string
 	contact string
+	// reservations is set when the backend supports advance
+	// reservations (process backend)
+	reservations *reservationStore
+	// onClose is called when the job session gets closed
+	onClose func()
 }
 
 // Close MUST perform the necessary action to disengage from the DRM system.
@@ -35,6 +40,9 @@ func (js *JobSession) Close() error {
 		return ErrorInvalidSession
 	}
 	var err error
+	if js.onClose != nil {
+		js.onClose()
+	}
 	if closer, ok := js.tracker[0].(jobtracker.Closer); ok {
 		// disengage from storage and DRM system
 		err = closer.Close()
@@ -64,6 +72,16 @@ func (js *JobSession) GetSessionName() (string, error) {
 	return js.name, nil
 }
 
+// JobTracker returns the JobTracker which manages the jobs of the job
+// session or nil if the job session is closed. It allows to serve a
+// job session through the remote JobTracker API.
+func (js *JobSession) JobTracker() jobtracker.JobTracker {
+	if len(js.tracker) == 0 {
+		return nil
+	}
+	return js.tracker[0]
+}
+
 // GetJobCategories provides the list of valid job category names which
 // can be used for the jobCategory attribute in a JobTemplate instance.
 func (js *JobSession) GetJobCategories() ([]string, error) {
@@ -178,6 +196,9 @@ func (js *JobSession) RunJob(jt drmaa2interface.JobTemplate) (drmaa2interface.Jo
 	if err != nil {
 		return nil, fmt.Errorf("failed to copy job template: %w", err)
 	}
+	if jt.ReservationID != "" && js.reservations != nil {
+		return js.runReservedJob(jtCopy.(drmaa2interface.JobTemplate))
+	}
 	id, err := js.tracker[0].AddJob(jtCopy.(drmaa2interface.JobTemplate))
 	if err != nil {
 		return nil, err
@@ -192,6 +213,10 @@ func (js *JobSession) RunBulkJobs(jt drmaa2interface.JobTemplate, begin, end, st
 	if err != nil {
 		return nil, fmt.Errorf("failed to copy job template: %w", err)
 	}
+	if jt.ReservationID != "" && js.reservations != nil {
+		return js.runReservedBulkJobs(jtCopy.(drmaa2interface.JobTemplate),
+			begin, end, step, maxParallel)
+	}
 	id, err := js.tracker[0].AddArrayJob(jtCopy.(drmaa2interface.JobTemplate),
 		begin, end, step, maxParallel)
 	if err != nil {
@@ -76,3 +76,107 @@ func waitAny(waitForStartedState bool, jobs []drmaa2interface.Job, timeout time.
 		}
 	}
 }
+
+// runReservedJob submits a job into the advance reservation referenced
+// in the job template. The job is only accepted when the reservation
+// has not ended and has enough free slots. Jobs submitted before the
+// reservation starts are queued until the start of the reservation.
+// Jobs which are still running at the end of the reservation are
+// terminated.
+func (js *JobSession) runReservedJob(jt drmaa2interface.JobTemplate) (drmaa2interface.Job, error) {
+	tracker := js.tracker[0]
+	var id string
+	end, err := js.reservations.submit(js.name, jt.ReservationID, jobSlots(jt), false,
+		js.isReservedJobActive,
+		func(start time.Time) (string, error) {
+			var errAdd error
+			delayUntil(&jt, start)
+			id, errAdd = tracker.AddJob(jt)
+			return id, errAdd
+		})
+	if err != nil {
+		return nil, err
+	}
+	go js.reservations.superviseReservedJobs(tracker, jt.ReservationID, js.name,
+		id, []string{id}, end)
+	return newJob(id, js.name, jt, tracker), nil
+}
+
+// runReservedBulkJobs submits a job array into the advance reservation
+// referenced in the job template. The amount of slots required is
+// derived by the amount of tasks which can run in parallel.
+func (js *JobSession) runReservedBulkJobs(jt drmaa2interface.JobTemplate, begin, end, step, maxParallel int) (drmaa2interface.ArrayJob, error) {
+	tracker := js.tracker[0]
+	if step <= 0 {
+		step = 1
+	}
+	parallel := int64((end-begin)/step + 1)
+	if maxParallel > 0 && int64(maxParallel) < parallel {
+		parallel = int64(maxParallel)
+	}
+	var id string
+	reservationEnd, err := js.reservations.submit(js.name, jt.ReservationID,
+		parallel*jobSlots(jt), true, js.isReservedJobActive,
+		func(start time.Time) (string, error) {
+			var errAdd error
+			delayUntil(&jt, start)
+			id, errAdd = tracker.AddArrayJob(jt, begin, end, step, maxParallel)
+			return id, errAdd
+		})
+	if err != nil {
+		return nil, err
+	}
+	taskIDs, err := tracker.ListArrayJobs(id)
+	if err != nil {
+		return nil, err
+	}
+	go js.reservations.superviseReservedJobs(tracker, jt.ReservationID, js.name,
+		id, taskIDs, reservationEnd)
+	return js.GetJobArray(id)
+}
+
+// isReservedJobActive returns true if the job still consumes slots of
+// a reservation. Jobs of other job sessions can't be checked, they free
+// their slots when they are finished.
+func (js *JobSession) isReservedJobActive(job reservedJob) bool {
+	if job.SessionName != js.name {
+		return true
+	}
+	tracker := js.tracker[0]
+	jobIDs := []string{job.JobID}
+	if job.IsArrayJob {
+		var err error
+		jobIDs, err = tracker.ListArrayJobs(job.JobID)
+		if err != nil {
+			return false
+		}
+	}
+	for _, jobID := range jobIDs {
+		state, _, err := tracker.JobState(jobID)
+		if err != nil {
+			continue
+		}
+		switch state {
+		case drmaa2interface.Done, drmaa2interface.Failed, drmaa2interface.Undetermined:
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// delayUntil sets the start time of the job template so that the job
+// does not start before the given time.
+func delayUntil(jt *drmaa2interface.JobTemplate, start time.Time) {
+	if jt.StartTime.Before(start) {
+		jt.StartTime = start
+	}
+}
+
+// jobSlots returns the amount of slots a job requires.
+func jobSlots(jt drmaa2interface.JobTemplate) int64 {
+	if jt.MinSlots > 0 {
+		return jt.MinSlots
+	}
+	return 1
+}
@@ -53,6 +53,13 @@ var _ = Describe("JobSession", func() {
 			Ω(js.Close()).Should(BeNil())
 		})
 
+		It("should return the job tracker of the job session", func() {
+			session := js.(*drmaa2os.JobSession)
+			Ω(session.JobTracker()).ShouldNot(BeNil())
+			Ω(js.Close()).Should(BeNil())
+			Ω(session.JobTracker()).Should(BeNil())
+		})
+
 		It("should be to get the contact string", func() {
 			_, err := js.GetContact()
 			Ω(err).Should(BeNil())
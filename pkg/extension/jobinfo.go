@@ -24,6 +24,9 @@ const (
 	JobInfoDefaultJSessionOutBlock   string = "ru_outblock"
 	JobInfoDefaultJSessionSystemTime string = "system_time_ms"
 	JobInfoDefaultJSessionUserTime   string = "user_time_ms"
+	// JobInfoDefaultJSessionMemoryPeak is the max. memory usage of all
+	// processes of a job in kilobytes (requires a cgroup for the job)
+	JobInfoDefaultJSessionMemoryPeak string = "memory_peak"
 )
 
 // JobInfo extensions for Kubernetes backend (kubernetes session)
@@ -32,3 +35,16 @@ const (
 	// JobInfoK8sJSessionJobOutput refers to the output of the job
 	JobInfoK8sJSessionJobOutput string = "output"
 )
+
+// JobInfo extensions of the retry tracker
+
+const (
+	// JobInfoRetryAttempts is the number of attempts of a ReRunnable
+	// job including the current attempt.
+	JobInfoRetryAttempts string = "retry_attempts"
+	// JobInfoRetryAttemptPrefix prefixes the keys of the failed attempts
+	// of a job which were resubmitted ("retry_attempt_1", ...). The value
+	// contains the job ID of the attempt, its exit status, and its
+	// terminating signal (like "job_id=4 exit_status=1 signal=none").
+	JobInfoRetryAttemptPrefix string = "retry_attempt_"
+)
@@ -97,3 +97,28 @@ const (
 	// Example: StageInFiles["/container/dir"] = JobTemplateK8sStageInFromNFSVolume + "server:path/to/nfs/volume"
 	JobTemplateK8sStageInFromNFSVolumePrefix string = "nfs:"
 )
+
+// JobTemplate extensions for the process backend
+
+const (
+	// JobTemplateStageInHardLink when set to TRUE hard-links the files
+	// of StageInFiles into the working directory of the job instead of
+	// copying them. Files which can't be hard-linked (like files on a
+	// different file system) are copied.
+	JobTemplateStageInHardLink string = "stage-in-hardlink"
+	// JobTemplateOutputAppend when set to TRUE appends the output of
+	// the job to existing OutputPath and ErrorPath files instead of
+	// truncating them.
+	JobTemplateOutputAppend string = "output-append"
+)
+
+// JobTemplate extensions for the slurm backend
+
+const (
+	// JobTemplateSlurmOptionPrefix is the prefix of extensions which are
+	// passed as sbatch options. The key without the prefix is the long
+	// option name and the value its argument (empty for options without
+	// argument).
+	// Example: ExtensionList[JobTemplateSlurmOptionPrefix + "constraint"] = "intel"
+	JobTemplateSlurmOptionPrefix string = "slurm."
+)
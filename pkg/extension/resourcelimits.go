@@ -0,0 +1,32 @@
+package extension
+
+// JobTemplate ResourceLimits keys as defined by the DRMAA2 standard.
+// Which of the limits are supported depends on the backend.
+const (
+	// ResourceLimitCoreFileSize is the max. size of a core dump in kilobytes
+	ResourceLimitCoreFileSize string = "CORE_FILE_SIZE"
+	// ResourceLimitCPUTime is the max. CPU time of a job (duration like "1h"
+	// or seconds)
+	ResourceLimitCPUTime string = "CPU_TIME"
+	// ResourceLimitDataSegSize is the max. data segment size in kilobytes
+	ResourceLimitDataSegSize string = "DATA_SEG_SIZE"
+	// ResourceLimitFileSize is the max. size of a file in kilobytes
+	ResourceLimitFileSize string = "FILE_SIZE"
+	// ResourceLimitOpenFiles is the max. number of open file descriptors
+	ResourceLimitOpenFiles string = "OPEN_FILES"
+	// ResourceLimitStackSize is the max. stack size in kilobytes
+	ResourceLimitStackSize string = "STACK_SIZE"
+	// ResourceLimitVirtualMemory is the max. amount of memory in kilobytes
+	ResourceLimitVirtualMemory string = "VIRTUAL_MEMORY"
+	// ResourceLimitWallclockTime is the max. run time of a job (duration
+	// like "1h" or seconds)
+	ResourceLimitWallclockTime string = "WALLCLOCK_TIME"
+)
+
+// JobTemplate ResourceLimits keys which are not defined by the DRMAA2
+// standard.
+const (
+	// ResourceLimitProcesses is the max. number of processes and threads
+	// of a job
+	ResourceLimitProcesses string = "PROCESSES"
+)
@@ -0,0 +1,133 @@
+package helper
+
+import (
+	"sync"
+	"time"
+
+	"github.com/dgruber/drmaa2interface"
+	"github.com/dgruber/drmaa2os/pkg/jobtracker"
+)
+
+// JobInfoChangeEvents compares two job infos of the same job and returns
+// the DRMAA2 events which describe the change. A state change results in
+// a NewState event, a change of the allocated machines of a job in a
+// Migrated event. Other changes are reported as AttributeChange when the
+// state did not change. Continuously increasing usage values (wallclock
+// time, CPU time) are not treated as attribute changes.
+func JobInfoChangeEvents(previous, current drmaa2interface.JobInfo) []drmaa2interface.Event {
+	events := make([]drmaa2interface.Event, 0, 2)
+	if previous.State != current.State {
+		events = append(events, drmaa2interface.NewState)
+	}
+	if len(previous.AllocatedMachines) > 0 && len(current.AllocatedMachines) > 0 &&
+		!equalStrings(previous.AllocatedMachines, current.AllocatedMachines) {
+		events = append(events, drmaa2interface.Migrated)
+	}
+	if previous.State == current.State &&
+		(previous.SubState != current.SubState ||
+			previous.ExitStatus != current.ExitStatus ||
+			previous.TerminatingSignal != current.TerminatingSignal ||
+			previous.Annotation != current.Annotation ||
+			previous.Slots != current.Slots ||
+			previous.QueueName != current.QueueName ||
+			previous.JobOwner != current.JobOwner ||
+			!previous.DispatchTime.Equal(current.DispatchTime) ||
+			!previous.FinishTime.Equal(current.FinishTime)) {
+		events = append(events, drmaa2interface.AttributeChange)
+	}
+	return events
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// PollEvents creates an event channel for job trackers which can't push
+// job events themselves. It polls the job infos of all jobs of the job
+// tracker in the given interval and emits notifications for all changes.
+// Jobs which exist when polling starts are the baseline, jobs which appear
+// later are reported with a NewState event. The returned function stops
+// polling and closes the channel.
+func PollEvents(jt jobtracker.JobTracker, sessionName string, interval time.Duration) (drmaa2interface.EventChannel, func()) {
+	ch := make(chan drmaa2interface.Notification, 128)
+	done := make(chan struct{})
+	var once sync.Once
+	stop := func() {
+		once.Do(func() { close(done) })
+	}
+
+	go func() {
+		defer close(ch)
+		t := time.NewTicker(interval)
+		defer t.Stop()
+
+		known, _ := pollJobInfos(jt)
+		for {
+			select {
+			case <-done:
+				return
+			case <-t.C:
+			}
+			current, err := pollJobInfos(jt)
+			if err != nil {
+				// keep the last known state for the next round
+				continue
+			}
+			for jobID, info := range current {
+				var events []drmaa2interface.Event
+				if previous, exists := known[jobID]; exists {
+					events = JobInfoChangeEvents(previous, info)
+				} else {
+					events = []drmaa2interface.Event{drmaa2interface.NewState}
+				}
+				for _, event := range events {
+					select {
+					case ch <- drmaa2interface.Notification{
+						Evt:         event,
+						JobID:       jobID,
+						SessionName: sessionName,
+						State:       info.State,
+					}:
+					case <-done:
+						return
+					}
+				}
+			}
+			known = current
+		}
+	}()
+
+	return ch, stop
+}
+
+// pollJobInfos returns the job infos of all jobs of the job tracker.
+// The state is always taken from JobState(). When the job info can't
+// be retrieved only the job state is used.
+func pollJobInfos(jt jobtracker.JobTracker) (map[string]drmaa2interface.JobInfo, error) {
+	infos := make(map[string]drmaa2interface.JobInfo)
+	jobIDs, err := jt.ListJobs()
+	if err != nil {
+		return infos, err
+	}
+	for _, jobID := range jobIDs {
+		state, _, err := jt.JobState(jobID)
+		if err != nil {
+			continue
+		}
+		info, err := jt.JobInfo(jobID)
+		if err != nil {
+			info = drmaa2interface.JobInfo{}
+		}
+		info.State = state
+		infos[jobID] = info
+	}
+	return infos, nil
+}
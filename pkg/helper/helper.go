@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/dgruber/drmaa2interface"
@@ -36,7 +37,8 @@ func Guids2ArrayJobID(guids []string) string {
 // jobs through the AddJob() method of the given job tracker. This function
 // is typically needed when a DRM does not support job arrays natively.
 // The returned array job ID is created from all of the returned job IDs and
-// does not work with the DRM directly.
+// does not work with the DRM directly. The bulk job index placeholder
+// is replaced by the task ID in the job template of each job.
 func AddArrayJobAsSingleJobs(jt drmaa2interface.JobTemplate, t jobtracker.JobTracker, begin, end, step int) (string, error) {
 	var guids []string
 	for i := begin; i <= end; i += step {
@@ -48,7 +50,8 @@ func AddArrayJobAsSingleJobs(jt drmaa2interface.JobTemplate, t jobtracker.JobTra
 		} else {
 			jt.JobEnvironment["TASK_ID"] = fmt.Sprintf("%d", i)
 		}
-		guid, err := t.AddJob(jt)
+		task := replacePlaceholder(jt, PlaceholderBulkJobIndex, strconv.Itoa(i), true)
+		guid, err := t.AddJob(task)
 		if err != nil {
 			return Guids2ArrayJobID(guids), err
 		}
@@ -151,3 +154,23 @@ func GetJobTemplateFromBase64(base64encondedJT string) (drmaa2interface.JobTempl
 	}
 	return jt, nil
 }
+
+// ParseDurationLimit parses a time based resource limit of a job
+// template (like WALLCLOCK_TIME). The value is either a duration
+// (like "1h30m") or an amount of seconds (like "5400").
+func ParseDurationLimit(value string) (time.Duration, error) {
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if seconds < 0 {
+			return 0, fmt.Errorf("negative duration %q", value)
+		}
+		return time.Duration(seconds) * time.Second, nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q: %v", value, err)
+	}
+	if duration < 0 {
+		return 0, fmt.Errorf("negative duration %q", value)
+	}
+	return duration, nil
+}
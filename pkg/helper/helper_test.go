@@ -138,4 +138,178 @@ var _ = Describe("Helper", func() {
 
 	})
 
+	Context("Resource limits", func() {
+
+		It("should parse durations and seconds", func() {
+			d, err := ParseDurationLimit("90")
+			Ω(err).Should(BeNil())
+			Ω(d).Should(Equal(90 * time.Second))
+			d, err = ParseDurationLimit("1h30m")
+			Ω(err).Should(BeNil())
+			Ω(d).Should(Equal(90 * time.Minute))
+			_, err = ParseDurationLimit("-5")
+			Ω(err).ShouldNot(BeNil())
+			_, err = ParseDurationLimit("tomorrow")
+			Ω(err).ShouldNot(BeNil())
+		})
+
+	})
+
+})
+
+var _ = Describe("Events", func() {
+
+	Context("Job info changes", func() {
+
+		It("should detect state changes", func() {
+			events := JobInfoChangeEvents(
+				drmaa2interface.JobInfo{State: drmaa2interface.Queued},
+				drmaa2interface.JobInfo{State: drmaa2interface.Running})
+			Ω(events).Should(ConsistOf(drmaa2interface.NewState))
+		})
+
+		It("should detect migrations and attribute changes", func() {
+			events := JobInfoChangeEvents(
+				drmaa2interface.JobInfo{
+					State:             drmaa2interface.Running,
+					AllocatedMachines: []string{"host1"},
+				},
+				drmaa2interface.JobInfo{
+					State:             drmaa2interface.Running,
+					AllocatedMachines: []string{"host2"},
+					SubState:          "migrated",
+				})
+			Ω(events).Should(ConsistOf(drmaa2interface.Migrated,
+				drmaa2interface.AttributeChange))
+		})
+
+		It("should ignore changing usage values", func() {
+			events := JobInfoChangeEvents(
+				drmaa2interface.JobInfo{
+					State:         drmaa2interface.Running,
+					WallclockTime: time.Second,
+				},
+				drmaa2interface.JobInfo{
+					State:         drmaa2interface.Running,
+					WallclockTime: time.Second * 2,
+					CPUTime:       1,
+				})
+			Ω(events).Should(BeEmpty())
+		})
+
+	})
+
+	Context("Polling events", func() {
+
+		It("should emit notifications for new jobs and state changes", func() {
+			fakeTracker := simpletrackerfakes.New("testsession")
+			ch, stop := PollEvents(fakeTracker, "testsession", time.Millisecond*10)
+			defer stop()
+
+			// wait for baseline
+			<-time.After(time.Millisecond * 50)
+
+			jobID, err := fakeTracker.AddJob(drmaa2interface.JobTemplate{RemoteCommand: "test"})
+			Ω(err).Should(BeNil())
+
+			var notification drmaa2interface.Notification
+			Eventually(ch).Should(Receive(&notification))
+			Ω(notification.Evt).Should(Equal(drmaa2interface.NewState))
+			Ω(notification.JobID).Should(Equal(jobID))
+			Ω(notification.SessionName).Should(Equal("testsession"))
+			Ω(notification.State).Should(Equal(drmaa2interface.Running))
+
+			err = fakeTracker.JobControl(jobID, jobtracker.JobControlSuspend)
+			Ω(err).Should(BeNil())
+			Eventually(ch).Should(Receive(&notification))
+			Ω(notification.Evt).Should(Equal(drmaa2interface.NewState))
+			Ω(notification.State).Should(Equal(drmaa2interface.Suspended))
+		})
+
+		It("should close the channel when stopped", func() {
+			fakeTracker := simpletrackerfakes.New("testsession")
+			ch, stop := PollEvents(fakeTracker, "testsession", time.Millisecond*10)
+			stop()
+			Eventually(ch).Should(BeClosed())
+		})
+
+	})
+
+})
+
+// recordingTracker records the job templates of the submitted jobs.
+type recordingTracker struct {
+	*simpletrackerfakes.JobTracker
+	templates []drmaa2interface.JobTemplate
+}
+
+func (rt *recordingTracker) AddJob(t drmaa2interface.JobTemplate) (string, error) {
+	rt.templates = append(rt.templates, t)
+	return rt.JobTracker.AddJob(t)
+}
+
+var _ = Describe("Placeholders", func() {
+
+	var jt drmaa2interface.JobTemplate
+
+	BeforeEach(func() {
+		jt = drmaa2interface.JobTemplate{
+			RemoteCommand:    "/bin/echo",
+			Args:             []string{PlaceholderBulkJobIndex, PlaceholderWorkingDirectory + "/in"},
+			WorkingDirectory: PlaceholderHomeDirectory + "/run" + PlaceholderBulkJobIndex,
+			InputPath:        PlaceholderWorkingDirectory + "/in.txt",
+			OutputPath:       PlaceholderHomeDirectory + "/out." + PlaceholderBulkJobIndex,
+			ErrorPath:        "err." + PlaceholderBulkJobIndex,
+		}
+	})
+
+	It("should replace the placeholders of a task", func() {
+		replaced := ReplacePlaceholders(jt, 3, true, "/home/user")
+		Ω(replaced.WorkingDirectory).Should(Equal("/home/user/run3"))
+		Ω(replaced.InputPath).Should(Equal("/home/user/run3/in.txt"))
+		Ω(replaced.OutputPath).Should(Equal("/home/user/out.3"))
+		Ω(replaced.ErrorPath).Should(Equal("err.3"))
+		Ω(replaced.Args).Should(Equal([]string{"3", "/home/user/run3/in"}))
+		// the given job template is not changed
+		Ω(jt.Args[0]).Should(Equal(PlaceholderBulkJobIndex))
+	})
+
+	It("should replace the bulk job index of task 0", func() {
+		replaced := ReplacePlaceholders(jt, 0, true, "/home/user")
+		Ω(replaced.WorkingDirectory).Should(Equal("/home/user/run0"))
+		Ω(replaced.OutputPath).Should(Equal("/home/user/out.0"))
+		Ω(replaced.Args).Should(Equal([]string{"0", "/home/user/run0/in"}))
+	})
+
+	It("should replace the bulk job index of a job by an empty string", func() {
+		jt.WorkingDirectory = ""
+		replaced := ReplacePlaceholders(jt, 0, false, "/home/user")
+		Ω(replaced.InputPath).Should(Equal("./in.txt"))
+		Ω(replaced.ErrorPath).Should(Equal("err."))
+		Ω(replaced.Args).Should(Equal([]string{"", "./in"}))
+	})
+
+	It("should keep the bulk job index when replacing directories", func() {
+		replaced := ReplaceDirectoryPlaceholders(jt, "/home/user")
+		Ω(replaced.OutputPath).Should(Equal("/home/user/out." + PlaceholderBulkJobIndex))
+		Ω(replaced.Args[0]).Should(Equal(PlaceholderBulkJobIndex))
+	})
+
+	It("should use HOME of the job environment as home directory in containers", func() {
+		Ω(ContainerHomeDirectory(jt)).Should(Equal(DefaultContainerHomeDirectory))
+		jt.JobEnvironment = map[string]string{"HOME": "/home/app"}
+		Ω(ContainerHomeDirectory(jt)).Should(Equal("/home/app"))
+	})
+
+	It("should replace the bulk job index of array jobs submitted as single jobs", func() {
+		tracker := &recordingTracker{JobTracker: simpletrackerfakes.New("testsession")}
+		_, err := AddArrayJobAsSingleJobs(jt, tracker, 1, 5, 2)
+		Ω(err).Should(BeNil())
+		Ω(tracker.templates).Should(HaveLen(3))
+		for i, task := range []string{"1", "3", "5"} {
+			Ω(tracker.templates[i].Args[0]).Should(Equal(task))
+			Ω(tracker.templates[i].ErrorPath).Should(Equal("err." + task))
+		}
+	})
+
 })
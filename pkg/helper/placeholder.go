@@ -0,0 +1,83 @@
+package helper
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/dgruber/drmaa2interface"
+)
+
+// DRMAA placeholders which can be used in the InputPath, OutputPath,
+// ErrorPath, WorkingDirectory, and Args of a job template.
+const (
+	// PlaceholderBulkJobIndex is replaced by the task ID of a task of
+	// an array job. For jobs it is replaced by an empty string.
+	PlaceholderBulkJobIndex string = "$drmaa_incr_ph$"
+	// PlaceholderHomeDirectory is replaced by the home directory of the
+	// user in the execution environment of the job.
+	PlaceholderHomeDirectory string = "$drmaa_hd_ph$"
+	// PlaceholderWorkingDirectory is replaced by the WorkingDirectory of
+	// the job template or by "." when it is not set. It can't be used in
+	// the WorkingDirectory itself.
+	PlaceholderWorkingDirectory string = "$drmaa_wd_ph$"
+)
+
+// DefaultContainerHomeDirectory is the home directory of jobs running
+// in containers when HOME is not set in the JobEnvironment.
+const DefaultContainerHomeDirectory string = "/root"
+
+// ReplacePlaceholders returns a copy of the job template in which the
+// DRMAA placeholders in InputPath, OutputPath, ErrorPath, WorkingDirectory,
+// and Args are replaced. isTask is true for a task of an array job and
+// taskID is its task ID which can be 0. For jobs taskID is ignored.
+// homeDir is the home directory of the user in the execution environment
+// of the job.
+func ReplacePlaceholders(jt drmaa2interface.JobTemplate, taskID int, isTask bool, homeDir string) drmaa2interface.JobTemplate {
+	jt = ReplaceDirectoryPlaceholders(jt, homeDir)
+	index := ""
+	if isTask {
+		index = strconv.Itoa(taskID)
+	}
+	return replacePlaceholder(jt, PlaceholderBulkJobIndex, index, true)
+}
+
+// ReplaceDirectoryPlaceholders replaces the home directory and working
+// directory placeholders like ReplacePlaceholders but keeps the bulk job
+// index placeholder. That is required for array jobs of backends which
+// know the task ID only when the task is running.
+func ReplaceDirectoryPlaceholders(jt drmaa2interface.JobTemplate, homeDir string) drmaa2interface.JobTemplate {
+	jt = replacePlaceholder(jt, PlaceholderHomeDirectory, homeDir, true)
+	workingDir := jt.WorkingDirectory
+	if workingDir == "" {
+		workingDir = "."
+	}
+	return replacePlaceholder(jt, PlaceholderWorkingDirectory, workingDir, false)
+}
+
+// ContainerHomeDirectory returns the home directory of a job running in a
+// container which is HOME of the JobEnvironment or the home directory of
+// the root user.
+func ContainerHomeDirectory(jt drmaa2interface.JobTemplate) string {
+	if home, exists := jt.JobEnvironment["HOME"]; exists && home != "" {
+		return home
+	}
+	return DefaultContainerHomeDirectory
+}
+
+func replacePlaceholder(jt drmaa2interface.JobTemplate, placeholder, value string, inWorkingDir bool) drmaa2interface.JobTemplate {
+	jt.InputPath = strings.ReplaceAll(jt.InputPath, placeholder, value)
+	jt.OutputPath = strings.ReplaceAll(jt.OutputPath, placeholder, value)
+	jt.ErrorPath = strings.ReplaceAll(jt.ErrorPath, placeholder, value)
+	if inWorkingDir {
+		jt.WorkingDirectory = strings.ReplaceAll(jt.WorkingDirectory, placeholder, value)
+	}
+	if jt.Args != nil {
+		// the args of the given job template must not be changed
+		args := make([]string, 0, len(jt.Args))
+		for _, arg := range jt.Args {
+			args = append(args, strings.ReplaceAll(arg, placeholder, value))
+		}
+		jt.Args = args
+	}
+	return jt
+}
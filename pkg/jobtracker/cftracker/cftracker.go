@@ -60,6 +60,28 @@ func (a *allocator) New(jobSessionName string, jobTrackerInitParams interface{})
 	return New(cfParams[0], cfParams[1], cfParams[2], jobSessionName)
 }
 
+// DrmsName returns "cloudfoundry".
+func (a *allocator) DrmsName() string {
+	return "cloudfoundry"
+}
+
+// DrmsVersion returns an error as the Cloud Foundry API endpoint
+// is only known when a job session is created.
+func (a *allocator) DrmsVersion() (drmaa2interface.Version, error) {
+	return drmaa2interface.Version{}, errors.New("Cloud Foundry version is only available within a job session")
+}
+
+// Supports returns false as none of the optional DRMAA2 capabilities
+// are implemented.
+func (a *allocator) Supports(capability drmaa2interface.Capability) bool {
+	return false
+}
+
+// SupportsJobControl returns true for terminate.
+func (a *allocator) SupportsJobControl(action string) bool {
+	return action == jobtracker.JobControlTerminate
+}
+
 func New(addr, username, password, jobsession string) (*cftracker, error) {
 	config := &cfclient.Config{
 		ApiAddress: addr,
@@ -16,6 +16,8 @@ import (
 func (t *ContainerdJobTracker) AddJob(jt drmaa2interface.JobTemplate) (string, error) {
 	ctx := namespaces.WithNamespace(context.Background(), "default")
 
+	jt = helper.ReplacePlaceholders(jt, 0, false, helper.ContainerHomeDirectory(jt))
+
 	if jt.JobName == "" {
 		jt.JobName = "drmaa2os-job-" + fmt.Sprintf("%d", time.Now().UnixNano())
 	}
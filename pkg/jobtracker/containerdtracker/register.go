@@ -1,8 +1,12 @@
 package containerdtracker
 
 import (
+	"context"
 	"errors"
+	"strings"
 
+	"github.com/containerd/containerd"
+	"github.com/dgruber/drmaa2interface"
 	"github.com/dgruber/drmaa2os"
 	"github.com/dgruber/drmaa2os/pkg/jobtracker"
 )
@@ -24,3 +28,40 @@ func (a *allocator) New(jobSessionName string, jobTrackerInitParams interface{})
 	return NewContainerdJobTracker(jobSessionName,
 		containerdParams.ContainerdAddr)
 }
+
+// DrmsName returns "containerd".
+func (a *allocator) DrmsName() string {
+	return "containerd"
+}
+
+// DrmsVersion returns the version of containerd listening on the
+// default socket.
+func (a *allocator) DrmsVersion() (drmaa2interface.Version, error) {
+	client, err := containerd.New("/run/containerd/containerd.sock")
+	if err != nil {
+		return drmaa2interface.Version{}, err
+	}
+	defer client.Close()
+	version, err := client.Version(context.Background())
+	if err != nil {
+		return drmaa2interface.Version{}, err
+	}
+	major, minor, _ := strings.Cut(strings.TrimPrefix(version.Version, "v"), ".")
+	return drmaa2interface.Version{Major: major, Minor: minor}, nil
+}
+
+// Supports returns false as none of the optional DRMAA2 capabilities
+// are implemented.
+func (a *allocator) Supports(capability drmaa2interface.Capability) bool {
+	return false
+}
+
+// SupportsJobControl returns true for suspend, resume, and terminate.
+func (a *allocator) SupportsJobControl(action string) bool {
+	switch action {
+	case jobtracker.JobControlSuspend, jobtracker.JobControlResume,
+		jobtracker.JobControlTerminate:
+		return true
+	}
+	return false
+}
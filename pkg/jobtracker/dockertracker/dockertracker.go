@@ -33,6 +33,43 @@ func (a *allocator) New(jobSessionName string, jobTrackerInitParams interface{})
 	return New(jobSessionName)
 }
 
+// DrmsName returns "docker".
+func (a *allocator) DrmsName() string {
+	return "docker"
+}
+
+// DrmsVersion returns the version of the Docker daemon which is
+// configured in the environment (see New()).
+func (a *allocator) DrmsVersion() (drmaa2interface.Version, error) {
+	cli, err := client.NewEnvClient()
+	if err != nil {
+		return drmaa2interface.Version{}, err
+	}
+	defer cli.Close()
+	version, err := cli.ServerVersion(context.Background())
+	if err != nil {
+		return drmaa2interface.Version{}, err
+	}
+	major, minor, _ := strings.Cut(version.Version, ".")
+	return drmaa2interface.Version{Major: major, Minor: minor}, nil
+}
+
+// Supports returns true for file staging which is implemented by
+// mounting the StageInFiles into the container.
+func (a *allocator) Supports(capability drmaa2interface.Capability) bool {
+	return capability == drmaa2interface.JtStaging
+}
+
+// SupportsJobControl returns true for suspend, resume, and terminate.
+func (a *allocator) SupportsJobControl(action string) bool {
+	switch action {
+	case jobtracker.JobControlSuspend, jobtracker.JobControlResume,
+		jobtracker.JobControlTerminate:
+		return true
+	}
+	return false
+}
+
 type DockerTracker struct {
 	jobsession string
 	cli        *client.Client
@@ -75,6 +112,7 @@ func (dt *DockerTracker) AddJob(jt drmaa2interface.JobTemplate) (string, error)
 	if err := dt.check(); err != nil {
 		return "", err
 	}
+	jt = helper.ReplacePlaceholders(jt, 0, false, helper.ContainerHomeDirectory(jt))
 	return runJob(dt.jobsession, dt.cli, jt)
 }
 
@@ -26,7 +26,8 @@ const JobControlRelease = "release"
 // in order to be able to be hooked into the DRMAA2OS framework.
 // Additionaly functionalities of a JobTracker are defined by additional
 // interfaces implemented by the same object. Those interfaces are
-// listed below (ContactStringer, JobTemplater, Closer, Monitorer).
+// listed below (ContactStringer, JobTemplater, Closer, Monitorer,
+// EventNotifier). Capabilities is implemented by the Allocator.
 type JobTracker interface {
 	// ListJobs returns all visible job IDs or an error.
 	ListJobs() ([]string, error)
@@ -110,6 +111,34 @@ type Monitorer interface {
 	JobInfoFromMonitor(id string) (drmaa2interface.JobInfo, error)
 }
 
+// EventNotifier is a JobTracker which pushes job events (like job state
+// changes) of all jobs of its job session instead of requiring the job
+// states to be polled. The returned function stops the notification.
+// The channel needs to be consumed in the rate the events are created.
+type EventNotifier interface {
+	RegisterEventNotification() (drmaa2interface.EventChannel, func(), error)
+}
+
+// Capabilities declares the name and version of the backend a JobTracker
+// implementation manages as well as the optional DRMAA2 functionality
+// it supports. The interface is implemented by the Allocator which is
+// registered at the SessionManager so that the capabilities can be
+// queried without creating a job session.
+type Capabilities interface {
+	// DrmsName returns the name of the backend (like "slurm").
+	DrmsName() string
+	// DrmsVersion returns the version of the backend. An error is
+	// returned when the version can't be determined.
+	DrmsVersion() (drmaa2interface.Version, error)
+	// Supports returns true if the given optional DRMAA2 functionality
+	// (like BulkJobsMaxParallel or JobTemplate fields like JtDeadline)
+	// is supported.
+	Supports(capability drmaa2interface.Capability) bool
+	// SupportsJobControl returns true if the given JobControl action
+	// (like JobControlHold) is supported.
+	SupportsJobControl(action string) bool
+}
+
 // constants for Monitorer struct extensions
 
 // deprecated - use extension package
@@ -0,0 +1,358 @@
+package kubernetestracker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/dgruber/drmaa2interface"
+	"github.com/dgruber/drmaa2os/pkg/extension"
+	"github.com/dgruber/drmaa2os/pkg/helper"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Job arrays are mapped to one Indexed Job. The tasks of the job array
+// are addressed by "<job name>:<task ID>". The colon is not allowed in
+// Kubernetes object names so task IDs can't collide with job IDs.
+const arrayTaskSeparator = ":"
+
+// Annotations of an Indexed Job which are required for translating the
+// completion index of a pod into the TASK_ID of the array job and back.
+const (
+	annotationArrayBegin = "drmaa2os/array-begin"
+	annotationArrayStep  = "drmaa2os/array-step"
+)
+
+// labelCompletionIndex is set by Kubernetes on each pod of an Indexed Job.
+const labelCompletionIndex = "batch.kubernetes.io/job-completion-index"
+
+// convertArrayJob converts the job template into an Indexed Job which
+// runs a pod for each task of the job array. The TASK_ID environment
+// variable is derived from the completion index of the pod. Since that
+// requires shell arithmetic the container image must provide /bin/sh.
+func convertArrayJob(jobsession, namespace string, jt drmaa2interface.JobTemplate, begin, end, step, maxParallel int) (*batchv1.Job, error) {
+	if step <= 0 {
+		return nil, fmt.Errorf("step must be greater than 0 (is %d)", step)
+	}
+	if begin < 0 || end < begin {
+		return nil, fmt.Errorf("invalid task ID range: begin %d end %d", begin, end)
+	}
+	job, err := convertJob(jobsession, namespace, jt)
+	if err != nil {
+		return nil, err
+	}
+
+	completions := int32((end-begin)/step + 1)
+	parallelism := completions
+	if maxParallel > 0 && int32(maxParallel) < completions {
+		parallelism = int32(maxParallel)
+	}
+	mode := batchv1.IndexedCompletion
+	var zero int32 = 0
+
+	job.Spec.CompletionMode = &mode
+	job.Spec.Completions = &completions
+	job.Spec.Parallelism = &parallelism
+	// a failing task must not fail the other tasks of the job array
+	job.Spec.BackoffLimit = nil
+	job.Spec.BackoffLimitPerIndex = &zero
+
+	if job.Annotations == nil {
+		job.Annotations = make(map[string]string)
+	}
+	job.Annotations[annotationArrayBegin] = strconv.Itoa(begin)
+	job.Annotations[annotationArrayStep] = strconv.Itoa(step)
+	// allows to watch the pods of the tasks of the job session
+	if job.Spec.Template.Labels == nil {
+		job.Spec.Template.Labels = make(map[string]string)
+	}
+	job.Spec.Template.Labels["drmaa2jobsession"] = jobsession
+
+	// the job container is always the first container
+	container := &job.Spec.Template.Spec.Containers[0]
+	container.Args = append([]string{jt.RemoteCommand}, jt.Args...)
+	script := fmt.Sprintf(`export TASK_ID=$((%d + JOB_COMPLETION_INDEX * %d)); `,
+		begin, step)
+	if usesBulkJobIndex(jt) {
+		script += replaceBulkJobIndexScript
+		if strings.Contains(jt.WorkingDirectory, helper.PlaceholderBulkJobIndex) {
+			container.WorkingDir = ""
+			script += fmt.Sprintf(`cd "$(drmaa2_incr %s)" || exit 1; `,
+				shellQuote(jt.WorkingDirectory))
+		}
+	}
+	container.Command = []string{"/bin/sh", "-c", script + `exec "$@"`, "drmaa2os"}
+
+	return job, nil
+}
+
+// replaceBulkJobIndexScript replaces the bulk job index placeholder in
+// the args of the task by the TASK_ID.
+const replaceBulkJobIndexScript = `drmaa2_incr() { s=$1; o=; ` +
+	`while :; do case $s in *'$drmaa_incr_ph$'*) ` +
+	`o=$o${s%%'$drmaa_incr_ph$'*}$TASK_ID; s=${s#*'$drmaa_incr_ph$'};; ` +
+	`*) break;; esac; done; printf '%s' "$o$s"; }; ` +
+	`for arg do shift; set -- "$@" "$(drmaa2_incr "$arg")"; done; `
+
+// usesBulkJobIndex returns true if the bulk job index placeholder is
+// used in the args or the working directory of the job template.
+func usesBulkJobIndex(jt drmaa2interface.JobTemplate) bool {
+	if strings.Contains(jt.WorkingDirectory, helper.PlaceholderBulkJobIndex) {
+		return true
+	}
+	for _, arg := range jt.Args {
+		if strings.Contains(arg, helper.PlaceholderBulkJobIndex) {
+			return true
+		}
+	}
+	return false
+}
+
+// shellQuote quotes a string for the shell.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// isArrayJob returns true if the job was created by AddArrayJob().
+func isArrayJob(job *batchv1.Job) bool {
+	if job.Spec.CompletionMode == nil ||
+		*job.Spec.CompletionMode != batchv1.IndexedCompletion {
+		return false
+	}
+	_, exists := job.Annotations[annotationArrayBegin]
+	return exists
+}
+
+// arrayBeginAndStep returns the first task ID and the increment of the
+// task IDs of an array job.
+func arrayBeginAndStep(job *batchv1.Job) (int, int, error) {
+	if !isArrayJob(job) {
+		return 0, 0, fmt.Errorf("job %s is not an array job", job.Name)
+	}
+	begin, err := strconv.Atoi(job.Annotations[annotationArrayBegin])
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid %s annotation of job %s: %v",
+			annotationArrayBegin, job.Name, err)
+	}
+	step, err := strconv.Atoi(job.Annotations[annotationArrayStep])
+	if err != nil || step <= 0 {
+		return 0, 0, fmt.Errorf("invalid %s annotation of job %s",
+			annotationArrayStep, job.Name)
+	}
+	return begin, step, nil
+}
+
+// arrayTaskIDs returns the IDs of all tasks of an array job.
+func arrayTaskIDs(job *batchv1.Job) ([]string, error) {
+	begin, step, err := arrayBeginAndStep(job)
+	if err != nil {
+		return nil, err
+	}
+	var completions int32
+	if job.Spec.Completions != nil {
+		completions = *job.Spec.Completions
+	}
+	ids := make([]string, 0, completions)
+	for i := 0; i < int(completions); i++ {
+		ids = append(ids, job.Name+arrayTaskSeparator+strconv.Itoa(begin+i*step))
+	}
+	return ids, nil
+}
+
+// splitTaskID splits a task ID of an array job into the job ID and
+// the TASK_ID. isTask is false if the ID is the ID of a job.
+func splitTaskID(id string) (jobID string, taskID int, isTask bool, err error) {
+	pos := strings.LastIndex(id, arrayTaskSeparator)
+	if pos == -1 {
+		return id, 0, false, nil
+	}
+	taskID, err = strconv.Atoi(id[pos+1:])
+	if err != nil {
+		return "", 0, true, fmt.Errorf("invalid task ID %s: %v", id, err)
+	}
+	return id[:pos], taskID, true, nil
+}
+
+// taskIndex translates the TASK_ID into the completion index of the
+// Indexed Job.
+func taskIndex(job *batchv1.Job, taskID int) (int, error) {
+	begin, step, err := arrayBeginAndStep(job)
+	if err != nil {
+		return 0, err
+	}
+	index := (taskID - begin) / step
+	if taskID < begin || (taskID-begin)%step != 0 ||
+		job.Spec.Completions == nil || index >= int(*job.Spec.Completions) {
+		return 0, fmt.Errorf("task %d is not part of array job %s", taskID, job.Name)
+	}
+	return index, nil
+}
+
+// isIndexIn returns true if the index is part of the given list of
+// completion indexes which has the format "1,3-5,7".
+func isIndexIn(indexes string, index int) bool {
+	for _, r := range strings.Split(indexes, ",") {
+		if r == "" {
+			continue
+		}
+		bounds := strings.SplitN(r, "-", 2)
+		first, err := strconv.Atoi(bounds[0])
+		if err != nil {
+			continue
+		}
+		last := first
+		if len(bounds) == 2 {
+			if last, err = strconv.Atoi(bounds[1]); err != nil {
+				continue
+			}
+		}
+		if index >= first && index <= last {
+			return true
+		}
+	}
+	return false
+}
+
+// getPodsForTask returns all pods which were started for the given
+// completion index of an Indexed Job.
+func getPodsForTask(cs kubernetes.Interface, namespace, jobID string, index int) ([]corev1.Pod, error) {
+	selector := fmt.Sprintf("job-name=%s,%s=%d", jobID, labelCompletionIndex, index)
+	podList, err := cs.CoreV1().Pods(namespace).List(context.Background(),
+		metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, fmt.Errorf("could not get pods of task %d of job %s in namespace %s: %v",
+			index, jobID, namespace, err)
+	}
+	if len(podList.Items) == 0 {
+		return nil, fmt.Errorf("no pod for job with label selector %s", selector)
+	}
+	return podList.Items, nil
+}
+
+// latestPod returns the pod which was created last. Multiple pods of a
+// task exist when the pod was restarted.
+func latestPod(pods []corev1.Pod) corev1.Pod {
+	latest := pods[0]
+	for _, pod := range pods[1:] {
+		if pod.CreationTimestamp.After(latest.CreationTimestamp.Time) {
+			latest = pod
+		}
+	}
+	return latest
+}
+
+func convertPodPhase2JobState(phase corev1.PodPhase) drmaa2interface.JobState {
+	switch phase {
+	case corev1.PodPending:
+		return drmaa2interface.Queued
+	case corev1.PodRunning:
+		return drmaa2interface.Running
+	case corev1.PodSucceeded:
+		return drmaa2interface.Done
+	case corev1.PodFailed:
+		return drmaa2interface.Failed
+	}
+	return drmaa2interface.Undetermined
+}
+
+// taskState returns the state of a task of an array job. Finished tasks
+// are taken from the job status as their pods might be removed already.
+// Tasks without pods are waiting for being scheduled (maxParallel) or
+// are on hold when the array job is suspended.
+func taskState(cs kubernetes.Interface, job *batchv1.Job, index int) drmaa2interface.JobState {
+	return taskStateFromPods(job, index, func() ([]corev1.Pod, error) {
+		return getPodsForTask(cs, job.Namespace, job.Name, index)
+	})
+}
+
+// taskStateFromPods derives the state of a task from the job status and
+// (only if required) from the pods of the task returned by getPods.
+func taskStateFromPods(job *batchv1.Job, index int, getPods func() ([]corev1.Pod, error)) drmaa2interface.JobState {
+	if isIndexIn(job.Status.CompletedIndexes, index) {
+		return drmaa2interface.Done
+	}
+	if job.Status.FailedIndexes != nil && isIndexIn(*job.Status.FailedIndexes, index) {
+		return drmaa2interface.Failed
+	}
+	pods, err := getPods()
+	if err != nil {
+		switch convertJob2JobState(job) {
+		case drmaa2interface.Done, drmaa2interface.Failed:
+			// job finished (like deadline reached) before the task ran
+			return drmaa2interface.Failed
+		case drmaa2interface.QueuedHeld:
+			return drmaa2interface.QueuedHeld
+		}
+		return drmaa2interface.Queued
+	}
+	return convertPodPhase2JobState(latestPod(pods).Status.Phase)
+}
+
+// taskJobInfo returns the JobInfo of a task of an array job which is
+// derived from the last pod started for the task.
+func taskJobInfo(cs kubernetes.Interface, job *batchv1.Job, taskID string, index int) drmaa2interface.JobInfo {
+	ji := drmaa2interface.JobInfo{}
+	ji.ID = taskID
+	ji.Slots = 1
+	ji.SubmissionTime = job.CreationTimestamp.Time
+	ji.State = taskState(cs, job, index)
+
+	pods, err := getPodsForTask(cs, job.Namespace, job.Name, index)
+	if err != nil {
+		// task not yet started or pods already removed
+		ji.ExitStatus = exitStatusFromJobState(ji.State)
+		return ji
+	}
+	pod := latestPod(pods)
+	if pod.Spec.NodeName != "" {
+		ji.AllocatedMachines = []string{pod.Spec.NodeName}
+	}
+	if pod.Status.StartTime != nil {
+		ji.DispatchTime = pod.Status.StartTime.Time
+	}
+	ji.SubState = pod.Status.Reason
+	if len(pod.Status.ContainerStatuses) >= 1 &&
+		pod.Status.ContainerStatuses[0].State.Terminated != nil {
+		terminated := pod.Status.ContainerStatuses[0].State.Terminated
+		ji.ExitStatus = int(terminated.ExitCode)
+		ji.TerminatingSignal = fmt.Sprintf("%d", terminated.Signal)
+		ji.FinishTime = terminated.FinishedAt.Time
+		if !ji.DispatchTime.IsZero() {
+			ji.WallclockTime = ji.FinishTime.Sub(ji.DispatchTime)
+		}
+		output, err := GetJobOutput(cs, job.Namespace,
+			job.Spec.Template.Spec.Containers[0].Name, pod.Name)
+		if err == nil {
+			ji.ExtensionList = map[string]string{
+				extension.JobInfoK8sJSessionJobOutput: string(output),
+			}
+		}
+	}
+	return ji
+}
+
+// getTask returns the job of the given task ID and the completion index
+// of the task.
+func getTask(cs kubernetes.Interface, namespace, id string) (*batchv1.Job, int, error) {
+	jobID, taskID, _, err := splitTaskID(id)
+	if err != nil {
+		return nil, 0, err
+	}
+	job, err := cs.BatchV1().Jobs(namespace).Get(context.Background(), jobID,
+		metav1.GetOptions{})
+	if err != nil {
+		return nil, 0, fmt.Errorf("can't find array job %s: %v", jobID, err)
+	}
+	index, err := taskIndex(job, taskID)
+	if err != nil {
+		return nil, 0, err
+	}
+	return job, index, nil
+}
+
+var errTaskJobControl = errors.New("job control of single tasks of an array job is not supported")
@@ -0,0 +1,262 @@
+package kubernetestracker
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/dgruber/drmaa2interface"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+var _ = Describe("Array jobs", func() {
+
+	var jt drmaa2interface.JobTemplate
+
+	BeforeEach(func() {
+		jt = drmaa2interface.JobTemplate{
+			JobName:       "array",
+			RemoteCommand: "/bin/echo",
+			Args:          []string{"hello"},
+			JobCategory:   "busybox:latest",
+		}
+	})
+
+	taskPod := func(name string, index string, phase corev1.PodPhase) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels: map[string]string{
+					"job-name":           "array",
+					labelCompletionIndex: index,
+				},
+			},
+			Spec: corev1.PodSpec{NodeName: "node1"},
+			Status: corev1.PodStatus{
+				Phase: phase,
+			},
+		}
+	}
+
+	Context("conversion", func() {
+
+		It("should convert the job template into an Indexed Job", func() {
+			job, err := convertArrayJob("session", "default", jt, 1, 10, 2, 3)
+			Ω(err).Should(BeNil())
+			Ω(*job.Spec.CompletionMode).Should(Equal(batchv1.IndexedCompletion))
+			Ω(*job.Spec.Completions).Should(BeNumerically("==", 5))
+			Ω(*job.Spec.Parallelism).Should(BeNumerically("==", 3))
+			Ω(*job.Spec.BackoffLimitPerIndex).Should(BeNumerically("==", 0))
+			Ω(job.Spec.BackoffLimit).Should(BeNil())
+			Ω(job.Annotations[annotationArrayBegin]).Should(Equal("1"))
+			Ω(job.Annotations[annotationArrayStep]).Should(Equal("2"))
+			Ω(job.Labels["drmaa2jobsession"]).Should(Equal("session"))
+			Ω(job.Spec.Template.Labels["drmaa2jobsession"]).Should(Equal("session"))
+
+			c := job.Spec.Template.Spec.Containers[0]
+			Ω(c.Command).Should(Equal([]string{"/bin/sh", "-c",
+				`export TASK_ID=$((1 + JOB_COMPLETION_INDEX * 2)); exec "$@"`,
+				"drmaa2os"}))
+			Ω(c.Args).Should(Equal([]string{"/bin/echo", "hello"}))
+		})
+
+		It("should run all tasks in parallel when maxParallel is not set", func() {
+			job, err := convertArrayJob("session", "default", jt, 1, 10, 1, 0)
+			Ω(err).Should(BeNil())
+			Ω(*job.Spec.Completions).Should(BeNumerically("==", 10))
+			Ω(*job.Spec.Parallelism).Should(BeNumerically("==", 10))
+
+			job, err = convertArrayJob("session", "default", jt, 1, 10, 1, 20)
+			Ω(err).Should(BeNil())
+			Ω(*job.Spec.Parallelism).Should(BeNumerically("==", 10))
+		})
+
+		It("should replace the bulk job index placeholder when the task runs", func() {
+			dir, err := os.MkdirTemp("", "k8sarray")
+			Ω(err).Should(BeNil())
+			defer os.RemoveAll(dir)
+			Ω(os.Mkdir(filepath.Join(dir, "task5"), 0755)).Should(BeNil())
+
+			jt.RemoteCommand = "/bin/sh"
+			jt.Args = []string{"-c", `echo "$0 $1" > out`,
+				"in.$drmaa_incr_ph$", "it's $drmaa_incr_ph$$drmaa_incr_ph$"}
+			jt.WorkingDirectory = filepath.Join(dir, "task$drmaa_incr_ph$")
+			job, err := convertArrayJob("session", "default", jt, 1, 10, 2, 0)
+			Ω(err).Should(BeNil())
+			c := job.Spec.Template.Spec.Containers[0]
+			Ω(c.WorkingDir).Should(BeEmpty())
+
+			cmd := exec.Command(c.Command[0], append(c.Command[1:], c.Args...)...)
+			cmd.Env = append(os.Environ(), "JOB_COMPLETION_INDEX=2")
+			out, err := cmd.CombinedOutput()
+			Ω(err).Should(BeNil(), string(out))
+			content, err := os.ReadFile(filepath.Join(dir, "task5", "out"))
+			Ω(err).Should(BeNil())
+			Ω(string(content)).Should(Equal("in.5 it's 55\n"))
+		})
+
+		It("should reject invalid task ranges", func() {
+			_, err := convertArrayJob("session", "default", jt, 1, 10, 0, 0)
+			Ω(err).ShouldNot(BeNil())
+			_, err = convertArrayJob("session", "default", jt, 10, 1, 1, 0)
+			Ω(err).ShouldNot(BeNil())
+		})
+
+	})
+
+	Context("task IDs", func() {
+
+		It("should list the task IDs of an array job", func() {
+			job, err := convertArrayJob("session", "default", jt, 2, 8, 3, 0)
+			Ω(err).Should(BeNil())
+			ids, err := arrayTaskIDs(job)
+			Ω(err).Should(BeNil())
+			Ω(ids).Should(Equal([]string{"array:2", "array:5", "array:8"}))
+		})
+
+		It("should not list tasks of a job which is not an array job", func() {
+			job, err := convertJob("session", "default", jt)
+			Ω(err).Should(BeNil())
+			_, err = arrayTaskIDs(job)
+			Ω(err).ShouldNot(BeNil())
+		})
+
+		It("should split task IDs", func() {
+			jobID, taskID, isTask, err := splitTaskID("array:5")
+			Ω(err).Should(BeNil())
+			Ω(isTask).Should(BeTrue())
+			Ω(jobID).Should(Equal("array"))
+			Ω(taskID).Should(Equal(5))
+
+			jobID, _, isTask, err = splitTaskID("job")
+			Ω(err).Should(BeNil())
+			Ω(isTask).Should(BeFalse())
+			Ω(jobID).Should(Equal("job"))
+
+			_, _, _, err = splitTaskID("array:x")
+			Ω(err).ShouldNot(BeNil())
+		})
+
+		It("should translate task IDs into completion indexes", func() {
+			job, err := convertArrayJob("session", "default", jt, 2, 8, 3, 0)
+			Ω(err).Should(BeNil())
+			index, err := taskIndex(job, 2)
+			Ω(err).Should(BeNil())
+			Ω(index).Should(Equal(0))
+			index, err = taskIndex(job, 8)
+			Ω(err).Should(BeNil())
+			Ω(index).Should(Equal(2))
+			_, err = taskIndex(job, 3)
+			Ω(err).ShouldNot(BeNil())
+			_, err = taskIndex(job, 11)
+			Ω(err).ShouldNot(BeNil())
+			_, err = taskIndex(job, 1)
+			Ω(err).ShouldNot(BeNil())
+		})
+
+		It("should find indexes in completed index lists", func() {
+			Ω(isIndexIn("1,3-5,7", 1)).Should(BeTrue())
+			Ω(isIndexIn("1,3-5,7", 4)).Should(BeTrue())
+			Ω(isIndexIn("1,3-5,7", 7)).Should(BeTrue())
+			Ω(isIndexIn("1,3-5,7", 2)).Should(BeFalse())
+			Ω(isIndexIn("1,3-5,7", 6)).Should(BeFalse())
+			Ω(isIndexIn("", 0)).Should(BeFalse())
+		})
+
+	})
+
+	Context("task states", func() {
+
+		var job *batchv1.Job
+
+		BeforeEach(func() {
+			var err error
+			job, err = convertArrayJob("session", "default", jt, 1, 5, 1, 2)
+			Ω(err).Should(BeNil())
+			failed := "1"
+			job.Status = batchv1.JobStatus{
+				Active:           1,
+				CompletedIndexes: "0",
+				FailedIndexes:    &failed,
+			}
+		})
+
+		It("should resolve the states of the tasks", func() {
+			cs := fake.NewSimpleClientset(
+				taskPod("array-0-abc", "0", corev1.PodSucceeded),
+				taskPod("array-1-abc", "1", corev1.PodFailed),
+				taskPod("array-2-abc", "2", corev1.PodRunning),
+				taskPod("array-3-abc", "3", corev1.PodPending),
+			)
+			Ω(taskState(cs, job, 0)).Should(Equal(drmaa2interface.Done))
+			Ω(taskState(cs, job, 1)).Should(Equal(drmaa2interface.Failed))
+			Ω(taskState(cs, job, 2)).Should(Equal(drmaa2interface.Running))
+			Ω(taskState(cs, job, 3)).Should(Equal(drmaa2interface.Queued))
+			// no pod created yet due to maxParallel
+			Ω(taskState(cs, job, 4)).Should(Equal(drmaa2interface.Queued))
+		})
+
+		It("should report tasks without pods of a held job as held", func() {
+			suspend := true
+			job.Spec.Suspend = &suspend
+			cs := fake.NewSimpleClientset()
+			Ω(taskState(cs, job, 4)).Should(Equal(drmaa2interface.QueuedHeld))
+			Ω(taskState(cs, job, 0)).Should(Equal(drmaa2interface.Done))
+		})
+
+		It("should report tasks without pods of a finished job as failed", func() {
+			job.Status = batchv1.JobStatus{
+				Failed: 1,
+				Conditions: []batchv1.JobCondition{
+					{Type: batchv1.JobFailed, Status: corev1.ConditionTrue},
+				},
+			}
+			cs := fake.NewSimpleClientset()
+			Ω(taskState(cs, job, 4)).Should(Equal(drmaa2interface.Failed))
+		})
+
+		It("should create the job info of a task out of its pod", func() {
+			pod := taskPod("array-2-abc", "2", corev1.PodFailed)
+			pod.Status.ContainerStatuses = []corev1.ContainerStatus{
+				{
+					Name: "array",
+					State: corev1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{
+							ExitCode:   3,
+							FinishedAt: metav1.Now(),
+						},
+					},
+				},
+			}
+			cs := fake.NewSimpleClientset(pod)
+			ji := taskJobInfo(cs, job, "array:3", 2)
+			Ω(ji.ID).Should(Equal("array:3"))
+			Ω(ji.State).Should(Equal(drmaa2interface.Failed))
+			Ω(ji.ExitStatus).Should(Equal(3))
+			Ω(ji.AllocatedMachines).Should(Equal([]string{"node1"}))
+		})
+
+		It("should find the job and index of a task ID", func() {
+			job.Namespace = "default"
+			cs := fake.NewSimpleClientset(job)
+			j, index, err := getTask(cs, "default", "array:4")
+			Ω(err).Should(BeNil())
+			Ω(j.Name).Should(Equal("array"))
+			Ω(index).Should(Equal(3))
+
+			_, _, err = getTask(cs, "default", "array:6")
+			Ω(err).ShouldNot(BeNil())
+			_, _, err = getTask(cs, "default", "unknown:1")
+			Ω(err).ShouldNot(BeNil())
+		})
+
+	})
+
+})
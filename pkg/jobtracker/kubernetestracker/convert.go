@@ -564,6 +564,11 @@ func convertJob(jobsession, namespace string, jt drmaa2interface.JobTemplate) (*
 		},
 	}
 
+	if jt.SubmitAsHold {
+		suspend := true
+		job.Spec.Suspend = &suspend
+	}
+
 	dl, err := deadlineTime(jt)
 	if err != nil {
 		return nil, err
@@ -65,6 +65,19 @@ var _ = Describe("Convert", func() {
 			Ω(*job.Spec.Completions).Should(BeNumerically("==", 1))
 		})
 
+		It("should create a suspended job when SubmitAsHold is set", func() {
+			job, err := convertJob("jobsession", "default", jt)
+			Ω(err).Should(BeNil())
+			Ω(job.Spec.Suspend).Should(BeNil())
+
+			jt.SubmitAsHold = true
+			job, err = convertJob("jobsession", "default", jt)
+			Ω(err).Should(BeNil())
+			Ω(job.Spec.Suspend).ShouldNot(BeNil())
+			Ω(*job.Spec.Suspend).Should(BeTrue())
+			Ω(convertJob2JobState(job)).Should(Equal(drmaa2interface.QueuedHeld))
+		})
+
 		It("should convert the deadline from time to int", func() {
 			jt.DeadlineTime = time.Now().Add(time.Second * 10)
 			deadline, err := deadlineTime(jt)
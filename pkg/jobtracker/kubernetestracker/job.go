@@ -5,13 +5,15 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/dgruber/drmaa2interface"
 	batchv1 "k8s.io/api/batch/v1"
 	k8sapi "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 	clientBatchv1 "k8s.io/client-go/kubernetes/typed/batch/v1"
 )
 
-func getJobInterfaceAndJob(kt *kubernetes.Clientset, jobid, namespace string) (clientBatchv1.JobInterface, *batchv1.Job, error) {
+func getJobInterfaceAndJob(kt kubernetes.Interface, jobid, namespace string) (clientBatchv1.JobInterface, *batchv1.Job, error) {
 	if kt == nil {
 		return nil, nil, errors.New("no clientset")
 	}
@@ -36,9 +38,9 @@ func jobStateChange(jc clientBatchv1.JobInterface, job *batchv1.Job, action stri
 	case "resume":
 		return errors.New("Unsupported Operation")
 	case "hold":
-		return errors.New("Unsupported Operation")
+		return setSuspend(jc, job, true)
 	case "release":
-		return errors.New("Unsupported Operation")
+		return setSuspend(jc, job, false)
 	case "terminate":
 		// activeDeadlineSeconds to zero
 		return deleteJob(jc, job)
@@ -46,6 +48,28 @@ func jobStateChange(jc clientBatchv1.JobInterface, job *batchv1.Job, action stri
 	return fmt.Errorf("Undefined job operation")
 }
 
+// setSuspend puts a job on hold or releases it by setting spec.suspend.
+// Pods of a running job are terminated by Kubernetes when the job is
+// put on hold. They are started again when the job is released.
+func setSuspend(jc clientBatchv1.JobInterface, job *batchv1.Job, suspend bool) error {
+	switch convertJob2JobState(job) {
+	case drmaa2interface.Done, drmaa2interface.Failed:
+		return fmt.Errorf("job %s is already finished", job.GetName())
+	case drmaa2interface.QueuedHeld:
+		if suspend {
+			return nil
+		}
+	default:
+		if !suspend {
+			return fmt.Errorf("job %s is not on hold", job.GetName())
+		}
+	}
+	patch := fmt.Sprintf(`{"spec":{"suspend":%t}}`, suspend)
+	_, err := jc.Patch(context.Background(), job.GetName(), types.MergePatchType,
+		[]byte(patch), k8sapi.PatchOptions{})
+	return err
+}
+
 func deleteJob(jc clientBatchv1.JobInterface, job *batchv1.Job) error {
 	if jc == nil || job == nil {
 		return errors.New("internal error: can't delete job: job is nil")
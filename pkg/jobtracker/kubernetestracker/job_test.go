@@ -4,7 +4,10 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	"github.com/dgruber/drmaa2interface"
 	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/kubernetes/typed/batch/v1/fake"
 )
 
@@ -36,12 +39,6 @@ var _ = Describe("Job", func() {
 			err = jobStateChange(fakeJobInterface.Jobs("default"),
 				&fakeJob, "resume")
 			Ω(err).ShouldNot(BeNil())
-			err = jobStateChange(fakeJobInterface.Jobs("default"),
-				&fakeJob, "hold")
-			Ω(err).ShouldNot(BeNil())
-			err = jobStateChange(fakeJobInterface.Jobs("default"),
-				&fakeJob, "release")
-			Ω(err).ShouldNot(BeNil())
 		})
 
 		It("should error when job is not found", func() {
@@ -53,6 +50,58 @@ var _ = Describe("Job", func() {
 
 	})
 
+	Context("Hold and release", func() {
+
+		var cs *k8sfake.Clientset
+		var job *batchv1.Job
+
+		BeforeEach(func() {
+			job = &batchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "job",
+					Namespace: "default",
+				},
+			}
+			cs = k8sfake.NewSimpleClientset(job)
+		})
+
+		getJob := func() *batchv1.Job {
+			j, err := getJobByID(cs.BatchV1().Jobs("default"), "job")
+			Ω(err).Should(BeNil())
+			return j
+		}
+
+		It("should put a job on hold and release it", func() {
+			err := jobStateChange(cs.BatchV1().Jobs("default"), job, "hold")
+			Ω(err).Should(BeNil())
+			held := getJob()
+			Ω(*held.Spec.Suspend).Should(BeTrue())
+			Ω(convertJob2JobState(held)).Should(Equal(drmaa2interface.QueuedHeld))
+
+			// holding a held job again is accepted
+			err = jobStateChange(cs.BatchV1().Jobs("default"), held, "hold")
+			Ω(err).Should(BeNil())
+
+			err = jobStateChange(cs.BatchV1().Jobs("default"), held, "release")
+			Ω(err).Should(BeNil())
+			released := getJob()
+			Ω(*released.Spec.Suspend).Should(BeFalse())
+			Ω(convertJob2JobState(released)).ShouldNot(Equal(drmaa2interface.QueuedHeld))
+		})
+
+		It("should error when releasing a job which is not on hold", func() {
+			err := jobStateChange(cs.BatchV1().Jobs("default"), job, "release")
+			Ω(err).ShouldNot(BeNil())
+		})
+
+		It("should error when putting a finished job on hold", func() {
+			job.Status.Succeeded = 1
+			err := jobStateChange(cs.BatchV1().Jobs("default"), job, "hold")
+			Ω(err).ShouldNot(BeNil())
+		})
+
+	})
+
 	Context("Standard error cases", func() {
 		It("should error when given job is nil", func() {
 			err := deleteJob(nil, nil)
@@ -70,6 +70,6 @@ func homeDir() string {
 	return os.Getenv("USERPROFILE")
 }
 
-func getJobsClient(cs *kubernetes.Clientset, namespace string) (batchv1.JobInterface, error) {
+func getJobsClient(cs kubernetes.Interface, namespace string) (batchv1.JobInterface, error) {
 	return cs.BatchV1().Jobs(namespace), nil
 }
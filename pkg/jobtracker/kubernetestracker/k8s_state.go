@@ -28,7 +28,6 @@ func convertJobStatus2JobState(status *v1.JobStatus) drmaa2interface.JobState {
 			return drmaa2interface.Done
 		}
 	}
-	// TODO: check for suspended state
 	// From Kubernetes code base:
 	// "The latest available observations of an object's current state. When a Job
 	// fails, one of the conditions will have type "Failed" and status true. When
@@ -36,6 +35,11 @@ func convertJobStatus2JobState(status *v1.JobStatus) drmaa2interface.JobState {
 	// status true; when the Job is resumed, the status of this condition will
 	// become false. When a Job is completed, one of the conditions will have
 	// type "Complete" and status true."
+	for _, condition := range status.Conditions {
+		if condition.Type == v1.JobSuspended && condition.Status == corev1.ConditionTrue {
+			return drmaa2interface.QueuedHeld
+		}
+	}
 	if status.Succeeded >= 1 {
 		return drmaa2interface.Done
 	}
@@ -55,12 +59,26 @@ func convertJobStatus2JobState(status *v1.JobStatus) drmaa2interface.JobState {
 	return drmaa2interface.Undetermined
 }
 
+// convertJob2JobState returns the state of the job. A job which is not
+// finished and has spec.suspend set is on hold, even before Kubernetes
+// has added the "Suspended" condition to the job status.
+func convertJob2JobState(job *v1.Job) drmaa2interface.JobState {
+	state := convertJobStatus2JobState(&job.Status)
+	if job.Spec.Suspend != nil && *job.Spec.Suspend {
+		if state == drmaa2interface.Done || state == drmaa2interface.Failed {
+			return state
+		}
+		return drmaa2interface.QueuedHeld
+	}
+	return state
+}
+
 func DRMAA2State(jc batchv1.JobInterface, jobid string) drmaa2interface.JobState {
 	job, err := getJobByID(jc, jobid)
 	if err != nil {
 		return drmaa2interface.Undetermined
 	}
-	return convertJobStatus2JobState(&job.Status)
+	return convertJob2JobState(job)
 }
 
 func exitStatusFromJobState(status drmaa2interface.JobState) int {
@@ -89,7 +107,7 @@ func JobToJobInfo(jc batchv1.JobInterface, jobid string) (drmaa2interface.JobInf
 		ji.FinishTime = job.Status.CompletionTime.Time
 		ji.WallclockTime = ji.FinishTime.Sub(ji.DispatchTime)
 	}
-	ji.State = convertJobStatus2JobState(&job.Status)
+	ji.State = convertJob2JobState(job)
 	ji.ID = jobid
 	ji.ExitStatus = exitStatusFromJobState(ji.State)
 	ji.AllocatedMachines = []string{job.Spec.Template.Spec.NodeName}
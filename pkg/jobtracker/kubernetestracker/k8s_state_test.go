@@ -62,6 +62,27 @@ var _ = Describe("K8sState", func() {
 			Ω(convertJobStatus2JobState(&status)).Should(Equal(drmaa2interface.Done))
 		})
 
+		It("should convert suspended to QueuedHeld state", func() {
+			status.Conditions = []batchv1.JobCondition{
+				{Type: batchv1.JobSuspended, Status: corev1.ConditionTrue},
+			}
+			Ω(convertJobStatus2JobState(&status)).Should(Equal(drmaa2interface.QueuedHeld))
+			status.Conditions[0].Status = corev1.ConditionFalse
+			status.Active = 1
+			Ω(convertJobStatus2JobState(&status)).Should(Equal(drmaa2interface.Running))
+		})
+
+		It("should convert a job with spec.suspend set to QueuedHeld state", func() {
+			suspend := true
+			job := batchv1.Job{
+				Spec:   batchv1.JobSpec{Suspend: &suspend},
+				Status: status,
+			}
+			Ω(convertJob2JobState(&job)).Should(Equal(drmaa2interface.QueuedHeld))
+			job.Status.Succeeded = 1
+			Ω(convertJob2JobState(&job)).Should(Equal(drmaa2interface.Done))
+		})
+
 		It("should convert unset states to Undetermined state", func() {
 			var s batchv1.JobStatus
 			Ω(convertJobStatus2JobState(&s)).Should(Equal(drmaa2interface.Undetermined))
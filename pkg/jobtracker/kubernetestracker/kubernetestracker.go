@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/dgruber/drmaa2interface"
@@ -11,6 +13,7 @@ import (
 	"github.com/dgruber/drmaa2os/pkg/extension"
 	"github.com/dgruber/drmaa2os/pkg/helper"
 	"github.com/dgruber/drmaa2os/pkg/jobtracker"
+	batchv1 "k8s.io/api/batch/v1"
 	k8sapi "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 )
@@ -19,9 +22,12 @@ const K8S_JT_EXTENSION_NAMESPACE = "namespace"
 const K8S_JT_EXTENSION_LABELS = "labels"
 
 type KubernetesTracker struct {
-	clientSet  *kubernetes.Clientset
+	clientSet  kubernetes.Interface
 	jobsession string
 	namespace  string
+	// watcher is created with the first Wait() call
+	watcherMutex sync.Mutex
+	watcher      *jobWatcher
 }
 
 // init registers the Kubernetes job tracker at the SessionManager
@@ -35,6 +41,47 @@ func NewAllocator() *allocator {
 	return &allocator{}
 }
 
+// DrmsName returns "kubernetes".
+func (a *allocator) DrmsName() string {
+	return "kubernetes"
+}
+
+// DrmsVersion returns the version of the Kubernetes cluster which is
+// configured in the .kube/config file (or the cluster the process
+// runs in).
+func (a *allocator) DrmsVersion() (drmaa2interface.Version, error) {
+	cs, err := NewClientSet()
+	if err != nil {
+		return drmaa2interface.Version{}, err
+	}
+	version, err := cs.Discovery().ServerVersion()
+	if err != nil {
+		return drmaa2interface.Version{}, err
+	}
+	return drmaa2interface.Version{Major: version.Major, Minor: version.Minor}, nil
+}
+
+// Supports returns true for file staging (StageInFiles are
+// mounted as secrets, config maps, or host paths) and for
+// limiting the parallel tasks of job arrays.
+func (a *allocator) Supports(capability drmaa2interface.Capability) bool {
+	switch capability {
+	case drmaa2interface.JtStaging, drmaa2interface.BulkJobsMaxParallel:
+		return true
+	}
+	return false
+}
+
+// SupportsJobControl returns true for terminate, hold, and release.
+func (a *allocator) SupportsJobControl(action string) bool {
+	switch action {
+	case jobtracker.JobControlTerminate, jobtracker.JobControlHold,
+		jobtracker.JobControlRelease:
+		return true
+	}
+	return false
+}
+
 // KubernetesTrackerParameters can be used as parameter in
 // NewKubernetesSessionManager. Note, that the namespace
 // if set must exist. If not set the "default" namespace
@@ -73,6 +120,10 @@ func New(jobsession string, namespace string, cs *kubernetes.Clientset) (*Kubern
 			return nil, err
 		}
 	}
+	return newKubernetesTracker(jobsession, namespace, cs), nil
+}
+
+func newKubernetesTracker(jobsession string, namespace string, cs kubernetes.Interface) *KubernetesTracker {
 	if namespace == "" {
 		namespace = "default"
 	}
@@ -80,7 +131,7 @@ func New(jobsession string, namespace string, cs *kubernetes.Clientset) (*Kubern
 		clientSet:  cs,
 		jobsession: jobsession,
 		namespace:  namespace,
-	}, nil
+	}
 }
 
 // ListJobCategories returns all container images which are currently
@@ -122,8 +173,18 @@ func (kt *KubernetesTracker) ListJobs() ([]string, error) {
 }
 
 // AddJob converts the given DRMAA2 job template into a batchv1.Job and creates
-// the job within Kubernetes.
+// the job within Kubernetes. The DRMAA placeholders are replaced.
 func (kt *KubernetesTracker) AddJob(jt drmaa2interface.JobTemplate) (string, error) {
+	return kt.createJob(jt, func(jt drmaa2interface.JobTemplate) (*batchv1.Job, error) {
+		jt = helper.ReplacePlaceholders(jt, 0, false, helper.ContainerHomeDirectory(jt))
+		return convertJob(kt.jobsession, kt.namespace, jt)
+	})
+}
+
+// createJob creates the objects required by the job template (secrets,
+// configmaps, PVCs) and the job which is created out of the job template
+// by the given convert function.
+func (kt *KubernetesTracker) createJob(jt drmaa2interface.JobTemplate, convert func(drmaa2interface.JobTemplate) (*batchv1.Job, error)) (string, error) {
 	// unique job name is required for secrets, configmap names and pod
 	if jt.JobName == "" {
 		jt.JobName = fmt.Sprintf("d2-%d", time.Now().UnixNano())
@@ -167,7 +228,7 @@ func (kt *KubernetesTracker) AddJob(jt drmaa2interface.JobTemplate) (string, err
 		}
 	}
 
-	job, err := convertJob(kt.jobsession, kt.namespace, jt)
+	job, err := convert(jt)
 	if err != nil {
 		removeArtifacts(kt.clientSet, jt, kt.namespace)
 		return "", fmt.Errorf("converting job template into a k8s job: %s", err.Error())
@@ -190,15 +251,43 @@ func (kt *KubernetesTracker) AddJob(jt drmaa2interface.JobTemplate) (string, err
 	return string(j.Name), err
 }
 
+// AddArrayJob creates one Indexed Job which runs a pod for each task of the
+// job array. At most maxParallel pods of the job run at the same time (if
+// maxParallel is > 0). The TASK_ID environment variable of a pod is derived
+// from its completion index. The returned array job ID is the job name.
+// The bulk job index placeholder is replaced by the TASK_ID in the args
+// when the pod is running.
 func (kt *KubernetesTracker) AddArrayJob(jt drmaa2interface.JobTemplate, begin int, end int, step int, maxParallel int) (string, error) {
-	return helper.AddArrayJobAsSingleJobs(jt, kt, begin, end, step)
+	return kt.createJob(jt, func(jt drmaa2interface.JobTemplate) (*batchv1.Job, error) {
+		jt = helper.ReplaceDirectoryPlaceholders(jt, helper.ContainerHomeDirectory(jt))
+		return convertArrayJob(kt.jobsession, kt.namespace, jt, begin, end, step, maxParallel)
+	})
 }
 
+// ListArrayJobs returns the IDs of all tasks of the given array job in
+// the format "<job name>:<task ID>".
 func (kt *KubernetesTracker) ListArrayJobs(id string) ([]string, error) {
-	return helper.ArrayJobID2GUIDs(id)
+	if strings.HasPrefix(id, "[") {
+		// array job which was submitted as single jobs by older versions
+		return helper.ArrayJobID2GUIDs(id)
+	}
+	_, job, err := getJobInterfaceAndJob(kt.clientSet, id, kt.namespace)
+	if err != nil {
+		return nil, fmt.Errorf("ListArrayJobs: %w", err)
+	}
+	return arrayTaskIDs(job)
 }
 
+// JobState returns the state of a job or a task of an array job. The state
+// of a task is derived from the job status and the pods of the task.
 func (kt *KubernetesTracker) JobState(jobID string) (drmaa2interface.JobState, string, error) {
+	if _, _, isTask, _ := splitTaskID(jobID); isTask {
+		job, index, err := getTask(kt.clientSet, kt.namespace, jobID)
+		if err != nil {
+			return drmaa2interface.Undetermined, "", nil
+		}
+		return taskState(kt.clientSet, job, index), "", nil
+	}
 	jc, err := getJobsClient(kt.clientSet, kt.namespace)
 	if err != nil {
 		return drmaa2interface.Undetermined, "", nil
@@ -207,6 +296,13 @@ func (kt *KubernetesTracker) JobState(jobID string) (drmaa2interface.JobState, s
 }
 
 func (kt *KubernetesTracker) JobInfo(jobID string) (drmaa2interface.JobInfo, error) {
+	if _, _, isTask, _ := splitTaskID(jobID); isTask {
+		job, index, err := getTask(kt.clientSet, kt.namespace, jobID)
+		if err != nil {
+			return drmaa2interface.JobInfo{}, err
+		}
+		return taskJobInfo(kt.clientSet, job, jobID, index), nil
+	}
 	jc, err := getJobsClient(kt.clientSet, kt.namespace)
 	if err != nil {
 		return drmaa2interface.JobInfo{}, err
@@ -273,6 +369,10 @@ func (kt *KubernetesTracker) JobInfo(jobID string) (drmaa2interface.JobInfo, err
 // JobControl changes the state of the given job by execution the given action
 // (suspend, resume, hold, release, terminate).
 func (kt *KubernetesTracker) JobControl(jobid, state string) error {
+	if _, _, isTask, _ := splitTaskID(jobid); isTask {
+		return fmt.Errorf("JobControl failed for jobID %s and action %s: %w",
+			jobid, state, errTaskJobControl)
+	}
 	jc, job, err := getJobInterfaceAndJob(kt.clientSet, jobid, kt.namespace)
 	if err != nil {
 		return fmt.Errorf("JobControl failed for jobID %s and action %s: %w",
@@ -282,14 +382,66 @@ func (kt *KubernetesTracker) JobControl(jobid, state string) error {
 }
 
 // Wait returns when the job is in one of the given states or when a timeout
-// occurs (errors then).
+// occurs (errors then). Instead of polling the job state, the jobs of the
+// job session are watched by an informer which is shared by all waiters.
+// If the jobs can't be watched (yet) the job state is polled.
 func (kt *KubernetesTracker) Wait(jobid string, timeout time.Duration, states ...drmaa2interface.JobState) error {
-	return helper.WaitForState(kt, jobid, timeout, states...)
+	jobName, _, _, err := splitTaskID(jobid)
+	if err != nil {
+		return err
+	}
+	start := time.Now()
+	w, err := kt.getWatcher()
+	if err != nil || !w.waitForSync(timeout) {
+		remaining := timeout - time.Since(start)
+		if remaining < 0 {
+			remaining = drmaa2interface.ZeroTime
+		}
+		return helper.WaitForState(kt, jobid, remaining, states...)
+	}
+	changed, unsubscribe := w.subscribe(jobName)
+	defer unsubscribe()
+
+	timer := time.NewTimer(timeout - time.Since(start))
+	defer timer.Stop()
+
+	for {
+		if helper.IsInExpectedState(kt.watchedJobState(w, jobid), states...) {
+			return nil
+		}
+		select {
+		case <-changed:
+		case <-timer.C:
+			if helper.IsInExpectedState(kt.watchedJobState(w, jobid), states...) {
+				return nil
+			}
+			return errors.New("timeout while waiting for job state")
+		}
+	}
+}
+
+// Close stops watching the jobs of the job session.
+func (kt *KubernetesTracker) Close() error {
+	kt.watcherMutex.Lock()
+	defer kt.watcherMutex.Unlock()
+	if kt.watcher != nil {
+		kt.watcher.stop()
+		kt.watcher = nil
+	}
+	return nil
 }
 
 // DeleteJob removes a finished job and the objects created along
-// with the job (like configmaps and secrets) Kubernetes.
+// with the job (like configmaps and secrets) Kubernetes. Tasks of
+// array jobs are removed along with the array job.
 func (kt *KubernetesTracker) DeleteJob(jobid string) error {
+	if _, _, isTask, _ := splitTaskID(jobid); isTask {
+		state, _, _ := kt.JobState(jobid)
+		if state != drmaa2interface.Done && state != drmaa2interface.Failed {
+			return fmt.Errorf("DeleteJob error: task %s is not finished", jobid)
+		}
+		return nil
+	}
 	jc, job, err := getJobInterfaceAndJob(kt.clientSet, jobid, kt.namespace)
 	if err != nil {
 		return fmt.Errorf("DeleteJob error: %w", err)
@@ -110,7 +110,7 @@ func getJobStageInPVCs(jt drmaa2interface.JobTemplate) ([]*v1.PersistentVolumeCl
 }
 
 // removeArtifacts deletes all created secrets and configmaps
-func removeArtifacts(cs *kubernetes.Clientset, jt drmaa2interface.JobTemplate, namespace string) error {
+func removeArtifacts(cs kubernetes.Interface, jt drmaa2interface.JobTemplate, namespace string) error {
 	if jt.StageInFiles == nil {
 		return nil
 	}
@@ -146,7 +146,7 @@ func removeArtifacts(cs *kubernetes.Clientset, jt drmaa2interface.JobTemplate, n
 // - secrets (stagein)
 // - configmaps (stagein)
 // - job template configmap
-func removeArtifactsByJobID(cs *kubernetes.Clientset, jobID, namespace string) error {
+func removeArtifactsByJobID(cs kubernetes.Interface, jobID, namespace string) error {
 	// list secrets and delete those which match the label and jobID
 	secretList, err := cs.CoreV1().Secrets(namespace).List(context.Background(),
 		metav1.ListOptions{})
@@ -193,7 +193,7 @@ func removeArtifactsByJobID(cs *kubernetes.Clientset, jobID, namespace string) e
 	return err
 }
 
-func storeJobTemplateInConfigMap(cs *kubernetes.Clientset, jt drmaa2interface.JobTemplate, namespace string) error {
+func storeJobTemplateInConfigMap(cs kubernetes.Interface, jt drmaa2interface.JobTemplate, namespace string) error {
 	// remove content of secrets
 	for _, v := range jt.StageInFiles {
 		if strings.HasPrefix(v, "secret-data") {
@@ -214,7 +214,7 @@ func storeJobTemplateInConfigMap(cs *kubernetes.Clientset, jt drmaa2interface.Jo
 	return err
 }
 
-func getJobTemplateFromConfigMap(cs *kubernetes.Clientset, jobID, namespace string) (*drmaa2interface.JobTemplate, error) {
+func getJobTemplateFromConfigMap(cs kubernetes.Interface, jobID, namespace string) (*drmaa2interface.JobTemplate, error) {
 	cm, err := cs.CoreV1().ConfigMaps(namespace).Get(context.Background(), jobID+"-jobtemplate-configmap", k8sapi.GetOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("could not find configmap %s: %w", jobID+"-jobtemplate-configmap", err)
@@ -0,0 +1,292 @@
+package kubernetestracker
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/dgruber/drmaa2interface"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	batchlisters "k8s.io/client-go/listers/batch/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// watchResyncPeriod is the interval in which all cached jobs are passed
+// to the event handler again. That wakes up waiters on tasks of array
+// jobs whose pod states might have changed without a job status change.
+// The resync does not access the API server.
+const watchResyncPeriod = 10 * time.Second
+
+// watchSyncTimeout is the max. time to wait for the initial list of the
+// jobs and pods. When it is exceeded (like when watching jobs is not
+// permitted) Wait() falls back to polling.
+var watchSyncTimeout = 30 * time.Second
+
+// watchRetryInterval is the time after which a failed watcher is
+// started again. Until then Wait() polls the job state.
+var watchRetryInterval = 5 * time.Minute
+
+// errWatchFailed is returned when the jobs of the job session can't be
+// watched.
+var errWatchFailed = errors.New("failed to list the jobs of the job session")
+
+// jobWatcher watches all jobs and the pods of the array job tasks of a
+// job session with shared informers and wakes up the waiters of a job
+// when the job or one of its pods changes.
+type jobWatcher struct {
+	sync.Mutex
+	informer    cache.SharedIndexInformer
+	lister      batchlisters.JobLister
+	podInformer cache.SharedIndexInformer
+	podLister   corelisters.PodLister
+	stopCh      chan struct{}
+	stopOnce    sync.Once
+	// synced is closed when the initial lists are received, failed
+	// when they could not be received within the watchSyncTimeout
+	synced   chan struct{}
+	failed   chan struct{}
+	failedAt time.Time
+	waiters  map[string]map[chan struct{}]struct{}
+}
+
+func newJobWatcher(cs kubernetes.Interface, namespace, jobsession string) *jobWatcher {
+	factory := informers.NewSharedInformerFactoryWithOptions(cs, watchResyncPeriod,
+		informers.WithNamespace(namespace),
+		informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.LabelSelector = "drmaa2jobsession=" + jobsession
+		}))
+	jobInformer := factory.Batch().V1().Jobs()
+	podInformer := factory.Core().V1().Pods()
+	w := &jobWatcher{
+		informer:    jobInformer.Informer(),
+		lister:      jobInformer.Lister(),
+		podInformer: podInformer.Informer(),
+		podLister:   podInformer.Lister(),
+		stopCh:      make(chan struct{}),
+		synced:      make(chan struct{}),
+		failed:      make(chan struct{}),
+		waiters:     make(map[string]map[chan struct{}]struct{}),
+	}
+	w.informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: w.notify,
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			w.notify(newObj)
+		},
+		DeleteFunc: w.notify,
+	})
+	w.podInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: w.notifyPod,
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			w.notifyPod(newObj)
+		},
+		DeleteFunc: w.notifyPod,
+	})
+	return w
+}
+
+// start runs the informers in the background. The synced channel is
+// closed when the jobs and pods are listed, the failed channel when
+// that does not happen within the watchSyncTimeout.
+func (w *jobWatcher) start() {
+	go w.informer.Run(w.stopCh)
+	go w.podInformer.Run(w.stopCh)
+
+	syncTimeout := make(chan struct{})
+	timer := time.AfterFunc(watchSyncTimeout, func() { close(syncTimeout) })
+
+	stopCh := make(chan struct{})
+	go func() {
+		select {
+		case <-syncTimeout:
+		case <-w.stopCh:
+		}
+		close(stopCh)
+	}()
+	go func() {
+		defer timer.Stop()
+		if !cache.WaitForCacheSync(stopCh, w.informer.HasSynced, w.podInformer.HasSynced) {
+			w.Lock()
+			w.failedAt = time.Now()
+			w.Unlock()
+			w.stop()
+			close(w.failed)
+			return
+		}
+		close(w.synced)
+	}()
+}
+
+// waitForSync blocks until the jobs are listed or the timeout is
+// reached. It returns false if the jobs are not listed (yet).
+func (w *jobWatcher) waitForSync(timeout time.Duration) bool {
+	select {
+	case <-w.synced:
+		return true
+	case <-w.failed:
+		return false
+	default:
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-w.synced:
+		return true
+	case <-w.failed:
+		return false
+	case <-timer.C:
+		return false
+	}
+}
+
+// retryable returns true if the watcher failed and can be replaced.
+func (w *jobWatcher) retryable() bool {
+	select {
+	case <-w.failed:
+		w.Lock()
+		defer w.Unlock()
+		return time.Since(w.failedAt) >= watchRetryInterval
+	default:
+		return false
+	}
+}
+
+func (w *jobWatcher) stop() {
+	w.stopOnce.Do(func() { close(w.stopCh) })
+}
+
+// notify wakes up all waiters of the given job object.
+func (w *jobWatcher) notify(obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		return
+	}
+	_, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return
+	}
+	w.Lock()
+	defer w.Unlock()
+	for ch := range w.waiters[name] {
+		select {
+		case ch <- struct{}{}:
+		default:
+			// waiter is already woken up
+		}
+	}
+}
+
+// notifyPod wakes up all waiters of the job of the given pod.
+func (w *jobWatcher) notifyPod(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return
+	}
+	jobName := pod.Labels["job-name"]
+	if jobName == "" {
+		return
+	}
+	w.Lock()
+	defer w.Unlock()
+	for ch := range w.waiters[jobName] {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// subscribe returns a channel which gets a message when the job with
+// the given name changes. The returned function removes the subscription.
+func (w *jobWatcher) subscribe(jobName string) (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+	w.Lock()
+	if w.waiters[jobName] == nil {
+		w.waiters[jobName] = make(map[chan struct{}]struct{})
+	}
+	w.waiters[jobName][ch] = struct{}{}
+	w.Unlock()
+	return ch, func() {
+		w.Lock()
+		defer w.Unlock()
+		delete(w.waiters[jobName], ch)
+		if len(w.waiters[jobName]) == 0 {
+			delete(w.waiters, jobName)
+		}
+	}
+}
+
+// getWatcher returns the job watcher of the job session which is
+// created and started in the background when called the first time.
+// A watcher which failed to list the jobs is replaced after the
+// watchRetryInterval, until then errWatchFailed is returned.
+func (kt *KubernetesTracker) getWatcher() (*jobWatcher, error) {
+	kt.watcherMutex.Lock()
+	defer kt.watcherMutex.Unlock()
+	if kt.watcher != nil && !kt.watcher.retryable() {
+		select {
+		case <-kt.watcher.failed:
+			return nil, errWatchFailed
+		default:
+			return kt.watcher, nil
+		}
+	}
+	w := newJobWatcher(kt.clientSet, kt.namespace, kt.jobsession)
+	w.start()
+	kt.watcher = w
+	return w, nil
+}
+
+// watchedJobState returns the state of a job or a task of an array job
+// taken from the informer cache. Like JobState() it returns Undetermined
+// for jobs which don't exist.
+func (kt *KubernetesTracker) watchedJobState(w *jobWatcher, jobID string) drmaa2interface.JobState {
+	jobName, taskID, isTask, err := splitTaskID(jobID)
+	if err != nil {
+		return drmaa2interface.Undetermined
+	}
+	job, err := w.lister.Jobs(kt.namespace).Get(jobName)
+	if err != nil {
+		return drmaa2interface.Undetermined
+	}
+	if !isTask {
+		return convertJob2JobState(job)
+	}
+	index, err := taskIndex(job, taskID)
+	if err != nil {
+		return drmaa2interface.Undetermined
+	}
+	return taskStateFromPods(job, index, func() ([]corev1.Pod, error) {
+		return w.taskPods(kt.namespace, jobName, index)
+	})
+}
+
+// taskPods returns the pods of a task of an array job from the informer
+// cache.
+func (w *jobWatcher) taskPods(namespace, jobName string, index int) ([]corev1.Pod, error) {
+	selector := labels.SelectorFromSet(labels.Set{
+		"job-name":           jobName,
+		labelCompletionIndex: strconv.Itoa(index),
+	})
+	cached, err := w.podLister.Pods(namespace).List(selector)
+	if err != nil {
+		return nil, err
+	}
+	if len(cached) == 0 {
+		return nil, fmt.Errorf("no pod for job with label selector %s", selector)
+	}
+	pods := make([]corev1.Pod, 0, len(cached))
+	for _, pod := range cached {
+		pods = append(pods, *pod)
+	}
+	return pods, nil
+}
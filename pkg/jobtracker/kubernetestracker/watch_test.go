@@ -0,0 +1,225 @@
+package kubernetestracker
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/dgruber/drmaa2interface"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+var _ = Describe("Watch", func() {
+
+	var cs *fake.Clientset
+	var kt *KubernetesTracker
+
+	newSessionJob := func(name string) *batchv1.Job {
+		return &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels:    map[string]string{"drmaa2jobsession": "session"},
+			},
+		}
+	}
+
+	// setJobStatus updates the job status until the waiter returns
+	// as the fake clientset does not guarantee that the watch is
+	// established before the first update.
+	setJobStatus := func(name string, status batchv1.JobStatus, waitErr chan error) error {
+		var err error
+		Eventually(func() bool {
+			job, errGet := cs.BatchV1().Jobs("default").Get(context.Background(),
+				name, metav1.GetOptions{})
+			Ω(errGet).Should(BeNil())
+			job.Status = status
+			_, errUpdate := cs.BatchV1().Jobs("default").UpdateStatus(
+				context.Background(), job, metav1.UpdateOptions{})
+			Ω(errUpdate).Should(BeNil())
+			select {
+			case err = <-waitErr:
+				return true
+			default:
+				return false
+			}
+		}, time.Second*5, time.Millisecond*50).Should(BeTrue())
+		return err
+	}
+
+	BeforeEach(func() {
+		cs = fake.NewSimpleClientset(newSessionJob("job1"), newSessionJob("job2"))
+		kt = newKubernetesTracker("session", "default", cs)
+	})
+
+	AfterEach(func() {
+		Ω(kt.Close()).Should(BeNil())
+	})
+
+	It("should return immediately when the job is in the expected state", func() {
+		err := kt.Wait("job1", time.Second, drmaa2interface.Undetermined)
+		Ω(err).Should(BeNil())
+	})
+
+	It("should time out when the job does not reach the state", func() {
+		err := kt.Wait("job1", time.Millisecond*100, drmaa2interface.Done)
+		Ω(err).ShouldNot(BeNil())
+		err = kt.Wait("job1", drmaa2interface.ZeroTime, drmaa2interface.Done)
+		Ω(err).ShouldNot(BeNil())
+	})
+
+	It("should wake up the waiter when the job finishes", func() {
+		waitErr := make(chan error, 1)
+		go func() {
+			defer GinkgoRecover()
+			waitErr <- kt.Wait("job1", drmaa2interface.InfiniteTime,
+				drmaa2interface.Done, drmaa2interface.Failed)
+		}()
+		err := setJobStatus("job1", batchv1.JobStatus{Succeeded: 1}, waitErr)
+		Ω(err).Should(BeNil())
+		state, _, err := kt.JobState("job1")
+		Ω(err).Should(BeNil())
+		Ω(state).Should(Equal(drmaa2interface.Done))
+	})
+
+	It("should share one watcher between all waiters of the job session", func() {
+		waitErr := make(chan error, 2)
+		for _, job := range []string{"job1", "job2"} {
+			jobID := job
+			go func() {
+				defer GinkgoRecover()
+				waitErr <- kt.Wait(jobID, time.Second*10, drmaa2interface.Running)
+			}()
+		}
+		Eventually(func() int {
+			w, err := kt.getWatcher()
+			Ω(err).Should(BeNil())
+			w.Lock()
+			defer w.Unlock()
+			return len(w.waiters)
+		}).Should(Equal(2))
+
+		err := setJobStatus("job2", batchv1.JobStatus{Active: 1}, waitErr)
+		Ω(err).Should(BeNil())
+		Consistently(waitErr, time.Millisecond*200).ShouldNot(Receive())
+		err = setJobStatus("job1", batchv1.JobStatus{Active: 1}, waitErr)
+		Ω(err).Should(BeNil())
+	})
+
+	It("should wake up waiters on tasks of an array job", func() {
+		jt := drmaa2interface.JobTemplate{
+			JobName:       "array",
+			RemoteCommand: "/bin/sleep",
+			JobCategory:   "busybox:latest",
+		}
+		job, err := convertArrayJob("session", "default", jt, 1, 3, 1, 0)
+		Ω(err).Should(BeNil())
+		_, err = cs.BatchV1().Jobs("default").Create(context.Background(), job,
+			metav1.CreateOptions{})
+		Ω(err).Should(BeNil())
+
+		waitErr := make(chan error, 1)
+		go func() {
+			defer GinkgoRecover()
+			waitErr <- kt.Wait("array:2", time.Second*10, drmaa2interface.Done)
+		}()
+		err = setJobStatus("array", batchv1.JobStatus{
+			Active:           1,
+			CompletedIndexes: "1",
+		}, waitErr)
+		Ω(err).Should(BeNil())
+	})
+
+	It("should wake up waiters on tasks of an array job by pod changes", func() {
+		jt := drmaa2interface.JobTemplate{
+			JobName:       "array",
+			RemoteCommand: "/bin/sleep",
+			JobCategory:   "busybox:latest",
+		}
+		job, err := convertArrayJob("session", "default", jt, 1, 3, 1, 0)
+		Ω(err).Should(BeNil())
+		_, err = cs.BatchV1().Jobs("default").Create(context.Background(), job,
+			metav1.CreateOptions{})
+		Ω(err).Should(BeNil())
+
+		waitErr := make(chan error, 1)
+		go func() {
+			defer GinkgoRecover()
+			waitErr <- kt.Wait("array:1", time.Second*10, drmaa2interface.Running)
+		}()
+		Eventually(func() int {
+			w, err := kt.getWatcher()
+			Ω(err).Should(BeNil())
+			w.Lock()
+			defer w.Unlock()
+			return len(w.waiters)
+		}).Should(Equal(1))
+
+		_, err = cs.CoreV1().Pods("default").Create(context.Background(), &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "array-0",
+				Namespace: "default",
+				Labels: map[string]string{
+					"drmaa2jobsession":   "session",
+					"job-name":           "array",
+					labelCompletionIndex: "0",
+				},
+			},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning},
+		}, metav1.CreateOptions{})
+		Ω(err).Should(BeNil())
+		Eventually(waitErr, time.Second*5).Should(Receive(BeNil()))
+
+		// the pods are taken from the informer cache
+		for _, action := range cs.Actions() {
+			if list, ok := action.(k8stesting.ListAction); ok && action.GetResource().Resource == "pods" {
+				Ω(list.GetListRestrictions().Labels.String()).ShouldNot(ContainSubstring("job-name"))
+			}
+		}
+	})
+
+	It("should poll without blocking when the jobs can't be watched", func() {
+		syncTimeout := watchSyncTimeout
+		watchSyncTimeout = time.Millisecond * 200
+		defer func() { watchSyncTimeout = syncTimeout }()
+
+		cs.PrependReactor("list", "jobs", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, errors.New("forbidden")
+		})
+
+		start := time.Now()
+		err := kt.Wait("job1", drmaa2interface.ZeroTime, drmaa2interface.Done)
+		Ω(err).ShouldNot(BeNil())
+		Ω(time.Since(start)).Should(BeNumerically("<", time.Millisecond*100))
+
+		Eventually(func() error {
+			_, err := kt.getWatcher()
+			return err
+		}).Should(MatchError(errWatchFailed))
+
+		// the failure is remembered
+		w := kt.watcher
+		start = time.Now()
+		err = kt.Wait("job1", drmaa2interface.ZeroTime, drmaa2interface.Done)
+		Ω(err).ShouldNot(BeNil())
+		Ω(time.Since(start)).Should(BeNumerically("<", time.Millisecond*100))
+		Ω(kt.watcher).Should(BeIdenticalTo(w))
+	})
+
+	It("should stop watching when the tracker is closed", func() {
+		w, err := kt.getWatcher()
+		Ω(err).Should(BeNil())
+		Ω(kt.Close()).Should(BeNil())
+		Eventually(w.stopCh).Should(BeClosed())
+		Ω(kt.watcher).Should(BeNil())
+	})
+
+})
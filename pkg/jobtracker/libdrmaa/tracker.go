@@ -30,6 +30,42 @@ func (a *allocator) New(jobSessionName string, jobTrackerInitParams interface{})
 	return NewDRMAATrackerWithParams(jobTrackerInitParams)
 }
 
+// DrmsName returns "libdrmaa".
+func (a *allocator) DrmsName() string {
+	return "libdrmaa"
+}
+
+// DrmsVersion returns the version of the DRMAA standard implemented
+// by libdrmaa.so.
+func (a *allocator) DrmsVersion() (drmaa2interface.Version, error) {
+	major, minor, err := drmaa.GetVersion()
+	if err != nil {
+		return drmaa2interface.Version{}, err
+	}
+	return drmaa2interface.Version{
+		Major: fmt.Sprintf("%d", major),
+		Minor: fmt.Sprintf("%d", minor),
+	}, nil
+}
+
+// Supports returns true for email notification which is forwarded
+// to libdrmaa.
+func (a *allocator) Supports(capability drmaa2interface.Capability) bool {
+	return capability == drmaa2interface.JtEmail
+}
+
+// SupportsJobControl returns true for all job control actions
+// as they are forwarded to libdrmaa.
+func (a *allocator) SupportsJobControl(action string) bool {
+	switch action {
+	case jobtracker.JobControlSuspend, jobtracker.JobControlResume,
+		jobtracker.JobControlHold, jobtracker.JobControlRelease,
+		jobtracker.JobControlTerminate:
+		return true
+	}
+	return false
+}
+
 // LibDRMAASessionParams contains arguments which can be evaluated
 // during DRMAA2 job session creation.
 type LibDRMAASessionParams struct {
@@ -2,7 +2,10 @@ package podmantracker
 
 import (
 	"errors"
+	"strings"
 
+	"github.com/containers/podman/v3/pkg/bindings/system"
+	"github.com/dgruber/drmaa2interface"
 	"github.com/dgruber/drmaa2os/pkg/jobtracker"
 )
 
@@ -31,3 +34,46 @@ func (a *allocator) New(jobSessionName string, jobTrackerInitParams interface{})
 	}
 	return New(jobSessionName, PodmanTrackerParams{})
 }
+
+// DrmsName returns "podman".
+func (a *allocator) DrmsName() string {
+	return "podman"
+}
+
+// DrmsVersion returns the version of podman reachable through
+// the default socket.
+func (a *allocator) DrmsVersion() (drmaa2interface.Version, error) {
+	p, err := New("", PodmanTrackerParams{})
+	if err != nil {
+		return drmaa2interface.Version{}, err
+	}
+	report, err := system.Version(p.connectionContext, nil)
+	if err != nil {
+		return drmaa2interface.Version{}, err
+	}
+	version := report.Client
+	if report.Server != nil {
+		version = report.Server
+	}
+	if version == nil {
+		return drmaa2interface.Version{}, errors.New("podman did not report a version")
+	}
+	major, minor, _ := strings.Cut(version.Version, ".")
+	return drmaa2interface.Version{Major: major, Minor: minor}, nil
+}
+
+// Supports returns false as none of the optional DRMAA2 capabilities
+// are implemented.
+func (a *allocator) Supports(capability drmaa2interface.Capability) bool {
+	return false
+}
+
+// SupportsJobControl returns true for suspend, resume, and terminate.
+func (a *allocator) SupportsJobControl(action string) bool {
+	switch action {
+	case jobtracker.JobControlSuspend, jobtracker.JobControlResume,
+		jobtracker.JobControlTerminate:
+		return true
+	}
+	return false
+}
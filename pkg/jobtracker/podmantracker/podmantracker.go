@@ -59,6 +59,7 @@ func (p *PodmanTracker) ListJobs() ([]string, error) {
 }
 
 func (p *PodmanTracker) AddJob(template drmaa2interface.JobTemplate) (string, error) {
+	template = helper.ReplacePlaceholders(template, 0, false, helper.ContainerHomeDirectory(template))
 	return RunPodmanContainer(p.connectionContext, template, p.disableImagePull)
 }
 
@@ -14,6 +14,25 @@ type ClientTrackerParams struct {
 	Path string
 	// Opts are additional settings for the client, like for authentication
 	Opts []genclient.ClientOption
+	// CACertFile is a PEM encoded CA bundle for verifying the certificate
+	// of a https server. If not set the CAs of the system are used.
+	CACertFile string
+	// ClientCertFile and ClientKeyFile are the PEM encoded certificate
+	// and key of the client for servers which require mutual TLS.
+	ClientCertFile string
+	ClientKeyFile  string
+	// Token is sent as bearer token in the Authorization header.
+	Token string
+	// APIKey is sent in the APIKeyHeader ("X-API-Key" if not set).
+	APIKey       string
+	APIKeyHeader string
+	// JobSessions is set when the server hosts multiple job sessions
+	// (server.SessionServer). The job tracker then manages the jobs of
+	// the job session it was created for.
+	JobSessions bool
+	// Backend selects the backend (like "docker") of job sessions which
+	// are created at the server. If not set the server uses its default.
+	Backend string
 }
 
 type allocator struct{}
@@ -0,0 +1,78 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+
+	genclient "github.com/dgruber/drmaa2os/pkg/jobtracker/remote/client/generated"
+)
+
+// defaultAPIKeyHeader is the header of the API key if not configured.
+const defaultAPIKeyHeader = "X-API-Key"
+
+// authOptions creates the client options for TLS and authentication
+// from the parameters. They are applied before the Opts of the
+// parameters so that Opts can override them.
+func authOptions(params ClientTrackerParams) ([]genclient.ClientOption, error) {
+	opts := make([]genclient.ClientOption, 0, 2)
+	tlsConfig, err := clientTLSConfig(params)
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig != nil {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = tlsConfig
+		opts = append(opts, genclient.WithHTTPClient(&http.Client{Transport: transport}))
+	}
+	if params.Token != "" {
+		opts = append(opts, genclient.WithRequestEditorFn(
+			func(ctx context.Context, req *http.Request) error {
+				req.Header.Set("Authorization", "Bearer "+params.Token)
+				return nil
+			}))
+	}
+	if params.APIKey != "" {
+		header := params.APIKeyHeader
+		if header == "" {
+			header = defaultAPIKeyHeader
+		}
+		opts = append(opts, genclient.WithRequestEditorFn(
+			func(ctx context.Context, req *http.Request) error {
+				req.Header.Set(header, params.APIKey)
+				return nil
+			}))
+	}
+	return opts, nil
+}
+
+// clientTLSConfig returns the TLS configuration for the CA bundle and
+// client certificate or nil if none of them is set.
+func clientTLSConfig(params ClientTrackerParams) (*tls.Config, error) {
+	if params.CACertFile == "" && params.ClientCertFile == "" && params.ClientKeyFile == "" {
+		return nil, nil
+	}
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+	if params.CACertFile != "" {
+		pem, err := os.ReadFile(params.CACertFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed reading CA bundle: %v", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA bundle %s", params.CACertFile)
+		}
+		config.RootCAs = pool
+	}
+	if params.ClientCertFile != "" || params.ClientKeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(params.ClientCertFile, params.ClientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed loading client certificate: %v", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	return config, nil
+}
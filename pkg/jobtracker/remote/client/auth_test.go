@@ -0,0 +1,300 @@
+package client_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/dgruber/drmaa2interface"
+
+	. "github.com/dgruber/drmaa2os/pkg/jobtracker/remote/client"
+	"github.com/dgruber/drmaa2os/pkg/jobtracker/remote/server"
+	genserver "github.com/dgruber/drmaa2os/pkg/jobtracker/remote/server/generated"
+	"github.com/dgruber/drmaa2os/pkg/jobtracker/simpletracker"
+)
+
+// testCA issues certificates for the TLS tests
+type testCA struct {
+	dir  string
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newTestCA(dir, name string) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).To(BeNil())
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	Expect(err).To(BeNil())
+	cert, err := x509.ParseCertificate(der)
+	Expect(err).To(BeNil())
+	ca := &testCA{dir: dir, cert: cert, key: key}
+	writePEM(ca.file(name+".crt"), "CERTIFICATE", der)
+	return ca
+}
+
+func (ca *testCA) file(name string) string {
+	return filepath.Join(ca.dir, name)
+}
+
+// issue creates a certificate and key file for the server or a client.
+func (ca *testCA) issue(name string, usage x509.ExtKeyUsage) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).To(BeNil())
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	Expect(err).To(BeNil())
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	Expect(err).To(BeNil())
+	writePEM(ca.file(name+".crt"), "CERTIFICATE", der)
+	writePEM(ca.file(name+".key"), "EC PRIVATE KEY", keyDER)
+	return ca.file(name + ".crt"), ca.file(name + ".key")
+}
+
+func writePEM(path, blockType string, der []byte) {
+	err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0600)
+	Expect(err).To(BeNil())
+}
+
+var _ = Describe("Authentication", func() {
+
+	var (
+		ca         *testCA
+		tlsServer  *httptest.Server
+		serverCert string
+		serverKey  string
+	)
+
+	// startServer starts a https server which requires client
+	// certificates when clientCA is set
+	startServer := func(clientCA string, middlewares ...func(h chi.Router)) {
+		impl, _ := server.NewJobTrackerImpl(simpletracker.New("drmaa2ostestjobsession"))
+		router := chi.NewRouter()
+		for _, m := range middlewares {
+			m(router)
+		}
+		tlsServer = httptest.NewUnstartedServer(genserver.HandlerFromMux(impl, router))
+		tlsConfig, err := server.TLSConfig(serverCert, serverKey, clientCA)
+		Expect(err).To(BeNil())
+		tlsServer.TLS = tlsConfig
+		tlsServer.StartTLS()
+	}
+
+	BeforeEach(func() {
+		ca = newTestCA(GinkgoT().TempDir(), "testca")
+		serverCert, serverKey = ca.issue("server", x509.ExtKeyUsageServerAuth)
+	})
+
+	AfterEach(func() {
+		if tlsServer != nil {
+			tlsServer.Close()
+		}
+	})
+
+	It("should connect to a https server with the CA bundle", func() {
+		startServer("")
+
+		c, err := New("session", ClientTrackerParams{
+			Server:     tlsServer.URL,
+			CACertFile: ca.file("testca.crt"),
+		})
+		Expect(err).To(BeNil())
+		_, err = c.ListJobs()
+		Expect(err).To(BeNil())
+
+		c, err = New("session", ClientTrackerParams{Server: tlsServer.URL})
+		Expect(err).To(BeNil())
+		_, err = c.ListJobs()
+		Expect(err).NotTo(BeNil())
+	})
+
+	It("should authenticate with a client certificate", func() {
+		startServer(ca.file("testca.crt"), func(r chi.Router) {
+			r.Use(server.ClientCertAuth())
+		})
+		clientCert, clientKey := ca.issue("user1", x509.ExtKeyUsageClientAuth)
+
+		c, err := New("session", ClientTrackerParams{
+			Server:         tlsServer.URL,
+			CACertFile:     ca.file("testca.crt"),
+			ClientCertFile: clientCert,
+			ClientKeyFile:  clientKey,
+		})
+		Expect(err).To(BeNil())
+		_, err = c.ListJobs()
+		Expect(err).To(BeNil())
+
+		c, err = New("session", ClientTrackerParams{
+			Server:     tlsServer.URL,
+			CACertFile: ca.file("testca.crt"),
+		})
+		Expect(err).To(BeNil())
+		_, err = c.ListJobs()
+		Expect(err).NotTo(BeNil())
+
+		// certificates of a different CA are rejected
+		otherCA := newTestCA(GinkgoT().TempDir(), "otherca")
+		otherCert, otherKey := otherCA.issue("user2", x509.ExtKeyUsageClientAuth)
+		c, err = New("session", ClientTrackerParams{
+			Server:         tlsServer.URL,
+			CACertFile:     ca.file("testca.crt"),
+			ClientCertFile: otherCert,
+			ClientKeyFile:  otherKey,
+		})
+		Expect(err).To(BeNil())
+		_, err = c.ListJobs()
+		Expect(err).NotTo(BeNil())
+	})
+
+	It("should authenticate with a bearer token", func() {
+		startServer("", func(r chi.Router) {
+			r.Use(server.BearerTokenAuth(map[string]string{"secret": "user1"}))
+		})
+
+		c, err := New("session", ClientTrackerParams{
+			Server:     tlsServer.URL,
+			CACertFile: ca.file("testca.crt"),
+			Token:      "secret",
+		})
+		Expect(err).To(BeNil())
+		_, err = c.ListJobs()
+		Expect(err).To(BeNil())
+
+		c, err = New("session", ClientTrackerParams{
+			Server:     tlsServer.URL,
+			CACertFile: ca.file("testca.crt"),
+			Token:      "wrong",
+		})
+		Expect(err).To(BeNil())
+		_, err = c.AddJob(drmaa2interface.JobTemplate{RemoteCommand: "/bin/true"})
+		Expect(err).NotTo(BeNil())
+	})
+
+	It("should authenticate with an API key", func() {
+		startServer("", func(r chi.Router) {
+			r.Use(server.APIKeyAuth("X-Token", map[string]string{"key1": "user1"}))
+		})
+
+		c, err := New("session", ClientTrackerParams{
+			Server:       tlsServer.URL,
+			CACertFile:   ca.file("testca.crt"),
+			APIKey:       "key1",
+			APIKeyHeader: "X-Token",
+		})
+		Expect(err).To(BeNil())
+		_, err = c.AddJob(drmaa2interface.JobTemplate{RemoteCommand: "/bin/true"})
+		Expect(err).To(BeNil())
+
+		c, err = New("session", ClientTrackerParams{
+			Server:     tlsServer.URL,
+			CACertFile: ca.file("testca.crt"),
+			APIKey:     "key1",
+		})
+		Expect(err).To(BeNil())
+		_, err = c.AddJob(drmaa2interface.JobTemplate{RemoteCommand: "/bin/true"})
+		Expect(err).NotTo(BeNil())
+	})
+
+	It("should deny access to jobs of other and unknown users", func() {
+		startServer("", func(r chi.Router) {
+			r.Use(server.BearerTokenAuth(map[string]string{
+				"token1": "user1",
+				"token2": "user2",
+			}))
+		})
+
+		owner, err := New("session", ClientTrackerParams{
+			Server:     tlsServer.URL,
+			CACertFile: ca.file("testca.crt"),
+			Token:      "token1",
+		})
+		Expect(err).To(BeNil())
+		other, err := New("session", ClientTrackerParams{
+			Server:     tlsServer.URL,
+			CACertFile: ca.file("testca.crt"),
+			Token:      "token2",
+		})
+		Expect(err).To(BeNil())
+
+		jobID, err := owner.AddJob(drmaa2interface.JobTemplate{
+			RemoteCommand: "/bin/sleep",
+			Args:          []string{"10"},
+		})
+		Expect(err).To(BeNil())
+
+		ji, err := other.JobInfo(jobID)
+		Expect(err).To(BeNil())
+		Expect(ji.JobOwner).To(Equal("user1"))
+
+		err = other.JobControl(jobID, "terminate")
+		Expect(err).NotTo(BeNil())
+		Expect(err.(drmaa2interface.Error).ID).To(Equal(drmaa2interface.DeniedByDrms))
+		err = other.DeleteJob(jobID)
+		Expect(err).NotTo(BeNil())
+		Expect(err.(drmaa2interface.Error).ID).To(Equal(drmaa2interface.DeniedByDrms))
+		_, err = other.JobTemplate(jobID)
+		Expect(err).NotTo(BeNil())
+		Expect(err.(drmaa2interface.Error).ID).To(Equal(drmaa2interface.DeniedByDrms))
+
+		unknown, err := New("session", ClientTrackerParams{
+			Server:     tlsServer.URL,
+			CACertFile: ca.file("testca.crt"),
+			Token:      "wrong",
+		})
+		Expect(err).To(BeNil())
+		err = unknown.JobControl(jobID, "terminate")
+		Expect(err).NotTo(BeNil())
+		Expect(err.(drmaa2interface.Error).ID).To(Equal(AuthException))
+		_, err = unknown.JobTemplate(jobID)
+		Expect(err).NotTo(BeNil())
+		Expect(err.(drmaa2interface.Error).ID).To(Equal(AuthException))
+
+		_, err = owner.JobTemplate(jobID)
+		Expect(err).To(BeNil())
+		Expect(owner.JobControl(jobID, "terminate")).To(Succeed())
+	})
+
+	It("should fail for invalid TLS files", func() {
+		_, err := New("session", ClientTrackerParams{
+			Server:     "https://localhost:32320",
+			CACertFile: ca.file("missing.crt"),
+		})
+		Expect(err).NotTo(BeNil())
+
+		_, err = New("session", ClientTrackerParams{
+			Server:         "https://localhost:32320",
+			ClientCertFile: ca.file("server.crt"),
+		})
+		Expect(err).NotTo(BeNil())
+	})
+
+})
@@ -2,7 +2,11 @@ package client
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/url"
+	"sync/atomic"
 	"time"
 
 	"github.com/dgruber/drmaa2interface"
@@ -11,8 +15,24 @@ import (
 	genclient "github.com/dgruber/drmaa2os/pkg/jobtracker/remote/client/generated"
 )
 
+// maxWaitRequestTime limits the time a single wait request blocks at
+// the server.
+const maxWaitRequestTime = time.Minute
+
+// AuthException is the ID of the DRMAA2 errors returned when the server
+// did not authenticate the user (401 Unauthorized). DRMAA2 defines no
+// error ID for failed authentications hence it is implementation
+// specific. Requests of authenticated users which are not allowed to
+// access a job or job session fail with DeniedByDrms (403 Forbidden).
+const AuthException = drmaa2interface.ImplementationSpecific
+
 type ClientJobTracker struct {
-	client genclient.ClientWithResponsesInterface
+	jobSession string
+	client     genclient.ClientWithResponsesInterface
+	// stream is used for requests which responses are streamed
+	stream genclient.ClientInterface
+	// pollWait is set when the server does not implement wait requests
+	pollWait atomic.Bool
 }
 
 // init registers the remote client tracker at the SessionManager
@@ -20,28 +40,45 @@ func init() {
 	drmaa2os.RegisterJobTracker(drmaa2os.RemoteSession, NewAllocator())
 }
 
-// New creates a new remote client job tracker.
+// New creates a new remote client job tracker. When the server hosts
+// multiple job sessions (ClientTrackerParams.JobSessions) the client
+// manages the jobs of the given job session which is created at the
+// server when it does not exist.
 func New(jobSessionName string, params ClientTrackerParams) (*ClientJobTracker, error) {
 	if params.Server == "" {
 		params.Server = "localhost:32321"
 	}
-	opts := make([]genclient.ClientOption, 0)
-	if params.Path != "" {
-		opts = []genclient.ClientOption{genclient.WithBaseURL(params.Server + params.Path)}
+	if params.JobSessions {
+		if err := openOrCreateJobSession(jobSessionName, params); err != nil {
+			return nil, fmt.Errorf("failed to create remote client: %v", err)
+		}
+		params.Path = params.Path + "/sessions/" + url.PathEscape(jobSessionName)
 	}
-	opts = append(opts, params.Opts...)
-	client, err := genclient.NewClientWithResponses(
-		params.Server,
-		opts...)
+	client, err := newClient(params)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create remote client: %v", err)
 	}
 
 	return &ClientJobTracker{
-		client: client,
+		jobSession: jobSessionName,
+		client:     &genclient.ClientWithResponses{ClientInterface: client},
+		stream:     client,
 	}, nil
 }
 
+// newClient creates the client of the JobTracker API.
+func newClient(params ClientTrackerParams) (*genclient.Client, error) {
+	opts, err := authOptions(params)
+	if err != nil {
+		return nil, err
+	}
+	if params.Path != "" {
+		opts = append(opts, genclient.WithBaseURL(params.Server+params.Path))
+	}
+	opts = append(opts, params.Opts...)
+	return genclient.NewClient(params.Server, opts...)
+}
+
 func (c *ClientJobTracker) ListJobs() ([]string, error) {
 	resp, err := c.client.ListJobsWithResponse(context.Background(),
 		&genclient.ListJobsParams{})
@@ -153,6 +190,9 @@ func (c *ClientJobTracker) JobControl(jobid, action string) error {
 	if err != nil || resp == nil {
 		return fmt.Errorf("failed changing job state: %v", err)
 	}
+	if err := deniedError("changing job state", resp.HTTPResponse, resp.Body); err != nil {
+		return err
+	}
 	if resp.JSON200 == nil {
 		return fmt.Errorf("failed changing job state")
 	}
@@ -163,12 +203,61 @@ func (c *ClientJobTracker) JobControl(jobid, action string) error {
 }
 
 // Wait until the job has a certain DRMAA2 state or return an error if the state
-// is unreachable.
+// is unreachable. The server blocks the wait requests until the job reaches
+// one of the states (long-poll). For servers which don't implement wait
+// requests the job state is polled.
 func (p *ClientJobTracker) Wait(jobid string, timeout time.Duration, states ...drmaa2interface.JobState) error {
-	// this is not specified in the OpenAPI spec for JobTracker as
-	// we can rely on the helper function querying the job state
-	// for now.
-	return helper.WaitForStateWithInterval(p, 200*time.Millisecond, jobid, timeout, states...)
+	if p.pollWait.Load() {
+		return helper.WaitForStateWithInterval(p, 200*time.Millisecond, jobid, timeout, states...)
+	}
+	params := genclient.WaitParams{
+		JobID: jobid,
+		State: make([]genclient.JobState, 0, len(states)),
+	}
+	for _, state := range states {
+		params.State = append(params.State, ConvertJobState(state))
+	}
+	start := time.Now()
+	for {
+		remaining := timeout - time.Since(start)
+		if timeout == drmaa2interface.InfiniteTime {
+			remaining = maxWaitRequestTime
+		}
+		if remaining < 0 {
+			remaining = 0
+		}
+		seconds := remaining.Seconds()
+		if remaining > maxWaitRequestTime {
+			seconds = maxWaitRequestTime.Seconds()
+		}
+		params.Timeout = &seconds
+
+		resp, err := p.client.WaitWithResponse(context.Background(), &params)
+		if err != nil || resp == nil {
+			return fmt.Errorf("failed waiting for job state: %v", err)
+		}
+		if resp.StatusCode() == http.StatusNotFound {
+			// server does not implement wait requests
+			p.pollWait.Store(true)
+			return helper.WaitForStateWithInterval(p, 200*time.Millisecond, jobid, remaining, states...)
+		}
+		if resp.JSON200 == nil {
+			return fmt.Errorf("failed waiting for job state at remote")
+		}
+		if resp.JSON200.Error != nil && *resp.JSON200.Error != "" {
+			return fmt.Errorf("failed waiting for job state: %s", *resp.JSON200.Error)
+		}
+		if !resp.JSON200.Timeout {
+			return nil
+		}
+		state := ConvertJobStateToDRMAA2(string(resp.JSON200.JobState))
+		if state == drmaa2interface.Done || state == drmaa2interface.Failed {
+			return fmt.Errorf("job finished in state %s", state)
+		}
+		if remaining == 0 {
+			return errors.New("timeout while waiting for job state")
+		}
+	}
 }
 
 // DeleteJob removes a finished job from remote.
@@ -179,6 +268,9 @@ func (c *ClientJobTracker) DeleteJob(jobid string) error {
 	if err != nil || resp == nil {
 		return fmt.Errorf("failed deleting job from remote: %v", err)
 	}
+	if err := deniedError("deleting job", resp.HTTPResponse, resp.Body); err != nil {
+		return err
+	}
 	if resp.JSON200 == nil {
 		return fmt.Errorf("failed deleting job from remote")
 	}
@@ -200,3 +292,27 @@ func (c *ClientJobTracker) ListJobCategories() ([]string, error) {
 	}
 	return *resp.JSON200, nil
 }
+
+// deniedError converts the responses of a server which rejected the
+// request of the user into a DRMAA2 error. 403 is returned when the user
+// is not allowed to access the job (like when the job is owned by
+// someone else) and results in a DeniedByDrms error, 401 is returned
+// when the user is not authenticated and results in an AuthException.
+func deniedError(operation string, resp *http.Response, body []byte) error {
+	if resp == nil {
+		return nil
+	}
+	switch resp.StatusCode {
+	case http.StatusForbidden:
+		return drmaa2interface.Error{
+			Message: fmt.Sprintf("%s denied: %s", operation, responseError(resp, body)),
+			ID:      drmaa2interface.DeniedByDrms,
+		}
+	case http.StatusUnauthorized:
+		return drmaa2interface.Error{
+			Message: fmt.Sprintf("%s not authenticated: %s", operation, responseError(resp, body)),
+			ID:      AuthException,
+		}
+	}
+	return nil
+}
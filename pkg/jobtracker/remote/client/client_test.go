@@ -3,6 +3,10 @@ package client_test
 import (
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/deepmap/oapi-codegen/pkg/securityprovider"
@@ -13,6 +17,7 @@ import (
 
 	"github.com/dgruber/drmaa2interface"
 
+	"github.com/dgruber/drmaa2os"
 	"github.com/dgruber/drmaa2os/pkg/jobtracker"
 	. "github.com/dgruber/drmaa2os/pkg/jobtracker/remote/client"
 	genclient "github.com/dgruber/drmaa2os/pkg/jobtracker/remote/client/generated"
@@ -22,6 +27,11 @@ import (
 	"github.com/dgruber/drmaa2os/pkg/jobtracker/simpletracker"
 )
 
+// pollingTracker hides the event notification of the wrapped job tracker.
+type pollingTracker struct {
+	jobtracker.JobTracker
+}
+
 var _ = Describe("Client", func() {
 
 	var testServer *httptest.Server
@@ -126,6 +136,244 @@ var _ = Describe("Client", func() {
 
 	})
 
+	Context("wait and events", func() {
+
+		// countRequests counts the requests per path and rejects the
+		// requests to the given paths like an old server would do
+		countRequests := func(handler http.Handler, rejected ...string) map[string]int {
+			counts := make(map[string]int)
+			var mtx sync.Mutex
+			testServer.Close()
+			testServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mtx.Lock()
+				counts[r.URL.Path]++
+				mtx.Unlock()
+				for _, path := range rejected {
+					if r.URL.Path == path {
+						http.NotFound(w, r)
+						return
+					}
+				}
+				handler.ServeHTTP(w, r)
+			}))
+			var err error
+			client, err = New("clientdrmaa2ostestjobsession", ClientTrackerParams{
+				Server: testServer.URL,
+			})
+			Expect(err).To(BeNil())
+			return counts
+		}
+
+		It("should wait at the server instead of polling the job state", func() {
+			impl, _ := server.NewJobTrackerImpl(simpletracker.New("drmaa2ostestjobsession"))
+			counts := countRequests(genserver.Handler(impl))
+
+			jobid, err := client.AddJob(drmaa2interface.JobTemplate{
+				RemoteCommand: "/bin/sleep",
+				Args:          []string{"1"},
+			})
+			Expect(err).To(BeNil())
+
+			err = client.Wait(jobid, time.Second*5, drmaa2interface.Done)
+			Expect(err).To(BeNil())
+			Expect(counts["/wait"]).To(Equal(1))
+			Expect(counts["/jobstate"]).To(Equal(0))
+		})
+
+		It("should return an error when the job finished in a different state", func() {
+			jobid, err := client.AddJob(drmaa2interface.JobTemplate{
+				RemoteCommand: "/bin/sh",
+				Args:          []string{"-c", "exit 1"},
+			})
+			Expect(err).To(BeNil())
+			err = client.Wait(jobid, time.Second*5, drmaa2interface.Done)
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should return an error when the timeout is reached", func() {
+			jobid, err := client.AddJob(drmaa2interface.JobTemplate{
+				RemoteCommand: "/bin/sleep",
+				Args:          []string{"1"},
+			})
+			Expect(err).To(BeNil())
+			err = client.Wait(jobid, time.Millisecond*200, drmaa2interface.Done)
+			Expect(err).NotTo(BeNil())
+			err = client.Wait(jobid, drmaa2interface.InfiniteTime, drmaa2interface.Done)
+			Expect(err).To(BeNil())
+		})
+
+		It("should poll the job state when the server does not support wait", func() {
+			impl, _ := server.NewJobTrackerImpl(simpletracker.New("drmaa2ostestjobsession"))
+			counts := countRequests(genserver.Handler(impl), "/wait", "/events")
+
+			jobid, err := client.AddJob(drmaa2interface.JobTemplate{
+				RemoteCommand: "/bin/sleep",
+				Args:          []string{"1"},
+			})
+			Expect(err).To(BeNil())
+
+			err = client.Wait(jobid, time.Second*5, drmaa2interface.Done)
+			Expect(err).To(BeNil())
+			Expect(counts["/wait"]).To(Equal(1))
+			Expect(counts["/jobstate"]).To(BeNumerically(">", 1))
+
+			_, _, err = client.RegisterEventNotification()
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should stream the job events", func() {
+			events, stop, err := client.RegisterEventNotification()
+			Expect(err).To(BeNil())
+			defer stop()
+
+			jobid, err := client.AddJob(drmaa2interface.JobTemplate{
+				RemoteCommand: "/bin/sleep",
+				Args:          []string{"0"},
+			})
+			Expect(err).To(BeNil())
+
+			var states []drmaa2interface.JobState
+			Eventually(func() []drmaa2interface.JobState {
+				select {
+				case notification := <-events:
+					Expect(notification.JobID).To(Equal(jobid))
+					Expect(notification.SessionName).To(Equal("clientdrmaa2ostestjobsession"))
+					states = append(states, notification.State)
+				default:
+				}
+				return states
+			}, time.Second*5).Should(ContainElement(drmaa2interface.Done))
+		})
+
+		It("should stream the job events of trackers which can't push events", func() {
+			impl, _ := server.NewJobTrackerImpl(pollingTracker{
+				JobTracker: simpletracker.New("drmaa2ostestjobsession")})
+			countRequests(genserver.Handler(impl))
+
+			events, stop, err := client.RegisterEventNotification()
+			Expect(err).To(BeNil())
+
+			jobid, err := client.AddJob(drmaa2interface.JobTemplate{
+				RemoteCommand: "/bin/sleep",
+				Args:          []string{"0"},
+			})
+			Expect(err).To(BeNil())
+
+			Eventually(events, time.Second*5).Should(Receive(
+				WithTransform(func(n drmaa2interface.Notification) string {
+					return n.JobID
+				}, Equal(jobid))))
+			stop()
+			Eventually(events, time.Second*5).Should(BeClosed())
+		})
+
+	})
+
+	Context("monitoring and job templates", func() {
+
+		It("should return the job template of a job", func() {
+			jobid, err := client.AddJob(drmaa2interface.JobTemplate{
+				RemoteCommand: "/bin/sleep",
+				Args:          []string{"0"},
+				JobName:       "templatejob",
+			})
+			Expect(err).To(BeNil())
+			jt, err := client.JobTemplate(jobid)
+			Expect(err).To(BeNil())
+			Expect(jt.RemoteCommand).To(Equal("/bin/sleep"))
+			Expect(jt.Args).To(Equal([]string{"0"}))
+			Expect(jt.JobName).To(Equal("templatejob"))
+
+			_, err = client.JobTemplate("unknown")
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should list the jobs and machines of the backend", func() {
+			Expect(client.OpenMonitoringSession("monitor")).To(BeNil())
+			defer client.CloseMonitoringSession("monitor")
+
+			// the process backend monitors all processes of the host
+			// including the process of the test server
+			pid := strconv.Itoa(os.Getpid())
+			ids, err := client.GetAllJobIDs(nil)
+			Expect(err).To(BeNil())
+			Expect(ids).To(ContainElement(pid))
+
+			ji, err := client.JobInfoFromMonitor(pid)
+			Expect(err).To(BeNil())
+			Expect(ji.ID).To(Equal(pid))
+
+			machines, err := client.GetAllMachines(nil)
+			Expect(err).To(BeNil())
+			Expect(machines).To(HaveLen(1))
+			Expect(machines[0].Name).NotTo(Equal(""))
+
+			machines, err = client.GetAllMachines([]string{"unknownmachine"})
+			Expect(err).To(BeNil())
+			Expect(machines).To(HaveLen(0))
+
+			queues, err := client.GetAllQueueNames(nil)
+			Expect(err).To(BeNil())
+			Expect(queues).To(HaveLen(0))
+		})
+
+		It("should return an error when the backend does not support it", func() {
+			testServer.Close()
+			impl, _ := server.NewJobTrackerImpl(pollingTracker{
+				JobTracker: simpletracker.New("drmaa2ostestjobsession")})
+			testServer = httptest.NewServer(genserver.Handler(impl))
+			var err error
+			client, err = New("clientdrmaa2ostestjobsession", ClientTrackerParams{
+				Server: testServer.URL,
+			})
+			Expect(err).To(BeNil())
+
+			jobid, err := client.AddJob(drmaa2interface.JobTemplate{
+				RemoteCommand: "/bin/sleep",
+				Args:          []string{"0"},
+			})
+			Expect(err).To(BeNil())
+			_, err = client.JobTemplate(jobid)
+			Expect(err).To(MatchError(ErrNotImplemented))
+			_, err = client.GetAllJobIDs(nil)
+			Expect(err).To(MatchError(ErrNotImplemented))
+			_, err = client.GetAllMachines(nil)
+			Expect(err).To(MatchError(ErrNotImplemented))
+			_, err = client.GetAllQueueNames(nil)
+			Expect(err).To(MatchError(ErrNotImplemented))
+			_, err = client.JobInfoFromMonitor(jobid)
+			Expect(err).To(MatchError(ErrNotImplemented))
+		})
+
+		It("should open a monitoring session of a remote session manager", func() {
+			dbpath := filepath.Join(GinkgoT().TempDir(), "remote.db")
+			sm, err := drmaa2os.NewRemoteSessionManager(ClientTrackerParams{
+				Server: testServer.URL,
+			}, dbpath)
+			Expect(err).To(BeNil())
+
+			ms, err := sm.OpenMonitoringSession("")
+			Expect(err).To(BeNil())
+			defer ms.CloseMonitoringSession()
+			machines, err := ms.GetAllMachines(nil)
+			Expect(err).To(BeNil())
+			Expect(machines).To(HaveLen(1))
+
+			js, err := sm.CreateJobSession("remotejobsession", "")
+			Expect(err).To(BeNil())
+			defer sm.DestroyJobSession("remotejobsession")
+			job, err := js.RunJob(drmaa2interface.JobTemplate{
+				RemoteCommand: "/bin/sleep",
+				Args:          []string{"0"},
+			})
+			Expect(err).To(BeNil())
+			jt, err := job.GetJobTemplate()
+			Expect(err).To(BeNil())
+			Expect(jt.RemoteCommand).To(Equal("/bin/sleep"))
+		})
+
+	})
+
 	Context("extensions", func() {
 
 		It("should convert JobTemplate with extensions", func() {
@@ -0,0 +1,55 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/dgruber/drmaa2interface"
+	genclient "github.com/dgruber/drmaa2os/pkg/jobtracker/remote/client/generated"
+)
+
+// RegisterEventNotification implements the jobtracker.EventNotifier
+// interface by consuming the stream of job events (Server-Sent Events)
+// of the server. The channel is closed when the stream ends. An error
+// is returned when the server does not stream events.
+func (c *ClientJobTracker) RegisterEventNotification() (drmaa2interface.EventChannel, func(), error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	resp, err := c.stream.Events(ctx, &genclient.EventsParams{})
+	if err != nil {
+		cancel()
+		return nil, nil, fmt.Errorf("failed requesting job events from remote: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		cancel()
+		return nil, nil, fmt.Errorf("remote does not stream job events: %s", resp.Status)
+	}
+
+	ch := make(chan drmaa2interface.Notification, 128)
+	go func() {
+		defer close(ch)
+		defer resp.Body.Close()
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			data, isData := strings.CutPrefix(scanner.Text(), "data:")
+			if !isData {
+				// event type, comments, and event separators
+				continue
+			}
+			var event genclient.JobEvent
+			if err := json.Unmarshal([]byte(strings.TrimSpace(data)), &event); err != nil {
+				continue
+			}
+			select {
+			case ch <- ConvertJobEventToDRMAA2(event, c.jobSession):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch, cancel, nil
+}
@@ -1,6 +1,6 @@
 // Package genclient provides primitives to interact with the openapi HTTP API.
 //
-// Code generated by github.com/deepmap/oapi-codegen version v1.8.1 DO NOT EDIT.
+// Code generated by github.com/deepmap/oapi-codegen version v1.8.2 DO NOT EDIT.
 package genclient
 
 import (
@@ -23,6 +23,15 @@ import (
 	"github.com/pkg/errors"
 )
 
+// Defines values for JobEventEvent.
+const (
+	JobEventEventAttributeChange JobEventEvent = "attributeChange"
+
+	JobEventEventMigrated JobEventEvent = "migrated"
+
+	JobEventEventNewState JobEventEvent = "newState"
+)
+
 // Defines values for JobState.
 const (
 	JobStateDone JobState = "done"
@@ -65,9 +74,26 @@ type AddJobOutput struct {
 // error string
 type Error string
 
+// JobEvent defines model for JobEvent.
+type JobEvent struct {
+	Event    JobEventEvent `json:"event"`
+	JobID    JobID         `json:"jobID"`
+	JobState JobState      `json:"jobState"`
+}
+
+// JobEventEvent defines model for JobEvent.Event.
+type JobEventEvent string
+
 // JobID defines model for JobID.
 type JobID string
 
+// JobIDsOutput defines model for JobIDsOutput.
+type JobIDsOutput struct {
+	// error string
+	Error  *Error  `json:"error"`
+	JobIDs []JobID `json:"jobIDs"`
+}
+
 // JobInfo defines model for JobInfo.
 type JobInfo struct {
 	AllocatedMachines []string           `json:"allocatedMachines"`
@@ -176,6 +202,66 @@ type JobTemplate_StageOutFiles struct {
 	AdditionalProperties map[string]string `json:"-"`
 }
 
+// JobTemplateOutput defines model for JobTemplateOutput.
+type JobTemplateOutput struct {
+	// Embedded struct due to allOf(#/components/schemas/JobTemplate)
+	JobTemplate `yaml:",inline"`
+	// Embedded struct due to allOf(#/components/schemas/Error)
+	Error `yaml:",inline"`
+}
+
+// DRMAA2 machine definition
+type Machine struct {
+	Architecture   string             `json:"architecture"`
+	Available      bool               `json:"available"`
+	CoresPerSocket int64              `json:"coresPerSocket"`
+	Extension      *Machine_Extension `json:"extension,omitempty"`
+	Load           float64            `json:"load"`
+	Name           string             `json:"name"`
+	Os             string             `json:"os"`
+	OsVersion      Version            `json:"osVersion"`
+	PhysicalMemory int64              `json:"physicalMemory"`
+	Sockets        int64              `json:"sockets"`
+	ThreadsPerCore int64              `json:"threadsPerCore"`
+	VirtualMemory  int64              `json:"virtualMemory"`
+}
+
+// Machine_Extension defines model for Machine.Extension.
+type Machine_Extension struct {
+	AdditionalProperties map[string]string `json:"-"`
+}
+
+// MachinesOutput defines model for MachinesOutput.
+type MachinesOutput struct {
+	// error string
+	Error    *Error    `json:"error"`
+	Machines []Machine `json:"machines"`
+}
+
+// QueueNamesOutput defines model for QueueNamesOutput.
+type QueueNamesOutput struct {
+	// error string
+	Error      *Error   `json:"error"`
+	QueueNames []string `json:"queueNames"`
+}
+
+// Version defines model for Version.
+type Version struct {
+	Major string `json:"major"`
+	Minor string `json:"minor"`
+}
+
+// WaitOutput defines model for WaitOutput.
+type WaitOutput struct {
+	// error string
+	Error       *Error      `json:"error"`
+	JobState    JobState    `json:"jobState"`
+	JobSubState JobSubState `json:"jobSubState"`
+
+	// true when the job is not in any of the given states
+	Timeout bool `json:"timeout"`
+}
+
 // AddArrayJobJSONBody defines parameters for AddArrayJob.
 type AddArrayJobJSONBody struct {
 	// Embedded struct due to allOf(#/components/schemas/JobTemplate)
@@ -192,14 +278,18 @@ type AddJobJSONBody JobTemplate
 
 // DeleteJobParams defines parameters for DeleteJob.
 type DeleteJobParams struct {
-
 	// ID of the job to manipulate
 	JobID string `json:"jobID"`
 }
 
+// EventsParams defines parameters for Events.
+type EventsParams struct {
+	// streams only the events of the job with the given ID
+	JobID *string `json:"jobID,omitempty"`
+}
+
 // JobControlParams defines parameters for JobControl.
 type JobControlParams struct {
-
 	// ID of the job to manipulate
 	JobID string `json:"jobID"`
 
@@ -212,21 +302,24 @@ type JobControlParamsAction string
 
 // JobInfoParams defines parameters for JobInfo.
 type JobInfoParams struct {
-
 	// ID if the job for which the JobInfo should be returned
 	JobID string `json:"jobID"`
 }
 
 // JobStateParams defines parameters for JobState.
 type JobStateParams struct {
-
 	// job ID the current job state should be queried for
 	JobID string `json:"jobID"`
 }
 
+// JobTemplateParams defines parameters for JobTemplate.
+type JobTemplateParams struct {
+	// ID of the job for which the job template should be returned
+	JobID string `json:"jobID"`
+}
+
 // ListArrayJobsParams defines parameters for ListArrayJobs.
 type ListArrayJobsParams struct {
-
 	// array job ID
 	ArrayJobID string `json:"arrayJobID"`
 
@@ -239,7 +332,6 @@ type ListArrayJobsParams struct {
 
 // ListJobsParams defines parameters for ListJobs.
 type ListJobsParams struct {
-
 	// number of records to skip for pagination
 	Skip *int32 `json:"skip,omitempty"`
 
@@ -247,6 +339,36 @@ type ListJobsParams struct {
 	Limit *int32 `json:"limit,omitempty"`
 }
 
+// JobInfoFromMonitorParams defines parameters for JobInfoFromMonitor.
+type JobInfoFromMonitorParams struct {
+	// ID of the job for which the JobInfo should be returned
+	JobID string `json:"jobID"`
+}
+
+// GetAllMachinesParams defines parameters for GetAllMachines.
+type GetAllMachinesParams struct {
+	// names of the machines to return
+	Name *[]string `json:"name,omitempty"`
+}
+
+// GetAllQueueNamesParams defines parameters for GetAllQueueNames.
+type GetAllQueueNamesParams struct {
+	// names of the queues to return
+	Name *[]string `json:"name,omitempty"`
+}
+
+// WaitParams defines parameters for Wait.
+type WaitParams struct {
+	// ID of the job to wait for
+	JobID string `json:"jobID"`
+
+	// job states to wait for
+	State []JobState `json:"state"`
+
+	// maximum time to wait in seconds (0 returns immediately)
+	Timeout *float64 `json:"timeout,omitempty"`
+}
+
 // AddArrayJobJSONRequestBody defines body for AddArrayJob for application/json ContentType.
 type AddArrayJobJSONRequestBody AddArrayJobJSONBody
 
@@ -571,6 +693,59 @@ func (a JobTemplate_StageOutFiles) MarshalJSON() ([]byte, error) {
 	return json.Marshal(object)
 }
 
+// Getter for additional properties for Machine_Extension. Returns the specified
+// element and whether it was found
+func (a Machine_Extension) Get(fieldName string) (value string, found bool) {
+	if a.AdditionalProperties != nil {
+		value, found = a.AdditionalProperties[fieldName]
+	}
+	return
+}
+
+// Setter for additional properties for Machine_Extension
+func (a *Machine_Extension) Set(fieldName string, value string) {
+	if a.AdditionalProperties == nil {
+		a.AdditionalProperties = make(map[string]string)
+	}
+	a.AdditionalProperties[fieldName] = value
+}
+
+// Override default JSON handling for Machine_Extension to handle AdditionalProperties
+func (a *Machine_Extension) UnmarshalJSON(b []byte) error {
+	object := make(map[string]json.RawMessage)
+	err := json.Unmarshal(b, &object)
+	if err != nil {
+		return err
+	}
+
+	if len(object) != 0 {
+		a.AdditionalProperties = make(map[string]string)
+		for fieldName, fieldBuf := range object {
+			var fieldVal string
+			err := json.Unmarshal(fieldBuf, &fieldVal)
+			if err != nil {
+				return errors.Wrap(err, fmt.Sprintf("error unmarshaling field %s", fieldName))
+			}
+			a.AdditionalProperties[fieldName] = fieldVal
+		}
+	}
+	return nil
+}
+
+// Override default JSON handling for Machine_Extension to handle AdditionalProperties
+func (a Machine_Extension) MarshalJSON() ([]byte, error) {
+	var err error
+	object := make(map[string]json.RawMessage)
+
+	for fieldName, field := range a.AdditionalProperties {
+		object[fieldName], err = json.Marshal(field)
+		if err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf("error marshaling '%s'", fieldName))
+		}
+	}
+	return json.Marshal(object)
+}
+
 // RequestEditorFn  is the function signature for the RequestEditor callback function
 type RequestEditorFn func(ctx context.Context, req *http.Request) error
 
@@ -644,12 +819,12 @@ func WithRequestEditorFn(fn RequestEditorFn) ClientOption {
 
 // The interface specification for the client above.
 type ClientInterface interface {
-	// AddArrayJob request  with any body
+	// AddArrayJob request with any body
 	AddArrayJobWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
 
 	AddArrayJob(ctx context.Context, body AddArrayJobJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
 
-	// AddJob request  with any body
+	// AddJob request with any body
 	AddJobWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
 
 	AddJob(ctx context.Context, body AddJobJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
@@ -657,6 +832,9 @@ type ClientInterface interface {
 	// DeleteJob request
 	DeleteJob(ctx context.Context, params *DeleteJobParams, reqEditors ...RequestEditorFn) (*http.Response, error)
 
+	// Events request
+	Events(ctx context.Context, params *EventsParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
 	// JobControl request
 	JobControl(ctx context.Context, params *JobControlParams, reqEditors ...RequestEditorFn) (*http.Response, error)
 
@@ -666,6 +844,9 @@ type ClientInterface interface {
 	// JobState request
 	JobState(ctx context.Context, params *JobStateParams, reqEditors ...RequestEditorFn) (*http.Response, error)
 
+	// JobTemplate request
+	JobTemplate(ctx context.Context, params *JobTemplateParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
 	// ListArrayJobs request
 	ListArrayJobs(ctx context.Context, params *ListArrayJobsParams, reqEditors ...RequestEditorFn) (*http.Response, error)
 
@@ -674,6 +855,21 @@ type ClientInterface interface {
 
 	// ListJobs request
 	ListJobs(ctx context.Context, params *ListJobsParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetAllJobIDs request
+	GetAllJobIDs(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// JobInfoFromMonitor request
+	JobInfoFromMonitor(ctx context.Context, params *JobInfoFromMonitorParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetAllMachines request
+	GetAllMachines(ctx context.Context, params *GetAllMachinesParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetAllQueueNames request
+	GetAllQueueNames(ctx context.Context, params *GetAllQueueNamesParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// Wait request
+	Wait(ctx context.Context, params *WaitParams, reqEditors ...RequestEditorFn) (*http.Response, error)
 }
 
 func (c *Client) AddArrayJobWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
@@ -736,6 +932,18 @@ func (c *Client) DeleteJob(ctx context.Context, params *DeleteJobParams, reqEdit
 	return c.Client.Do(req)
 }
 
+func (c *Client) Events(ctx context.Context, params *EventsParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewEventsRequest(c.Server, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
 func (c *Client) JobControl(ctx context.Context, params *JobControlParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
 	req, err := NewJobControlRequest(c.Server, params)
 	if err != nil {
@@ -772,6 +980,18 @@ func (c *Client) JobState(ctx context.Context, params *JobStateParams, reqEditor
 	return c.Client.Do(req)
 }
 
+func (c *Client) JobTemplate(ctx context.Context, params *JobTemplateParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewJobTemplateRequest(c.Server, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
 func (c *Client) ListArrayJobs(ctx context.Context, params *ListArrayJobsParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
 	req, err := NewListArrayJobsRequest(c.Server, params)
 	if err != nil {
@@ -808,6 +1028,66 @@ func (c *Client) ListJobs(ctx context.Context, params *ListJobsParams, reqEditor
 	return c.Client.Do(req)
 }
 
+func (c *Client) GetAllJobIDs(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetAllJobIDsRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) JobInfoFromMonitor(ctx context.Context, params *JobInfoFromMonitorParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewJobInfoFromMonitorRequest(c.Server, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetAllMachines(ctx context.Context, params *GetAllMachinesParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetAllMachinesRequest(c.Server, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetAllQueueNames(ctx context.Context, params *GetAllQueueNamesParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetAllQueueNamesRequest(c.Server, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) Wait(ctx context.Context, params *WaitParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewWaitRequest(c.Server, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
 // NewAddArrayJobRequest calls the generic AddArrayJob builder with application/json body
 func NewAddArrayJobRequest(server string, body AddArrayJobJSONRequestBody) (*http.Request, error) {
 	var bodyReader io.Reader
@@ -830,13 +1110,13 @@ func NewAddArrayJobRequestWithBody(server string, contentType string, body io.Re
 
 	operationPath := fmt.Sprintf("/addarrayjob")
 	if operationPath[0] == '/' {
-		operationPath = operationPath[1:]
-	}
-	operationURL := url.URL{
-		Path: operationPath,
+		operationPath = "." + operationPath
 	}
 
-	queryURL := serverURL.ResolveReference(&operationURL)
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
 
 	req, err := http.NewRequest("POST", queryURL.String(), body)
 	if err != nil {
@@ -870,13 +1150,13 @@ func NewAddJobRequestWithBody(server string, contentType string, body io.Reader)
 
 	operationPath := fmt.Sprintf("/addjob")
 	if operationPath[0] == '/' {
-		operationPath = operationPath[1:]
-	}
-	operationURL := url.URL{
-		Path: operationPath,
+		operationPath = "." + operationPath
 	}
 
-	queryURL := serverURL.ResolveReference(&operationURL)
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
 
 	req, err := http.NewRequest("POST", queryURL.String(), body)
 	if err != nil {
@@ -899,13 +1179,13 @@ func NewDeleteJobRequest(server string, params *DeleteJobParams) (*http.Request,
 
 	operationPath := fmt.Sprintf("/deletejob")
 	if operationPath[0] == '/' {
-		operationPath = operationPath[1:]
-	}
-	operationURL := url.URL{
-		Path: operationPath,
+		operationPath = "." + operationPath
 	}
 
-	queryURL := serverURL.ResolveReference(&operationURL)
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
 
 	queryValues := queryURL.Query()
 
@@ -931,6 +1211,53 @@ func NewDeleteJobRequest(server string, params *DeleteJobParams) (*http.Request,
 	return req, nil
 }
 
+// NewEventsRequest generates requests for Events
+func NewEventsRequest(server string, params *EventsParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/events")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	queryValues := queryURL.Query()
+
+	if params.JobID != nil {
+
+		if queryFrag, err := runtime.StyleParamWithLocation("form", true, "jobID", runtime.ParamLocationQuery, *params.JobID); err != nil {
+			return nil, err
+		} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+			return nil, err
+		} else {
+			for k, v := range parsed {
+				for _, v2 := range v {
+					queryValues.Add(k, v2)
+				}
+			}
+		}
+
+	}
+
+	queryURL.RawQuery = queryValues.Encode()
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
 // NewJobControlRequest generates requests for JobControl
 func NewJobControlRequest(server string, params *JobControlParams) (*http.Request, error) {
 	var err error
@@ -942,13 +1269,13 @@ func NewJobControlRequest(server string, params *JobControlParams) (*http.Reques
 
 	operationPath := fmt.Sprintf("/jobcontrol")
 	if operationPath[0] == '/' {
-		operationPath = operationPath[1:]
-	}
-	operationURL := url.URL{
-		Path: operationPath,
+		operationPath = "." + operationPath
 	}
 
-	queryURL := serverURL.ResolveReference(&operationURL)
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
 
 	queryValues := queryURL.Query()
 
@@ -997,13 +1324,13 @@ func NewJobInfoRequest(server string, params *JobInfoParams) (*http.Request, err
 
 	operationPath := fmt.Sprintf("/jobinfo")
 	if operationPath[0] == '/' {
-		operationPath = operationPath[1:]
-	}
-	operationURL := url.URL{
-		Path: operationPath,
+		operationPath = "." + operationPath
 	}
 
-	queryURL := serverURL.ResolveReference(&operationURL)
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
 
 	queryValues := queryURL.Query()
 
@@ -1040,13 +1367,13 @@ func NewJobStateRequest(server string, params *JobStateParams) (*http.Request, e
 
 	operationPath := fmt.Sprintf("/jobstate")
 	if operationPath[0] == '/' {
-		operationPath = operationPath[1:]
-	}
-	operationURL := url.URL{
-		Path: operationPath,
+		operationPath = "." + operationPath
 	}
 
-	queryURL := serverURL.ResolveReference(&operationURL)
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
 
 	queryValues := queryURL.Query()
 
@@ -1072,8 +1399,8 @@ func NewJobStateRequest(server string, params *JobStateParams) (*http.Request, e
 	return req, nil
 }
 
-// NewListArrayJobsRequest generates requests for ListArrayJobs
-func NewListArrayJobsRequest(server string, params *ListArrayJobsParams) (*http.Request, error) {
+// NewJobTemplateRequest generates requests for JobTemplate
+func NewJobTemplateRequest(server string, params *JobTemplateParams) (*http.Request, error) {
 	var err error
 
 	serverURL, err := url.Parse(server)
@@ -1081,19 +1408,19 @@ func NewListArrayJobsRequest(server string, params *ListArrayJobsParams) (*http.
 		return nil, err
 	}
 
-	operationPath := fmt.Sprintf("/listarrayjobs")
+	operationPath := fmt.Sprintf("/jobtemplate")
 	if operationPath[0] == '/' {
-		operationPath = operationPath[1:]
-	}
-	operationURL := url.URL{
-		Path: operationPath,
+		operationPath = "." + operationPath
 	}
 
-	queryURL := serverURL.ResolveReference(&operationURL)
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
 
 	queryValues := queryURL.Query()
 
-	if queryFrag, err := runtime.StyleParamWithLocation("form", true, "arrayJobID", runtime.ParamLocationQuery, params.ArrayJobID); err != nil {
+	if queryFrag, err := runtime.StyleParamWithLocation("form", true, "jobID", runtime.ParamLocationQuery, params.JobID); err != nil {
 		return nil, err
 	} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
 		return nil, err
@@ -1105,16 +1432,59 @@ func NewListArrayJobsRequest(server string, params *ListArrayJobsParams) (*http.
 		}
 	}
 
-	if params.Skip != nil {
+	queryURL.RawQuery = queryValues.Encode()
 
-		if queryFrag, err := runtime.StyleParamWithLocation("form", true, "skip", runtime.ParamLocationQuery, *params.Skip); err != nil {
-			return nil, err
-		} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
-			return nil, err
-		} else {
-			for k, v := range parsed {
-				for _, v2 := range v {
-					queryValues.Add(k, v2)
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewListArrayJobsRequest generates requests for ListArrayJobs
+func NewListArrayJobsRequest(server string, params *ListArrayJobsParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/listarrayjobs")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	queryValues := queryURL.Query()
+
+	if queryFrag, err := runtime.StyleParamWithLocation("form", true, "arrayJobID", runtime.ParamLocationQuery, params.ArrayJobID); err != nil {
+		return nil, err
+	} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+		return nil, err
+	} else {
+		for k, v := range parsed {
+			for _, v2 := range v {
+				queryValues.Add(k, v2)
+			}
+		}
+	}
+
+	if params.Skip != nil {
+
+		if queryFrag, err := runtime.StyleParamWithLocation("form", true, "skip", runtime.ParamLocationQuery, *params.Skip); err != nil {
+			return nil, err
+		} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+			return nil, err
+		} else {
+			for k, v := range parsed {
+				for _, v2 := range v {
+					queryValues.Add(k, v2)
 				}
 			}
 		}
@@ -1158,13 +1528,13 @@ func NewListJobCategoriesRequest(server string) (*http.Request, error) {
 
 	operationPath := fmt.Sprintf("/listjobcategories")
 	if operationPath[0] == '/' {
-		operationPath = operationPath[1:]
-	}
-	operationURL := url.URL{
-		Path: operationPath,
+		operationPath = "." + operationPath
 	}
 
-	queryURL := serverURL.ResolveReference(&operationURL)
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
 
 	req, err := http.NewRequest("GET", queryURL.String(), nil)
 	if err != nil {
@@ -1185,13 +1555,13 @@ func NewListJobsRequest(server string, params *ListJobsParams) (*http.Request, e
 
 	operationPath := fmt.Sprintf("/listjobs")
 	if operationPath[0] == '/' {
-		operationPath = operationPath[1:]
-	}
-	operationURL := url.URL{
-		Path: operationPath,
+		operationPath = "." + operationPath
 	}
 
-	queryURL := serverURL.ResolveReference(&operationURL)
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
 
 	queryValues := queryURL.Query()
 
@@ -1237,6 +1607,241 @@ func NewListJobsRequest(server string, params *ListJobsParams) (*http.Request, e
 	return req, nil
 }
 
+// NewGetAllJobIDsRequest generates requests for GetAllJobIDs
+func NewGetAllJobIDsRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/monitor/jobids")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewJobInfoFromMonitorRequest generates requests for JobInfoFromMonitor
+func NewJobInfoFromMonitorRequest(server string, params *JobInfoFromMonitorParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/monitor/jobinfo")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	queryValues := queryURL.Query()
+
+	if queryFrag, err := runtime.StyleParamWithLocation("form", true, "jobID", runtime.ParamLocationQuery, params.JobID); err != nil {
+		return nil, err
+	} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+		return nil, err
+	} else {
+		for k, v := range parsed {
+			for _, v2 := range v {
+				queryValues.Add(k, v2)
+			}
+		}
+	}
+
+	queryURL.RawQuery = queryValues.Encode()
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetAllMachinesRequest generates requests for GetAllMachines
+func NewGetAllMachinesRequest(server string, params *GetAllMachinesParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/monitor/machines")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	queryValues := queryURL.Query()
+
+	if params.Name != nil {
+
+		if queryFrag, err := runtime.StyleParamWithLocation("form", true, "name", runtime.ParamLocationQuery, *params.Name); err != nil {
+			return nil, err
+		} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+			return nil, err
+		} else {
+			for k, v := range parsed {
+				for _, v2 := range v {
+					queryValues.Add(k, v2)
+				}
+			}
+		}
+
+	}
+
+	queryURL.RawQuery = queryValues.Encode()
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetAllQueueNamesRequest generates requests for GetAllQueueNames
+func NewGetAllQueueNamesRequest(server string, params *GetAllQueueNamesParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/monitor/queuenames")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	queryValues := queryURL.Query()
+
+	if params.Name != nil {
+
+		if queryFrag, err := runtime.StyleParamWithLocation("form", true, "name", runtime.ParamLocationQuery, *params.Name); err != nil {
+			return nil, err
+		} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+			return nil, err
+		} else {
+			for k, v := range parsed {
+				for _, v2 := range v {
+					queryValues.Add(k, v2)
+				}
+			}
+		}
+
+	}
+
+	queryURL.RawQuery = queryValues.Encode()
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewWaitRequest generates requests for Wait
+func NewWaitRequest(server string, params *WaitParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/wait")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	queryValues := queryURL.Query()
+
+	if queryFrag, err := runtime.StyleParamWithLocation("form", true, "jobID", runtime.ParamLocationQuery, params.JobID); err != nil {
+		return nil, err
+	} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+		return nil, err
+	} else {
+		for k, v := range parsed {
+			for _, v2 := range v {
+				queryValues.Add(k, v2)
+			}
+		}
+	}
+
+	if queryFrag, err := runtime.StyleParamWithLocation("form", true, "state", runtime.ParamLocationQuery, params.State); err != nil {
+		return nil, err
+	} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+		return nil, err
+	} else {
+		for k, v := range parsed {
+			for _, v2 := range v {
+				queryValues.Add(k, v2)
+			}
+		}
+	}
+
+	if params.Timeout != nil {
+
+		if queryFrag, err := runtime.StyleParamWithLocation("form", true, "timeout", runtime.ParamLocationQuery, *params.Timeout); err != nil {
+			return nil, err
+		} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+			return nil, err
+		} else {
+			for k, v := range parsed {
+				for _, v2 := range v {
+					queryValues.Add(k, v2)
+				}
+			}
+		}
+
+	}
+
+	queryURL.RawQuery = queryValues.Encode()
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
 func (c *Client) applyEditors(ctx context.Context, req *http.Request, additionalEditors []RequestEditorFn) error {
 	for _, r := range c.RequestEditors {
 		if err := r(ctx, req); err != nil {
@@ -1280,12 +1885,12 @@ func WithBaseURL(baseURL string) ClientOption {
 
 // ClientWithResponsesInterface is the interface specification for the client with responses above.
 type ClientWithResponsesInterface interface {
-	// AddArrayJob request  with any body
+	// AddArrayJob request with any body
 	AddArrayJobWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*AddArrayJobResponse, error)
 
 	AddArrayJobWithResponse(ctx context.Context, body AddArrayJobJSONRequestBody, reqEditors ...RequestEditorFn) (*AddArrayJobResponse, error)
 
-	// AddJob request  with any body
+	// AddJob request with any body
 	AddJobWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*AddJobResponse, error)
 
 	AddJobWithResponse(ctx context.Context, body AddJobJSONRequestBody, reqEditors ...RequestEditorFn) (*AddJobResponse, error)
@@ -1293,6 +1898,9 @@ type ClientWithResponsesInterface interface {
 	// DeleteJob request
 	DeleteJobWithResponse(ctx context.Context, params *DeleteJobParams, reqEditors ...RequestEditorFn) (*DeleteJobResponse, error)
 
+	// Events request
+	EventsWithResponse(ctx context.Context, params *EventsParams, reqEditors ...RequestEditorFn) (*EventsResponse, error)
+
 	// JobControl request
 	JobControlWithResponse(ctx context.Context, params *JobControlParams, reqEditors ...RequestEditorFn) (*JobControlResponse, error)
 
@@ -1302,24 +1910,195 @@ type ClientWithResponsesInterface interface {
 	// JobState request
 	JobStateWithResponse(ctx context.Context, params *JobStateParams, reqEditors ...RequestEditorFn) (*JobStateResponse, error)
 
-	// ListArrayJobs request
-	ListArrayJobsWithResponse(ctx context.Context, params *ListArrayJobsParams, reqEditors ...RequestEditorFn) (*ListArrayJobsResponse, error)
+	// JobTemplate request
+	JobTemplateWithResponse(ctx context.Context, params *JobTemplateParams, reqEditors ...RequestEditorFn) (*JobTemplateResponse, error)
+
+	// ListArrayJobs request
+	ListArrayJobsWithResponse(ctx context.Context, params *ListArrayJobsParams, reqEditors ...RequestEditorFn) (*ListArrayJobsResponse, error)
+
+	// ListJobCategories request
+	ListJobCategoriesWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*ListJobCategoriesResponse, error)
+
+	// ListJobs request
+	ListJobsWithResponse(ctx context.Context, params *ListJobsParams, reqEditors ...RequestEditorFn) (*ListJobsResponse, error)
+
+	// GetAllJobIDs request
+	GetAllJobIDsWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetAllJobIDsResponse, error)
+
+	// JobInfoFromMonitor request
+	JobInfoFromMonitorWithResponse(ctx context.Context, params *JobInfoFromMonitorParams, reqEditors ...RequestEditorFn) (*JobInfoFromMonitorResponse, error)
+
+	// GetAllMachines request
+	GetAllMachinesWithResponse(ctx context.Context, params *GetAllMachinesParams, reqEditors ...RequestEditorFn) (*GetAllMachinesResponse, error)
+
+	// GetAllQueueNames request
+	GetAllQueueNamesWithResponse(ctx context.Context, params *GetAllQueueNamesParams, reqEditors ...RequestEditorFn) (*GetAllQueueNamesResponse, error)
+
+	// Wait request
+	WaitWithResponse(ctx context.Context, params *WaitParams, reqEditors ...RequestEditorFn) (*WaitResponse, error)
+}
+
+type AddArrayJobResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *AddArrayJobOutput
+}
+
+// Status returns HTTPResponse.Status
+func (r AddArrayJobResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r AddArrayJobResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type AddJobResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *AddJobOutput
+}
+
+// Status returns HTTPResponse.Status
+func (r AddJobResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r AddJobResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type DeleteJobResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r DeleteJobResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r DeleteJobResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type EventsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+}
+
+// Status returns HTTPResponse.Status
+func (r EventsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r EventsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type JobControlResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r JobControlResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r JobControlResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type JobInfoResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *JobInfoOutput
+}
+
+// Status returns HTTPResponse.Status
+func (r JobInfoResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r JobInfoResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type JobStateResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *JobStateOutput
+}
 
-	// ListJobCategories request
-	ListJobCategoriesWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*ListJobCategoriesResponse, error)
+// Status returns HTTPResponse.Status
+func (r JobStateResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
 
-	// ListJobs request
-	ListJobsWithResponse(ctx context.Context, params *ListJobsParams, reqEditors ...RequestEditorFn) (*ListJobsResponse, error)
+// StatusCode returns HTTPResponse.StatusCode
+func (r JobStateResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
 }
 
-type AddArrayJobResponse struct {
+type JobTemplateResponse struct {
 	Body         []byte
 	HTTPResponse *http.Response
-	JSON200      *AddArrayJobOutput
+	JSON200      *JobTemplateOutput
 }
 
 // Status returns HTTPResponse.Status
-func (r AddArrayJobResponse) Status() string {
+func (r JobTemplateResponse) Status() string {
 	if r.HTTPResponse != nil {
 		return r.HTTPResponse.Status
 	}
@@ -1327,21 +2106,21 @@ func (r AddArrayJobResponse) Status() string {
 }
 
 // StatusCode returns HTTPResponse.StatusCode
-func (r AddArrayJobResponse) StatusCode() int {
+func (r JobTemplateResponse) StatusCode() int {
 	if r.HTTPResponse != nil {
 		return r.HTTPResponse.StatusCode
 	}
 	return 0
 }
 
-type AddJobResponse struct {
+type ListArrayJobsResponse struct {
 	Body         []byte
 	HTTPResponse *http.Response
-	JSON200      *AddJobOutput
+	JSON200      *[]JobID
 }
 
 // Status returns HTTPResponse.Status
-func (r AddJobResponse) Status() string {
+func (r ListArrayJobsResponse) Status() string {
 	if r.HTTPResponse != nil {
 		return r.HTTPResponse.Status
 	}
@@ -1349,21 +2128,21 @@ func (r AddJobResponse) Status() string {
 }
 
 // StatusCode returns HTTPResponse.StatusCode
-func (r AddJobResponse) StatusCode() int {
+func (r ListArrayJobsResponse) StatusCode() int {
 	if r.HTTPResponse != nil {
 		return r.HTTPResponse.StatusCode
 	}
 	return 0
 }
 
-type DeleteJobResponse struct {
+type ListJobCategoriesResponse struct {
 	Body         []byte
 	HTTPResponse *http.Response
-	JSON200      *Error
+	JSON200      *[]string
 }
 
 // Status returns HTTPResponse.Status
-func (r DeleteJobResponse) Status() string {
+func (r ListJobCategoriesResponse) Status() string {
 	if r.HTTPResponse != nil {
 		return r.HTTPResponse.Status
 	}
@@ -1371,21 +2150,21 @@ func (r DeleteJobResponse) Status() string {
 }
 
 // StatusCode returns HTTPResponse.StatusCode
-func (r DeleteJobResponse) StatusCode() int {
+func (r ListJobCategoriesResponse) StatusCode() int {
 	if r.HTTPResponse != nil {
 		return r.HTTPResponse.StatusCode
 	}
 	return 0
 }
 
-type JobControlResponse struct {
+type ListJobsResponse struct {
 	Body         []byte
 	HTTPResponse *http.Response
-	JSON200      *Error
+	JSON200      *[]JobID
 }
 
 // Status returns HTTPResponse.Status
-func (r JobControlResponse) Status() string {
+func (r ListJobsResponse) Status() string {
 	if r.HTTPResponse != nil {
 		return r.HTTPResponse.Status
 	}
@@ -1393,21 +2172,21 @@ func (r JobControlResponse) Status() string {
 }
 
 // StatusCode returns HTTPResponse.StatusCode
-func (r JobControlResponse) StatusCode() int {
+func (r ListJobsResponse) StatusCode() int {
 	if r.HTTPResponse != nil {
 		return r.HTTPResponse.StatusCode
 	}
 	return 0
 }
 
-type JobInfoResponse struct {
+type GetAllJobIDsResponse struct {
 	Body         []byte
 	HTTPResponse *http.Response
-	JSON200      *JobInfoOutput
+	JSON200      *JobIDsOutput
 }
 
 // Status returns HTTPResponse.Status
-func (r JobInfoResponse) Status() string {
+func (r GetAllJobIDsResponse) Status() string {
 	if r.HTTPResponse != nil {
 		return r.HTTPResponse.Status
 	}
@@ -1415,21 +2194,21 @@ func (r JobInfoResponse) Status() string {
 }
 
 // StatusCode returns HTTPResponse.StatusCode
-func (r JobInfoResponse) StatusCode() int {
+func (r GetAllJobIDsResponse) StatusCode() int {
 	if r.HTTPResponse != nil {
 		return r.HTTPResponse.StatusCode
 	}
 	return 0
 }
 
-type JobStateResponse struct {
+type JobInfoFromMonitorResponse struct {
 	Body         []byte
 	HTTPResponse *http.Response
-	JSON200      *JobStateOutput
+	JSON200      *JobInfoOutput
 }
 
 // Status returns HTTPResponse.Status
-func (r JobStateResponse) Status() string {
+func (r JobInfoFromMonitorResponse) Status() string {
 	if r.HTTPResponse != nil {
 		return r.HTTPResponse.Status
 	}
@@ -1437,21 +2216,21 @@ func (r JobStateResponse) Status() string {
 }
 
 // StatusCode returns HTTPResponse.StatusCode
-func (r JobStateResponse) StatusCode() int {
+func (r JobInfoFromMonitorResponse) StatusCode() int {
 	if r.HTTPResponse != nil {
 		return r.HTTPResponse.StatusCode
 	}
 	return 0
 }
 
-type ListArrayJobsResponse struct {
+type GetAllMachinesResponse struct {
 	Body         []byte
 	HTTPResponse *http.Response
-	JSON200      *[]JobID
+	JSON200      *MachinesOutput
 }
 
 // Status returns HTTPResponse.Status
-func (r ListArrayJobsResponse) Status() string {
+func (r GetAllMachinesResponse) Status() string {
 	if r.HTTPResponse != nil {
 		return r.HTTPResponse.Status
 	}
@@ -1459,21 +2238,21 @@ func (r ListArrayJobsResponse) Status() string {
 }
 
 // StatusCode returns HTTPResponse.StatusCode
-func (r ListArrayJobsResponse) StatusCode() int {
+func (r GetAllMachinesResponse) StatusCode() int {
 	if r.HTTPResponse != nil {
 		return r.HTTPResponse.StatusCode
 	}
 	return 0
 }
 
-type ListJobCategoriesResponse struct {
+type GetAllQueueNamesResponse struct {
 	Body         []byte
 	HTTPResponse *http.Response
-	JSON200      *[]string
+	JSON200      *QueueNamesOutput
 }
 
 // Status returns HTTPResponse.Status
-func (r ListJobCategoriesResponse) Status() string {
+func (r GetAllQueueNamesResponse) Status() string {
 	if r.HTTPResponse != nil {
 		return r.HTTPResponse.Status
 	}
@@ -1481,21 +2260,21 @@ func (r ListJobCategoriesResponse) Status() string {
 }
 
 // StatusCode returns HTTPResponse.StatusCode
-func (r ListJobCategoriesResponse) StatusCode() int {
+func (r GetAllQueueNamesResponse) StatusCode() int {
 	if r.HTTPResponse != nil {
 		return r.HTTPResponse.StatusCode
 	}
 	return 0
 }
 
-type ListJobsResponse struct {
+type WaitResponse struct {
 	Body         []byte
 	HTTPResponse *http.Response
-	JSON200      *[]JobID
+	JSON200      *WaitOutput
 }
 
 // Status returns HTTPResponse.Status
-func (r ListJobsResponse) Status() string {
+func (r WaitResponse) Status() string {
 	if r.HTTPResponse != nil {
 		return r.HTTPResponse.Status
 	}
@@ -1503,7 +2282,7 @@ func (r ListJobsResponse) Status() string {
 }
 
 // StatusCode returns HTTPResponse.StatusCode
-func (r ListJobsResponse) StatusCode() int {
+func (r WaitResponse) StatusCode() int {
 	if r.HTTPResponse != nil {
 		return r.HTTPResponse.StatusCode
 	}
@@ -1553,6 +2332,15 @@ func (c *ClientWithResponses) DeleteJobWithResponse(ctx context.Context, params
 	return ParseDeleteJobResponse(rsp)
 }
 
+// EventsWithResponse request returning *EventsResponse
+func (c *ClientWithResponses) EventsWithResponse(ctx context.Context, params *EventsParams, reqEditors ...RequestEditorFn) (*EventsResponse, error) {
+	rsp, err := c.Events(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseEventsResponse(rsp)
+}
+
 // JobControlWithResponse request returning *JobControlResponse
 func (c *ClientWithResponses) JobControlWithResponse(ctx context.Context, params *JobControlParams, reqEditors ...RequestEditorFn) (*JobControlResponse, error) {
 	rsp, err := c.JobControl(ctx, params, reqEditors...)
@@ -1580,6 +2368,15 @@ func (c *ClientWithResponses) JobStateWithResponse(ctx context.Context, params *
 	return ParseJobStateResponse(rsp)
 }
 
+// JobTemplateWithResponse request returning *JobTemplateResponse
+func (c *ClientWithResponses) JobTemplateWithResponse(ctx context.Context, params *JobTemplateParams, reqEditors ...RequestEditorFn) (*JobTemplateResponse, error) {
+	rsp, err := c.JobTemplate(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseJobTemplateResponse(rsp)
+}
+
 // ListArrayJobsWithResponse request returning *ListArrayJobsResponse
 func (c *ClientWithResponses) ListArrayJobsWithResponse(ctx context.Context, params *ListArrayJobsParams, reqEditors ...RequestEditorFn) (*ListArrayJobsResponse, error) {
 	rsp, err := c.ListArrayJobs(ctx, params, reqEditors...)
@@ -1607,6 +2404,51 @@ func (c *ClientWithResponses) ListJobsWithResponse(ctx context.Context, params *
 	return ParseListJobsResponse(rsp)
 }
 
+// GetAllJobIDsWithResponse request returning *GetAllJobIDsResponse
+func (c *ClientWithResponses) GetAllJobIDsWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetAllJobIDsResponse, error) {
+	rsp, err := c.GetAllJobIDs(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetAllJobIDsResponse(rsp)
+}
+
+// JobInfoFromMonitorWithResponse request returning *JobInfoFromMonitorResponse
+func (c *ClientWithResponses) JobInfoFromMonitorWithResponse(ctx context.Context, params *JobInfoFromMonitorParams, reqEditors ...RequestEditorFn) (*JobInfoFromMonitorResponse, error) {
+	rsp, err := c.JobInfoFromMonitor(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseJobInfoFromMonitorResponse(rsp)
+}
+
+// GetAllMachinesWithResponse request returning *GetAllMachinesResponse
+func (c *ClientWithResponses) GetAllMachinesWithResponse(ctx context.Context, params *GetAllMachinesParams, reqEditors ...RequestEditorFn) (*GetAllMachinesResponse, error) {
+	rsp, err := c.GetAllMachines(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetAllMachinesResponse(rsp)
+}
+
+// GetAllQueueNamesWithResponse request returning *GetAllQueueNamesResponse
+func (c *ClientWithResponses) GetAllQueueNamesWithResponse(ctx context.Context, params *GetAllQueueNamesParams, reqEditors ...RequestEditorFn) (*GetAllQueueNamesResponse, error) {
+	rsp, err := c.GetAllQueueNames(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetAllQueueNamesResponse(rsp)
+}
+
+// WaitWithResponse request returning *WaitResponse
+func (c *ClientWithResponses) WaitWithResponse(ctx context.Context, params *WaitParams, reqEditors ...RequestEditorFn) (*WaitResponse, error) {
+	rsp, err := c.Wait(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseWaitResponse(rsp)
+}
+
 // ParseAddArrayJobResponse parses an HTTP response from a AddArrayJobWithResponse call
 func ParseAddArrayJobResponse(rsp *http.Response) (*AddArrayJobResponse, error) {
 	bodyBytes, err := ioutil.ReadAll(rsp.Body)
@@ -1685,6 +2527,22 @@ func ParseDeleteJobResponse(rsp *http.Response) (*DeleteJobResponse, error) {
 	return response, nil
 }
 
+// ParseEventsResponse parses an HTTP response from a EventsWithResponse call
+func ParseEventsResponse(rsp *http.Response) (*EventsResponse, error) {
+	bodyBytes, err := ioutil.ReadAll(rsp.Body)
+	defer rsp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &EventsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	return response, nil
+}
+
 // ParseJobControlResponse parses an HTTP response from a JobControlWithResponse call
 func ParseJobControlResponse(rsp *http.Response) (*JobControlResponse, error) {
 	bodyBytes, err := ioutil.ReadAll(rsp.Body)
@@ -1763,6 +2621,32 @@ func ParseJobStateResponse(rsp *http.Response) (*JobStateResponse, error) {
 	return response, nil
 }
 
+// ParseJobTemplateResponse parses an HTTP response from a JobTemplateWithResponse call
+func ParseJobTemplateResponse(rsp *http.Response) (*JobTemplateResponse, error) {
+	bodyBytes, err := ioutil.ReadAll(rsp.Body)
+	defer rsp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &JobTemplateResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest JobTemplateOutput
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	}
+
+	return response, nil
+}
+
 // ParseListArrayJobsResponse parses an HTTP response from a ListArrayJobsWithResponse call
 func ParseListArrayJobsResponse(rsp *http.Response) (*ListArrayJobsResponse, error) {
 	bodyBytes, err := ioutil.ReadAll(rsp.Body)
@@ -1841,60 +2725,209 @@ func ParseListJobsResponse(rsp *http.Response) (*ListJobsResponse, error) {
 	return response, nil
 }
 
+// ParseGetAllJobIDsResponse parses an HTTP response from a GetAllJobIDsWithResponse call
+func ParseGetAllJobIDsResponse(rsp *http.Response) (*GetAllJobIDsResponse, error) {
+	bodyBytes, err := ioutil.ReadAll(rsp.Body)
+	defer rsp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetAllJobIDsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest JobIDsOutput
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseJobInfoFromMonitorResponse parses an HTTP response from a JobInfoFromMonitorWithResponse call
+func ParseJobInfoFromMonitorResponse(rsp *http.Response) (*JobInfoFromMonitorResponse, error) {
+	bodyBytes, err := ioutil.ReadAll(rsp.Body)
+	defer rsp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &JobInfoFromMonitorResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest JobInfoOutput
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetAllMachinesResponse parses an HTTP response from a GetAllMachinesWithResponse call
+func ParseGetAllMachinesResponse(rsp *http.Response) (*GetAllMachinesResponse, error) {
+	bodyBytes, err := ioutil.ReadAll(rsp.Body)
+	defer rsp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetAllMachinesResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest MachinesOutput
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetAllQueueNamesResponse parses an HTTP response from a GetAllQueueNamesWithResponse call
+func ParseGetAllQueueNamesResponse(rsp *http.Response) (*GetAllQueueNamesResponse, error) {
+	bodyBytes, err := ioutil.ReadAll(rsp.Body)
+	defer rsp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetAllQueueNamesResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest QueueNamesOutput
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseWaitResponse parses an HTTP response from a WaitWithResponse call
+func ParseWaitResponse(rsp *http.Response) (*WaitResponse, error) {
+	bodyBytes, err := ioutil.ReadAll(rsp.Body)
+	defer rsp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &WaitResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest WaitOutput
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	}
+
+	return response, nil
+}
+
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/+RabW/cuBH+KwRboHeAsm/OXYoF+sGJfZdNEzuI3aJtEgQjaXZFWyJ1JOW1a/i/F0NS",
-	"WkmrtdfO3TVF/UmWhuRw5plnhsO95YkqSiVRWsPnt9wkGRbgHg/T9FBruHmj4tPKlpWll5Dnp0s+/3jL",
-	"/6hxyef8D+PNBOMwevxGxYsjfhfdL3WstdL87vNdRGv9Hsv4x/ktT9EkWpRWKMnnHOk1M1YLueIRl1We",
-	"Q5wjn1tdYcTtTYl8zsP3u4j7dee3HK+hKEmQX6h4Ojt4/sOPfIe8XCoaUWpVorYCTdimSsBi+g6STEj/",
-	"Ulgs3MPWPOEFkFfof5BSWfCbaCszpENSVueiwK7gdDbhEV8qXYDlcy6k/fH5ZrCQFleoaXQqTAk2yban",
-	"mE2mL55NXjybTc+nL+YHB/PZn//VnjMFi88sDRtQCq+FPbNgK9OZdBLxAq5FURV8Pvvhh4gXQvr/JkPK",
-	"4bVFaYIVIE0FWQTy9x1TD6wdVrvlgh4KlN6WX0yJiViK5EuBYCrtvvA5v775d8sJKr7AxNKLpZDCPGyZ",
-	"54+yjEgfga8LFZ+uJerukMqgng6J/1JhhSfQ1zcTq6zUQmlhb4aGmVxZ08PPIFiMBdub+8PfTk4WJz8P",
-	"TlvFZ9sDSHlWasSitJjuGFcIQ24PwdNTbTSdjKaj6cH9Yx902+xRbrOoCyHBCrk6EysJeXfus8XPf128",
-	"fTs0cg15nuQquXxylN5FXOMvldCY8vlHQlAnwIaU61BI7biWS6IBhhqyfAuCNU7aMOvvbsNGW67oUU0n",
-	"uj4PBF+g1qdkDmLkR+SON6qFU0lk9JFX0qDlEa9kit66Dqtu55uH15jTP7qS0icYU5kSZepEyGVBun4M",
-	"8qlyll2CyDGlzW8QsZlqKNc4NZ9gEb+9h0xCgjU+GsMMxrCxqiyFXDEhjQWZ4A59z7Eo8zC6m5iPPrw7",
-	"PJyxCxUzG4RYigSJgNheOk0SVUnCdz89V1L8UuFh6/sgM4JePTIFJyBTQZTwtAyeIqS5kFgH/Z5pswCR",
-	"P24hN+RUnlnQxKebIbFSOYJsyZwHmtgpRjHxHmy2I61+ZTL2Pv9ycnp0/OX1OQX7csmjzuu3hy+P3345",
-	"/sf58cnZ4vSEZiqVHUzOQpaV3anshYpfgcWV0je7vh/LK6GV9DXA03b07p9fjk/+zuf8lZKW5ono1en5",
-	"6+MP3Q+zwS1cqLhO1gMKCvmTyDs6tFxVeFAe6iTrxsPhh3ftHLKZMIw47SX6l2dHw9LXZ3VVsEcVWQj5",
-	"Prsx77AIBt9vzGOWUI72djq8qW/2m61TKW1NpvFDJaU/KQxZX2OhLL5SRQGyV86NYyHHJkcsh8yq0aC+",
-	"clnZE9mQhKp0gm9FIbxtngJMmE4mroobgp2xsMLFBl1PWWDsTTBO0VhXeSi5FDlOyU5g8Mfn86QJiR2y",
-	"M7KW3+yY/t+t62llv05ZqnTyIV1r1Ty4RsbCLi20fRyTu+rHHprXKt/BtmulL4VcHQmNiR3mqV7h14Vd",
-	"SGq9pTrg3eK5gVW7XFmnoK28MpRENgzWpuNOqLaTSpvU+qHQLSobamgRUSvEh5Jzn4PahNely7Y7e3m6",
-	"Fxt9+G1FZ9QtTLbL2DuXqHyDgAICEpdrpCMefgRSYM5+1lXs6utK53Ras7Y08/F4JWxWxaNEFeN05UTG",
-	"qS4AZsr4AmO7njo9Y1R1aUguUbPD9wtGVf7aMKtYARJWyGKqwKnuMqwyVMJdgRaqMiymQTJlRJJ6CQma",
-	"EVtY1pygDbMZtqdvBNlSq8J9bbQotSIDuBlShYZJ1ZrKya5B2O++ZwnkOVtnIslYURnLYtzIYcqUdMJJ",
-	"LmicESmy+IYpm6FmBYI0Ix7xXCQoDbYse1hCkiGbjSYds87H4/V6PQL3daT0ahyGmvHbxavjk7PjZ7PR",
-	"ZJTZInc8IKzj9K5NecSvUPtCiE9Hk9HElSOqRAml4HN+4N5FfPOGwAs2c0Q1hjR1xduFil3fSBm7XR+f",
-	"uZg2DCRzwkwtvdOIXRuzGCiwU0OP2HmGDArCJA2xYC4NS5EOJYZGxbgSMmIo04iBTJmxWI7YT0qzwJde",
-	"gv2FTZ0UPUwiJ0aPTFfSsOkkTLwWNnOPbHFkmAa5IkQ5OEwJc9PJ6JP0ltA+0lNyzqb9WJ+NjH2p0ps6",
-	"SuqKrCxzkbhx4wvj605/VnnU4ac5hND5p3uscHvds2JAn+n3KWzg+j1oyHPM9xxB5t1LtJcQvP5et23y",
-	"+XzXlbe6Ql9hlIowTyvOqE54hNXvs/V2W9kp0AW2Rltp6cmEkOvRvThiShPYfcNWLJlRBdqM4LSmyF9r",
-	"5fPqc69wd9IYiLfKyrISNBR0Yne2MlVRAGXWkCMNA2bQNsFkMqBEOxxLbgaK1v0CdcOszEvEmBJXARs6",
-	"61rlFvUZvebeHcHydXGyd3T87mC5FydkrU0HiUGSYEkJIa5c+tDICrHKXL5oYJOhTJC5zk6TTJIMk0sk",
-	"eASDu+38KkgiFRs6hgHspJijxQCfFQ6gZ7EMmxE+RUJlVQFWJEwjuA6LWjLfKMPUY1a5IQyvMalolho8",
-	"n6TN8CZYZYWWGas0pmzpA0tI11xp5wZRuHwhKEGQoowiTqUOlFdoPkm/pya71yh16SJVpJ1RTSA32Vs6",
-	"h7jcQkj/LlUSo0/Sd7u+H8L4kVvew7wxu3Hc3jPXUb19Z2xX1Iiyyn1Hk6icykhX/YVC4MJd6PSBHbVA",
-	"2i+5P/+GoA9Nx220v3GskaNzqEZT5TZ4UsiU1kLvQ4taQu7hPmKLAAXPmYa5pmVjnjXUjk1Hj8M7yT7f",
-	"lqVJF0cOp0tVybQXGAE2DDqA9fBxmkPOjl76yLhQMRlUq3xnaHwIiYLqQwd8X8A6Tt2qRH2FgwHYxOY2",
-	"VENgWyGBMgOZ1Ly8FLlFPQTINyp+FdT7BhAZ9ReFxOHEKlaippqBfRcaz5EDT4ERy1Tu/ssRDEasviHA",
-	"73eo5ee8V6+6Nx7W8sehyh2bsvro6ZZr3Ui0e/v/3RAj/wTMtcjTx9rvER5JBnKFZoNOAo5j2CYk6pPi",
-	"/fHAWu83+ccXGX8yLFyAsPqu0xmRQax84mQpWsfEPSU69Zf3FFF5AsbLhE87osVduTwcKmITKpSW/LEv",
-	"BLNXOlNVnlIa8XWiazJ8+7zevasaAF+rBCQnB3dA4yQPg6fyrqVDWeNXWoCOERu3d1HWXOLeC7MOTGun",
-	"aSw1Gn8yDzS8ElcomVdxxI4cD7u6RbZrBiZqGobcqODcADxTeZX8aRJYoTSylQZZ5aCbCRpLeWYfhmF9",
-	"t3kvDoM5XVuh0pqShlPD6bABIAFO+PrpfwWD7evBARB23Nn4bW/YEUN0K5ABIG5Bp4W9XBhbdz7Mvnnf",
-	"dRZcDRtU9gfGoJxnkAQkeawymzo/fPf0ePh+4fpMZgg2b4Wx9bHVPISd9uK7EmmY62uTvKyKGDWZUGOi",
-	"dOqaeOZSlM4aJaxCK3uHGiTJ2wu2ewsHM37/T2+21Xnnf7bDBtXynncFKcHFO4rcsC0NGp1MLgpBlemn",
-	"XRtwAg/soP4p0XRCfw9s6WuDr7mS3eeHa71r2uGK3zXNakpt+hBCrnL0XbV+rHrwk81+rYJlV1KhWDUP",
-	"xt8mrqmg93cIobV2f2xfgXA/xHPzbAaOqBBo/e/QYjVIQwV1WndNXOO3Ns5a6ctcQRrOB9q1QG9qTnBN",
-	"dyHpkFDAChmBy0R+2RyMoX/QJruI4U1zNSLq/v/TAdTcSH3kcWVuYnU9p20ZQnlBpFj/+zna+/5/sM41",
-	"7EoYQea1irZq/TnpK5oe+gHHdYBgvskzXXDngxT/jfFu8TDv/n8S6G+C84b2OtBsT+3kUV8Ng2frpqh7",
-	"pXcltPWDR2YNqxXq3g3fi/FmrXF9t9RfJFPGMvfjPQ9Dv8atn/hufDXlEb8CLShO/U+/3RdvjyXQeXej",
-	"EpRiFIiJFHGXCf6YX0u4y3Nac34wO3A/WAxXad0P0/CBhhRgLOq+fOvtlH++u7v7fPefAAAA//+9aoZG",
-	"pS4AAA==",
+	"H4sIAAAAAAAC/+xce2/cunL/KoRaoAmg7MNO7ikW6B9O7HOO3cTOjX1ve3sSBFxpdkVHInVIyutt4O9e",
+	"DB96LbUPOznNQZt/srsaksOZ3zw4HPlrlIiiFBy4VtHsa6SSDApqPp6k6YmUdH0h5leVLiuNP9I8v1pE",
+	"s9++Rv8sYRHNon8aNxOM3ejxhZifn0YP8XaqMymFjB4+PcS41h+xjP04+xqloBLJSs0Ej2YR4M9Eacn4",
+	"MoojXuU5necQzbSsII70uoRoFrnnD3F0IeZnd8ANp6UUJUjNwMgM/M/AqyKa/RZxWF1rqiGKo4ItJdWQ",
+	"RnFEtZZsXml4k1G+hOhTYJFbs7nZfhJAarvO7gGW7uEhjiT8XjEJKTJql4vdDloTNryJ+S0k2gnA8gb3",
+	"tChRUkg/PTp++eovUVhg56eqUW9PaF4re2jRycWMYxoKtbeEHFMUIR3evcLtm2WG9swXYpN9muciQcW+",
+	"o0nGOHR525BFl484opwLTS0S2wINyTEpqxtWQJdwejSJ4mghZEF1NIsY13952QxmXMMSjORSpkqqk2xz",
+	"iqPJ9KcXk59eHE1vpj/Njo9nR//6X+05U6rhhcZhAabgnmmESqU6k07iqKD3rEA7OHr1CvHP7bdJiDm4",
+	"18CVkwJNU4YSofn7jqgDa7vVvkYMPxTArSw/qxIStmDJ5wKoqqR5Es2i+/V/Rw8B7S4YZ2q3ZF4eJBmW",
+	"HmAjt2J+teIgu0MqBXIaIv+9ggouaZ/fjC2zUjIhmV6HhqlcaNXDTxAsynuThvDD3y4vzy9/CU5bza83",
+	"ByDzpJQARWn9XmhcwRSq3RlPj7XRdDKajqbH28fuVNvRQWrTIAvGqWZ8ec2WnObdua/Pf/n387dvQyNX",
+	"NM+TXCRfHm2lPb/E0qhjYCHmOi7EK66lkjjgoUKSb0HQ46QNs/7uGm+0oYqeq+lY1xbX+pjwjx75gATg",
+	"ohUmfYiuuAIdxVHFU7DShdTvvPnwK+T4RVac2yxBVaoEnhoSCZao9dHRp8JIdkFZDiluvkFEM1UoXho2",
+	"HyERF93j3YQeH7VggjastChLxpeEcaUpT2CA3xsoytyN7mZXpx/enZwckVsxJ9oRkRQQEg6xvXCaJKLi",
+	"iO9+ilFx9nsFJ63nQc9I5fLAEJxQnjJ0CY+L4CnQNGccvNHvGTYLyvLDFjJDrvi1phL9aTNkLkQOlLdo",
+	"bpybGCRDm3hPdTYQVp8YjK3OP19enZ59/vUmmkVisYjizs9vT16fvf189p83Z5fX51eXOFMpdDA4M15W",
+	"epDZWzF/QzUshVwPPT/jd0wKXrjk/DE7evePz2eXf49m0RvBtU2R3/3j89XNr2cfug+Oglu4FXMfrAMM",
+	"Mv4zy0GFVVVYUJ7IJOvaw8mHd+0Y0kzoRlz1Av3r69Mw9f21zwr2yCILxt9na/UOCifw/cYcsoSo9DaF",
+	"1/nNfrN1MqWNySR8qDi3x72Q9CUUQsMbURSU99K58ZzxscoBypBYJSiQdyYqW0cWohCVTOAtK5hWjwUm",
+	"nU4mJosLwU5puoTzBl2PWWBsRTBOQWmTeQi+YDlMUU5UwV9ezpLaJAZoj1BadrNj/D7M61Wln8YsZjp5",
+	"iFfPmgXXSGk6xIXUh3lyk/3oE/WryAe87UrIL4wvT5mERIf9VC/x68LOBbXeUh3wbvi5wKpdX+lD0EZc",
+	"CQWRxoO13XHHVNtBpe3U+qbQTSpr19ByRC0TDwXnvg9qO7yuu2yrsxene7bRh9+GdcbdxGQgjfVZ0CMS",
+	"Nz/0kHS2dWgK5lxOFlvTLZlkTEOiK9nL/O7DwYXeUZYP+8tESFDvQV6L5AvoPV304/KNDQXkgqZdsxWV",
+	"tQ1HyqtiblfkGydnLlII5pOiF0bfMl7dhwn/DtLvYpsGPRnGsmytWELzg8KpMrLdN5rqTAJNUSdvhIQ9",
+	"B90xqauD2Oo5MG7Nu0FLw/YGSDZ4dKrcEE+fr7gL37YK8HPQSr0b+TbVyCJ0Ytg20i2/syJZNN5uuCb5",
+	"V+9Iv9FuasfchfxvUQoLWuWoqU6B6VO89+mlt73WSts22DKo7r4Keit6pbJXweSW8T7dNEC4IXyc3Y8O",
+	"MfYflOlvVs8+sHJvx1T7D6uakZoVICq9GTG0rICsMuBEZ2AO60wRLjRhnFC+JmJhHizZHXBiakwqijf8",
+	"/2ZZ3deg2iw3bAzr/sGc+2y9HfNLmhimrd+OTilnkJNfZDU35apK5tEsyrQu1Ww8XjKdVfNRIopxujQk",
+	"41QWlB4JZc/rm6Hy6ppgDJY0+QKSnLw/J1g0WymiBSkop0sgcyxooWQUqRRWRO6oZKJSZI6DeEoY1yAX",
+	"NAE1Iuea1AVpZSTXmr4mJAspCvO05qKUAgUwIogwXGUhpFGHlTlqpZ4YUkIVyQVfvihFnpNnK8r0c0J5",
+	"akaYqxxFqASitARaWPprkHcgX1wD1+TMkjyzpM9H6HlZAlxBS9gnJU0yIEejSUfSs/F4tVqNqHk6EnI5",
+	"dkPV+O35m7PL67MXR6PJKNNFbpGnjfl1xYwu3dt4NB1NRhOkZaIETksWzaJj81scNb9EcVRSnRlTG9M0",
+	"NQ7mVszxeylUANvXJmtWhHJiiBHMRo94fkmJsJhXtIBOlWpEbjIgtMCsD4doqr4okgKW/RSOmsOS8ZgA",
+	"T2MjdKWhHJGfhSTO21gK8m9kaqjwwyQ2ZPiRyIorMp24iVdMZ+YjOT9VRFK+NOpHhEwRhtPJ6CO3kpA2",
+	"l06jWfuW1lcflX4t0rU3HF/zKMucJWbc+FZZl2p9xOOz1K7bM3vdN+Hj6Z6UBb1/TyXNc8j3HIHifUzG",
+	"Yvm3vG36o08PXXr0l+YHVQrEPK54NJkcJPVtst68fTcMdIEtQVeSq9plW3SfnxIhEez2XpstiBIF6Azh",
+	"tEKrX0lho+NLy3B30jlFV1ZWmpRU0gK0k5WqioJiMuhOoYpQokDXxqQyisE0bEtmBrTW/Qy1cbbEUswh",
+	"JfM1oSRUTdbCLGrPzN4dDxjL0+xkb+v4w8GyFScmftR3NIQmCeCNHJlXGiUngRRsmWkyhwY2GfAEiElT",
+	"SFEp8zDJIPkCCA8ncLOdb4IkZLF2xzSAnRRy0ODgs4QAes4XbjMuc6GVFgXVLCESqLnDEAtir6IgtZh1",
+	"WQ3cQ1LhLB48H7nOYO2ksgRNlBYSUhOMKSeM4zS6HRtYYeIFU8QyStDiRGpAeQfqI7d7qgO+R6kJF6lA",
+	"7pSoDdkL3CRfJnSY8E+epYJD/JHb+6TnIYyfmuUtzGuxK+Pbe+I69ds3wjZ5Diur3GZoDEl+r8Ccslwi",
+	"4HtEusCOWyDtp9WfviPoXQa9ifYL4zVywK9Egqpy7TTJeIprgdWhBslpbuE+IucOCtZnKmKuBWvxrKhX",
+	"bDo6DO9Ie7xJa+7GPVAx1YQUdeDA45a1o19ujkaWzk/N4IWoeNozKwc6Qjtwt+Az+6Y5OX1t7cqmfoNG",
+	"dW0yRxtiwGWLOfsCDpBaUq5MuUY9b8FJuaTZOO1e9htMQW2ulVJNcRagSWZXQxFR4huuCPBEpNYBXVxf",
+	"XYbwb+fbBX7ltiV4vm7vrWURNh+rzzznpzus4ilWoOFeW028sJwdFHbMlkOWYOdyEdptse+BnSB6BONb",
+	"MUf+pMgHofHBZR80z3fpnHGbNoPzlpnDD6qd6pafBZ7hJbML9guWa5AhLV+I+RvH3g/g5uL+ojTBD7ha",
+	"CRITUfLM9QvExiMVEJNM5OZbDlRBTHxjBzwfYMvOuZUv39Lg1jK0uFgUR5m/MTDLtRpJQt2H/zt+G/Xj",
+	"MNeKyNaBf0+f69d8otNNTC+nasCNuKN2RmdRvqCx3ZxI6/cmJ7KJ778o4tpeiO9wMzogdC5sMkdS0CY7",
+	"6DHRORNYRRPGSUKVpeE+EN44UxErbsWFk9JKZ8A1M21ExAhylQmbVGr8yQlvwFaR4T0MlTWGipnWKmNJ",
+	"5l2J3XMmqjzFzMgefSAdMJYfK1XpNjgFoN861SBGnDZpreOnJQMa6ww1LBi3J+MGNV2Q1p1/W1HaQblX",
+	"moRSgrKVqfm6FTwtiyNyaqKAScV5Ow0mzAcBmivhlOtwqyrLkg3IlBRCAllKyqucynqCWlI2roRh6MuP",
+	"W3HoxIncJZWUwHVTf2sBEAHH7JHgz4LBdk9ZMFtoqbPW296wI0L2kuoAEDeg08WebnWR7YRfpwZgfQWm",
+	"6ZVyBzXnnHxNAr0T8eNfTo69txmICnghtbkMlvleTaZ+rEefWPQznlSAndKcHDuTqAF01tWDgzKarqPs",
+	"MPvn9Za9e/SBZKHeaEscrSh3aB4Q1DhO8moyDSUeVvW1oksp7ljaU/UWE+jvoGUJOVPal7XVvvm3KRub",
+	"AoUzXjODe+IgklCOcKhUU8Rxz22egRcfCc3zIETfMqV9TXLnAau9+FBC6+Z6arJtb/VRhBISIVNzaaO+",
+	"sNJIo6RL1wk0wAZSdo5v7cLx8VG0/c2FTXbe2bceSJAtCwBTbUDMWEWhGjapqQRDk7OCYdnh49AGDMGO",
+	"Hfg3MaYT/LdjS0817ie9mBMs55gbEZ9c1EVmxpc52CuTftSy4EeZHXxwGAh1Q+kV2qraaX+NXePB2rZg",
+	"MdjDtn3/hJmnGTgiF53vBi2mGJObDN2VxAugvC5qYD8Y9lW4c7pN9dfeJ5hLVmZS/gKvOxFcKrbL5lQp",
+	"/AI6GXIMF3VnGfPtU48HUKvzYF6p9Vzcz4w31QhldIr+6yENCKEQosgdUwzFqwVuVdvo/YSKttyhuA4Q",
+	"1A9ZW3Hq3OnifzC/W+z2u/83Heh3wXnt9jrQbE+NOC8EZ1pIU/tI1V5ZPXpRsWiA7xlnvQMjT/IqbeX2",
+	"yh8BwMXNpjaxYTMj8jfV/O4O3+8sr/hiFZh7sua0cFjGX/dnkKKeMmRnv4A+yfML/+rpd60+1G/fDqTS",
+	"TuqdHXYz6f2S4NDeg8ip9dtddRM2+5TM2iWipt7V3U5zQEQ+OSSgFJUsX28HyveFgGP6ZykKB7+nHP7+",
+	"v0om5t8Uqj6Yb6ud8XUAbV0Yt5tEd+LYE/dmtEcGWrhUz2aY9e1VPchfWiloEXsU/DEOrdWnvz19MPyJ",
+	"RXcDu2K1+S8Oxcpd+d/3hG6vqTiA3QG1fmcvuwVQXYiaflxOiz1B2tGdGXsYXt2QHwCtf203Iu+PV7eB",
+	"PydaN9rGA3g1G+zq+Y+DbBBRFrDY4joI0df4droiFdcsb/cxM4QehHqYcTP4m2tIRmKJ3QeQkmd1Y+1z",
+	"eymmTNeCrceoehShxHWTEeRNxa5zipIkZ7hfDuDPICVQe0dXj/AN14H157AQEgiuJVbcU4SQjK3CB1+D",
+	"I7Pf9PIiDlVNnJR3r+f/bMHwevseippe994LCEMHRqNFzyHjREEieKrIswnxCQArCkgZ1ZCvh67nm5b2",
+	"wDGyfhEpcGz0LyV9V5Nvva+w6+bH9L4gLleuAz2jqu4jesKJ0ViHs0262zItn9bmQoDe6CPvvgOALwrZ",
+	"wSO1osslyN4rAT+Nm8Pq2Hee9xfJhNLE/PEMJni9xlc78cP4Dl8Yw9cAsNBjdGafWPHYV2YalmjJRq6y",
+	"hYxEcd2v4SlwpRzXnB0fHZs/GOIa7bsPplFcDymo0iD79K1fpxG2Ln96+J8BAMUT8LzqSwAA",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
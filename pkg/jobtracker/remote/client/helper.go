@@ -136,3 +136,66 @@ func ConvertJobStateToDRMAA2(in string) drmaa2interface.JobState {
 	}
 	return drmaa2interface.Undetermined
 }
+
+func ConvertJobState(in drmaa2interface.JobState) genclient.JobState {
+	switch in {
+	case drmaa2interface.Done:
+		return genclient.JobStateDone
+	case drmaa2interface.Failed:
+		return genclient.JobStateFailed
+	case drmaa2interface.Queued:
+		return genclient.JobStateQueued
+	case drmaa2interface.QueuedHeld:
+		return genclient.JobStateQueuedHeld
+	case drmaa2interface.Requeued:
+		return genclient.JobStateRequeued
+	case drmaa2interface.RequeuedHeld:
+		return genclient.JobStateRequeuedHeld
+	case drmaa2interface.Running:
+		return genclient.JobStateRunning
+	case drmaa2interface.Suspended:
+		return genclient.JobStateSuspended
+	case drmaa2interface.Unset:
+		return genclient.JobStateUnset
+	}
+	return genclient.JobStateUndetermined
+}
+
+func ConvertJobEventToDRMAA2(in genclient.JobEvent, sessionName string) drmaa2interface.Notification {
+	event := drmaa2interface.NewState
+	switch in.Event {
+	case genclient.JobEventEventMigrated:
+		event = drmaa2interface.Migrated
+	case genclient.JobEventEventAttributeChange:
+		event = drmaa2interface.AttributeChange
+	}
+	return drmaa2interface.Notification{
+		Evt:         event,
+		JobID:       string(in.JobID),
+		SessionName: sessionName,
+		State:       ConvertJobStateToDRMAA2(string(in.JobState)),
+	}
+}
+
+func ConvertMachineToDRMAA2(in genclient.Machine) drmaa2interface.Machine {
+	machine := drmaa2interface.Machine{
+		Name:           in.Name,
+		Available:      in.Available,
+		Sockets:        in.Sockets,
+		CoresPerSocket: in.CoresPerSocket,
+		ThreadsPerCore: in.ThreadsPerCore,
+		Load:           in.Load,
+		PhysicalMemory: in.PhysicalMemory,
+		VirtualMemory:  in.VirtualMemory,
+		OSVersion: drmaa2interface.Version{
+			Major: in.OsVersion.Major,
+			Minor: in.OsVersion.Minor,
+		},
+	}
+	machine.Architecture, _ = drmaa2interface.CPUFromString(in.Architecture)
+	machine.OS, _ = drmaa2interface.OSFromString(in.Os)
+	if in.Extension != nil {
+		machine.ExtensionList = in.Extension.AdditionalProperties
+	}
+	return machine
+}
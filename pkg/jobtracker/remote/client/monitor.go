@@ -0,0 +1,157 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/dgruber/drmaa2interface"
+	"github.com/dgruber/drmaa2os/pkg/d2hlp"
+	genclient "github.com/dgruber/drmaa2os/pkg/jobtracker/remote/client/generated"
+)
+
+// ErrNotImplemented is returned when the job tracker of the server
+// does not implement the requested functionality.
+var ErrNotImplemented = errors.New("not implemented by remote job tracker")
+
+// JobTemplate returns the job template of a job.
+func (c *ClientJobTracker) JobTemplate(jobid string) (drmaa2interface.JobTemplate, error) {
+	resp, err := c.client.JobTemplateWithResponse(context.Background(),
+		&genclient.JobTemplateParams{JobID: jobid})
+	if err != nil || resp == nil {
+		return drmaa2interface.JobTemplate{}, fmt.Errorf("failed requesting job template: %v", err)
+	}
+	if err := deniedError("requesting job template", resp.HTTPResponse, resp.Body); err != nil {
+		return drmaa2interface.JobTemplate{}, err
+	}
+	if resp.StatusCode() == http.StatusNotImplemented {
+		return drmaa2interface.JobTemplate{}, fmt.Errorf("failed requesting job template: %w", ErrNotImplemented)
+	}
+	if resp.JSON200 == nil {
+		return drmaa2interface.JobTemplate{}, fmt.Errorf("failed requesting job template from remote")
+	}
+	if resp.JSON200.Error != "" {
+		return drmaa2interface.JobTemplate{}, fmt.Errorf("failed requesting job template from remote: %s", resp.JSON200.Error)
+	}
+	if resp.JSON200.Extension == nil {
+		resp.JSON200.Extension = &genclient.JobTemplate_Extension{}
+	}
+	return ConvertJobTemplateToDRMAA2(resp.JSON200.JobTemplate), nil
+}
+
+// OpenMonitoringSession does nothing as the monitoring requests
+// don't require a session at the server.
+func (c *ClientJobTracker) OpenMonitoringSession(name string) error {
+	return nil
+}
+
+// CloseMonitoringSession does nothing as the monitoring requests
+// don't require a session at the server.
+func (c *ClientJobTracker) CloseMonitoringSession(name string) error {
+	return nil
+}
+
+// GetAllJobIDs returns the IDs of all jobs of the backend. The filter
+// is applied on the client side.
+func (c *ClientJobTracker) GetAllJobIDs(filter *drmaa2interface.JobInfo) ([]string, error) {
+	resp, err := c.client.GetAllJobIDsWithResponse(context.Background())
+	if err != nil || resp == nil {
+		return nil, fmt.Errorf("failed getting job IDs from remote: %v", err)
+	}
+	if resp.StatusCode() == http.StatusNotImplemented {
+		return nil, fmt.Errorf("failed getting job IDs: %w", ErrNotImplemented)
+	}
+	if resp.JSON200 == nil {
+		return nil, fmt.Errorf("failed getting job IDs from remote")
+	}
+	if resp.JSON200.Error != nil && *resp.JSON200.Error != "" {
+		return nil, fmt.Errorf("failed getting job IDs from remote: %s", *resp.JSON200.Error)
+	}
+	ids := make([]string, 0, len(resp.JSON200.JobIDs))
+	for _, id := range resp.JSON200.JobIDs {
+		if filter != nil {
+			ji, err := c.JobInfoFromMonitor(string(id))
+			if err != nil || !d2hlp.JobInfoMatches(ji, *filter) {
+				continue
+			}
+		}
+		ids = append(ids, string(id))
+	}
+	return ids, nil
+}
+
+// GetAllQueueNames returns the queue names of the backend. If names
+// is not nil only the queues with the given names are returned.
+func (c *ClientJobTracker) GetAllQueueNames(names []string) ([]string, error) {
+	params := &genclient.GetAllQueueNamesParams{}
+	if names != nil {
+		params.Name = &names
+	}
+	resp, err := c.client.GetAllQueueNamesWithResponse(context.Background(), params)
+	if err != nil || resp == nil {
+		return nil, fmt.Errorf("failed getting queue names from remote: %v", err)
+	}
+	if resp.StatusCode() == http.StatusNotImplemented {
+		return nil, fmt.Errorf("failed getting queue names: %w", ErrNotImplemented)
+	}
+	if resp.JSON200 == nil {
+		return nil, fmt.Errorf("failed getting queue names from remote")
+	}
+	if resp.JSON200.Error != nil && *resp.JSON200.Error != "" {
+		return nil, fmt.Errorf("failed getting queue names from remote: %s", *resp.JSON200.Error)
+	}
+	if resp.JSON200.QueueNames == nil {
+		return []string{}, nil
+	}
+	return resp.JSON200.QueueNames, nil
+}
+
+// GetAllMachines returns the machines of the backend. If names is not
+// nil only the machines with the given names are returned.
+func (c *ClientJobTracker) GetAllMachines(names []string) ([]drmaa2interface.Machine, error) {
+	params := &genclient.GetAllMachinesParams{}
+	if names != nil {
+		params.Name = &names
+	}
+	resp, err := c.client.GetAllMachinesWithResponse(context.Background(), params)
+	if err != nil || resp == nil {
+		return nil, fmt.Errorf("failed getting machines from remote: %v", err)
+	}
+	if resp.StatusCode() == http.StatusNotImplemented {
+		return nil, fmt.Errorf("failed getting machines: %w", ErrNotImplemented)
+	}
+	if resp.JSON200 == nil {
+		return nil, fmt.Errorf("failed getting machines from remote")
+	}
+	if resp.JSON200.Error != nil && *resp.JSON200.Error != "" {
+		return nil, fmt.Errorf("failed getting machines from remote: %s", *resp.JSON200.Error)
+	}
+	machines := make([]drmaa2interface.Machine, 0, len(resp.JSON200.Machines))
+	for _, machine := range resp.JSON200.Machines {
+		machines = append(machines, ConvertMachineToDRMAA2(machine))
+	}
+	return machines, nil
+}
+
+// JobInfoFromMonitor returns the JobInfo of any job of the backend.
+func (c *ClientJobTracker) JobInfoFromMonitor(jobid string) (drmaa2interface.JobInfo, error) {
+	resp, err := c.client.JobInfoFromMonitorWithResponse(context.Background(),
+		&genclient.JobInfoFromMonitorParams{JobID: jobid})
+	if err != nil || resp == nil {
+		return drmaa2interface.JobInfo{}, fmt.Errorf("failed requesting job info: %v", err)
+	}
+	if resp.StatusCode() == http.StatusNotImplemented {
+		return drmaa2interface.JobInfo{}, fmt.Errorf("failed requesting job info: %w", ErrNotImplemented)
+	}
+	if resp.JSON200 == nil {
+		return drmaa2interface.JobInfo{}, fmt.Errorf("failed requesting job info from remote")
+	}
+	if resp.JSON200.Error != "" {
+		return drmaa2interface.JobInfo{}, fmt.Errorf("failed requesting job info from remote: %s", resp.JSON200.Error)
+	}
+	if resp.JSON200.Extension == nil {
+		resp.JSON200.Extension = &genclient.JobInfo_Extension{}
+	}
+	return ConvertJobInfoToDRMAA2(resp.JSON200.JobInfo), nil
+}
@@ -0,0 +1,173 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	gensessionclient "github.com/dgruber/drmaa2os/pkg/jobtracker/remote/client/generated/sessionmanager"
+)
+
+var (
+	// ErrJobSessionNotFound is returned when the job session does not
+	// exist at the server.
+	ErrJobSessionNotFound = errors.New("job session not found at remote")
+	// ErrJobSessionExists is returned when a job session with the same
+	// name exists already at the server.
+	ErrJobSessionExists = errors.New("job session exists already at remote")
+)
+
+// SessionClient manages the job sessions of a server which hosts
+// multiple job sessions (server.SessionServer).
+type SessionClient struct {
+	client gensessionclient.ClientWithResponsesInterface
+}
+
+// NewSessionClient creates a client for the SessionManager API. It uses
+// the same server, path, TLS, and authentication settings as the job
+// trackers created with the parameters.
+func NewSessionClient(params ClientTrackerParams) (*SessionClient, error) {
+	if params.Server == "" {
+		params.Server = "localhost:32321"
+	}
+	// the JobTracker API client applies all settings (including the
+	// client options) which are then reused for the SessionManager API
+	jobTrackerClient, err := newClient(params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create remote session client: %v", err)
+	}
+	opts := []gensessionclient.ClientOption{
+		gensessionclient.WithHTTPClient(jobTrackerClient.Client),
+	}
+	for _, fn := range jobTrackerClient.RequestEditors {
+		opts = append(opts, gensessionclient.WithRequestEditorFn(
+			gensessionclient.RequestEditorFn(fn)))
+	}
+	if params.Path != "" {
+		opts = append(opts, gensessionclient.WithBaseURL(params.Server+params.Path))
+	}
+	client, err := gensessionclient.NewClientWithResponses(params.Server, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create remote session client: %v", err)
+	}
+	return &SessionClient{client: client}, nil
+}
+
+// ListBackends returns the names of the backends of the server.
+func (c *SessionClient) ListBackends() ([]string, error) {
+	resp, err := c.client.ListBackendsWithResponse(context.Background())
+	if err != nil || resp == nil {
+		return nil, fmt.Errorf("failed listing backends from remote: %v", err)
+	}
+	if resp.JSON200 == nil {
+		return nil, fmt.Errorf("failed listing backends from remote: %s", responseError(resp.HTTPResponse, resp.Body))
+	}
+	return *resp.JSON200, nil
+}
+
+// ListJobSessions returns the job sessions of the server with their
+// backends.
+func (c *SessionClient) ListJobSessions() ([]gensessionclient.Session, error) {
+	resp, err := c.client.ListSessionsWithResponse(context.Background())
+	if err != nil || resp == nil {
+		return nil, fmt.Errorf("failed listing job sessions from remote: %v", err)
+	}
+	if resp.JSON200 == nil {
+		return nil, fmt.Errorf("failed listing job sessions from remote: %s", responseError(resp.HTTPResponse, resp.Body))
+	}
+	return *resp.JSON200, nil
+}
+
+// CreateJobSession creates a job session at the server using the given
+// backend or the default backend of the server if backend is empty.
+func (c *SessionClient) CreateJobSession(name, backend, contact string) error {
+	input := gensessionclient.CreateSessionJSONRequestBody{Name: name}
+	if backend != "" {
+		input.Backend = &backend
+	}
+	if contact != "" {
+		input.Contact = &contact
+	}
+	resp, err := c.client.CreateSessionWithResponse(context.Background(), input)
+	if err != nil || resp == nil {
+		return fmt.Errorf("failed creating job session at remote: %v", err)
+	}
+	switch resp.StatusCode() {
+	case http.StatusOK:
+		return nil
+	case http.StatusConflict:
+		return ErrJobSessionExists
+	}
+	return fmt.Errorf("failed creating job session at remote: %s", responseError(resp.HTTPResponse, resp.Body))
+}
+
+// OpenJobSession opens a job session at the server and returns its
+// backend.
+func (c *SessionClient) OpenJobSession(name string) (string, error) {
+	resp, err := c.client.OpenSessionWithResponse(context.Background(), name)
+	if err != nil || resp == nil {
+		return "", fmt.Errorf("failed opening job session at remote: %v", err)
+	}
+	if resp.StatusCode() == http.StatusNotFound {
+		return "", ErrJobSessionNotFound
+	}
+	if resp.JSON200 == nil {
+		return "", fmt.Errorf("failed opening job session at remote: %s", responseError(resp.HTTPResponse, resp.Body))
+	}
+	return resp.JSON200.Backend, nil
+}
+
+// DestroyJobSession destroys a job session at the server.
+func (c *SessionClient) DestroyJobSession(name string) error {
+	resp, err := c.client.DestroySessionWithResponse(context.Background(), name)
+	if err != nil || resp == nil {
+		return fmt.Errorf("failed destroying job session at remote: %v", err)
+	}
+	switch resp.StatusCode() {
+	case http.StatusOK:
+		return nil
+	case http.StatusNotFound:
+		return ErrJobSessionNotFound
+	}
+	if err := deniedError("destroying job session", resp.HTTPResponse, resp.Body); err != nil {
+		return err
+	}
+	return fmt.Errorf("failed destroying job session at remote: %s", responseError(resp.HTTPResponse, resp.Body))
+}
+
+// openOrCreateJobSession makes sure that the job session exists at the
+// server.
+func openOrCreateJobSession(name string, params ClientTrackerParams) error {
+	if name == "" {
+		return errors.New("job session name is required for servers hosting job sessions")
+	}
+	sc, err := NewSessionClient(params)
+	if err != nil {
+		return err
+	}
+	_, err = sc.OpenJobSession(name)
+	if !errors.Is(err, ErrJobSessionNotFound) {
+		return err
+	}
+	err = sc.CreateJobSession(name, params.Backend, "")
+	if errors.Is(err, ErrJobSessionExists) {
+		// created concurrently
+		return nil
+	}
+	return err
+}
+
+// responseError returns the status and the error message of a failed
+// request.
+func responseError(resp *http.Response, body []byte) string {
+	if resp == nil {
+		return "no response"
+	}
+	message := strings.TrimSpace(string(body))
+	if message == "" {
+		return resp.Status
+	}
+	return fmt.Sprintf("%s: %s", resp.Status, message)
+}
@@ -0,0 +1,176 @@
+package client_test
+
+import (
+	"net/http/httptest"
+	"path/filepath"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/dgruber/drmaa2interface"
+	"github.com/dgruber/drmaa2os"
+
+	. "github.com/dgruber/drmaa2os/pkg/jobtracker/remote/client"
+	"github.com/dgruber/drmaa2os/pkg/jobtracker/remote/server"
+)
+
+var _ = Describe("Job sessions", func() {
+
+	var (
+		sessionServer *server.SessionServer
+		testServer    *httptest.Server
+		params        ClientTrackerParams
+	)
+
+	BeforeEach(func() {
+		dir := GinkgoT().TempDir()
+		backends := make(map[string]*drmaa2os.SessionManager)
+		for _, name := range []string{"process", "other"} {
+			sm, err := drmaa2os.NewDefaultSessionManager(filepath.Join(dir, name+".db"))
+			Expect(err).To(BeNil())
+			backends[name] = sm
+		}
+		var err error
+		sessionServer, err = server.NewSessionServer(backends, "process")
+		Expect(err).To(BeNil())
+		router := chi.NewRouter()
+		router.Use(server.BearerTokenAuth(map[string]string{
+			"secret":  "user1",
+			"secret2": "user2",
+		}))
+		testServer = httptest.NewServer(
+			sessionServer.HandlerFromMuxWithBaseURL(router, "/api"))
+		params = ClientTrackerParams{
+			Server:      testServer.URL,
+			Path:        "/api",
+			Token:       "secret",
+			JobSessions: true,
+		}
+	})
+
+	AfterEach(func() {
+		testServer.Close()
+		sessionServer.Close()
+	})
+
+	It("should manage the job sessions of the server", func() {
+		sc, err := NewSessionClient(params)
+		Expect(err).To(BeNil())
+
+		backends, err := sc.ListBackends()
+		Expect(err).To(BeNil())
+		Expect(backends).To(Equal([]string{"other", "process"}))
+
+		Expect(sc.CreateJobSession("team1", "other", "")).To(Succeed())
+		Expect(sc.CreateJobSession("team1", "", "")).To(MatchError(ErrJobSessionExists))
+		Expect(sc.CreateJobSession("team2", "unknown", "")).NotTo(Succeed())
+
+		backend, err := sc.OpenJobSession("team1")
+		Expect(err).To(BeNil())
+		Expect(backend).To(Equal("other"))
+
+		sessions, err := sc.ListJobSessions()
+		Expect(err).To(BeNil())
+		Expect(sessions).To(HaveLen(1))
+		Expect(sessions[0].Name).To(Equal("team1"))
+		Expect(sessions[0].Backend).To(Equal("other"))
+
+		Expect(sc.DestroyJobSession("team1")).To(Succeed())
+		_, err = sc.OpenJobSession("team1")
+		Expect(err).To(MatchError(ErrJobSessionNotFound))
+		Expect(sc.DestroyJobSession("team1")).To(MatchError(ErrJobSessionNotFound))
+	})
+
+	It("should allow only the creator to destroy a job session", func() {
+		sc, err := NewSessionClient(params)
+		Expect(err).To(BeNil())
+		Expect(sc.CreateJobSession("team1", "", "")).To(Succeed())
+
+		otherParams := params
+		otherParams.Token = "secret2"
+		other, err := NewSessionClient(otherParams)
+		Expect(err).To(BeNil())
+		_, err = other.OpenJobSession("team1")
+		Expect(err).To(BeNil())
+		err = other.DestroyJobSession("team1")
+		Expect(err).NotTo(BeNil())
+		Expect(err.(drmaa2interface.Error).ID).To(Equal(drmaa2interface.DeniedByDrms))
+
+		unknownParams := params
+		unknownParams.Token = "wrong"
+		unknown, err := NewSessionClient(unknownParams)
+		Expect(err).To(BeNil())
+		err = unknown.DestroyJobSession("team1")
+		Expect(err).NotTo(BeNil())
+		Expect(err.(drmaa2interface.Error).ID).To(Equal(AuthException))
+
+		Expect(sc.DestroyJobSession("team1")).To(Succeed())
+	})
+
+	It("should create the job session of the job tracker at the server", func() {
+		params.Backend = "other"
+		team1, err := New("team1", params)
+		Expect(err).To(BeNil())
+		params.Backend = ""
+		team2, err := New("team2", params)
+		Expect(err).To(BeNil())
+
+		sc, err := NewSessionClient(params)
+		Expect(err).To(BeNil())
+		backend, err := sc.OpenJobSession("team1")
+		Expect(err).To(BeNil())
+		Expect(backend).To(Equal("other"))
+		backend, err = sc.OpenJobSession("team2")
+		Expect(err).To(BeNil())
+		Expect(backend).To(Equal("process"))
+
+		jobid, err := team1.AddJob(drmaa2interface.JobTemplate{
+			RemoteCommand: "/bin/sleep",
+			Args:          []string{"0"},
+		})
+		Expect(err).To(BeNil())
+		Expect(team1.Wait(jobid, time.Second*5, drmaa2interface.Done)).To(Succeed())
+
+		jobs, err := team1.ListJobs()
+		Expect(err).To(BeNil())
+		Expect(jobs).To(ConsistOf(jobid))
+		jobs, err = team2.ListJobs()
+		Expect(err).To(BeNil())
+		Expect(jobs).To(BeEmpty())
+
+		// a second client opens the existing job session
+		team1Again, err := New("team1", params)
+		Expect(err).To(BeNil())
+		jobs, err = team1Again.ListJobs()
+		Expect(err).To(BeNil())
+		Expect(jobs).To(ConsistOf(jobid))
+	})
+
+	It("should require a job session name", func() {
+		_, err := New("", params)
+		Expect(err).NotTo(BeNil())
+	})
+
+	It("should use the job sessions of the server in a remote session manager", func() {
+		sm, err := drmaa2os.NewRemoteSessionManager(params,
+			filepath.Join(GinkgoT().TempDir(), "remote.db"))
+		Expect(err).To(BeNil())
+		js, err := sm.CreateJobSession("team3", "")
+		Expect(err).To(BeNil())
+		job, err := js.RunJob(drmaa2interface.JobTemplate{
+			RemoteCommand: "/bin/sleep",
+			Args:          []string{"0"},
+		})
+		Expect(err).To(BeNil())
+		Expect(job.WaitTerminated(time.Second * 5)).To(Succeed())
+		Expect(job.GetState()).To(Equal(drmaa2interface.Done))
+
+		sc, err := NewSessionClient(params)
+		Expect(err).To(BeNil())
+		_, err = sc.OpenJobSession("team3")
+		Expect(err).To(BeNil())
+	})
+
+})
@@ -0,0 +1,172 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// DefaultAPIKeyHeader is the HTTP header which contains the API key
+// when no other header is configured.
+const DefaultAPIKeyHeader = "X-API-Key"
+
+type principalKey struct{}
+
+// Principal returns the name of the authenticated user of a request
+// which was set by the authentication middleware.
+func Principal(ctx context.Context) (string, bool) {
+	principal, ok := ctx.Value(principalKey{}).(string)
+	return principal, ok
+}
+
+// WithPrincipal returns a context which contains the name of the
+// authenticated user.
+func WithPrincipal(ctx context.Context, principal string) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// BearerTokenAuth returns a middleware which accepts only requests with
+// an "Authorization: Bearer <token>" header containing one of the given
+// tokens. tokens maps the tokens to the names of their users.
+func BearerTokenAuth(tokens map[string]string) func(http.Handler) http.Handler {
+	return tokenAuth(tokens, func(r *http.Request) string {
+		auth := r.Header.Get("Authorization")
+		if len(auth) < len("Bearer ") || !strings.EqualFold(auth[:len("Bearer ")], "Bearer ") {
+			return ""
+		}
+		return strings.TrimSpace(auth[len("Bearer "):])
+	}, `Bearer realm="jobtracker"`)
+}
+
+// APIKeyAuth returns a middleware which accepts only requests with one of
+// the given API keys in the given header (DefaultAPIKeyHeader if empty).
+// keys maps the API keys to the names of their users.
+func APIKeyAuth(header string, keys map[string]string) func(http.Handler) http.Handler {
+	if header == "" {
+		header = DefaultAPIKeyHeader
+	}
+	return tokenAuth(keys, func(r *http.Request) string {
+		return r.Header.Get(header)
+	}, "")
+}
+
+func tokenAuth(tokens map[string]string, extract func(*http.Request) string, challenge string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, ok := lookupToken(tokens, extract(r))
+			if !ok {
+				if challenge != "" {
+					w.Header().Set("WWW-Authenticate", challenge)
+				}
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(WithPrincipal(r.Context(), principal)))
+		})
+	}
+}
+
+// lookupToken compares the token with all known tokens in constant time.
+func lookupToken(tokens map[string]string, token string) (string, bool) {
+	if token == "" {
+		return "", false
+	}
+	var principal string
+	found := false
+	for known, name := range tokens {
+		if subtle.ConstantTimeCompare([]byte(known), []byte(token)) == 1 {
+			principal, found = name, true
+		}
+	}
+	return principal, found
+}
+
+// ClientCertAuth returns a middleware which sets the common name of the
+// verified client certificate as name of the user. Requests without a
+// verified client certificate are rejected. It must be used together
+// with a TLS configuration which verifies client certificates.
+func ClientCertAuth() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 {
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+			cert := r.TLS.VerifiedChains[0][0]
+			next.ServeHTTP(w, r.WithContext(WithPrincipal(r.Context(), cert.Subject.CommonName)))
+		})
+	}
+}
+
+// TLSConfig creates the TLS configuration of the server from the PEM
+// encoded certificate and key files. If clientCAFile is set mutual TLS
+// is enabled: clients need to present a certificate which is signed by
+// one of the CAs in that file.
+func TLSConfig(certFile, keyFile, clientCAFile string) (*tls.Config, error) {
+	if certFile == "" || keyFile == "" {
+		return nil, errors.New("certificate and key file are required for TLS")
+	}
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed loading server certificate: %v", err)
+	}
+	config := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if clientCAFile != "" {
+		pool, err := LoadCertPool(clientCAFile)
+		if err != nil {
+			return nil, err
+		}
+		config.ClientCAs = pool
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return config, nil
+}
+
+// LoadCertPool reads a PEM encoded CA bundle.
+func LoadCertPool(caFile string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading CA bundle: %v", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates found in CA bundle %s", caFile)
+	}
+	return pool, nil
+}
+
+// LoadTokenFile reads the tokens (or API keys) and the names of their
+// users from a file. Each line contains a token followed by the name
+// of the user separated by whitespace. Empty lines and lines starting
+// with # are ignored.
+func LoadTokenFile(path string) (map[string]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading token file: %v", err)
+	}
+	tokens := make(map[string]string)
+	for i, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("line %d of token file %s: expected token and user name", i+1, path)
+		}
+		tokens[fields[0]] = fields[1]
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("no tokens found in %s", path)
+	}
+	return tokens, nil
+}
@@ -0,0 +1,126 @@
+package server_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	. "github.com/dgruber/drmaa2os/pkg/jobtracker/remote/server"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Auth", func() {
+
+	// principalHandler responds with the name of the authenticated user
+	principalHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal, _ := Principal(r.Context())
+		w.Write([]byte(principal))
+	})
+
+	serve := func(handler http.Handler, header, value string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/listjobs", nil)
+		if header != "" {
+			req.Header.Set(header, value)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	Context("bearer token", func() {
+
+		It("should accept only requests with a known token", func() {
+			handler := BearerTokenAuth(map[string]string{"secret": "user1"})(principalHandler)
+
+			rec := serve(handler, "Authorization", "Bearer secret")
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			Expect(rec.Body.String()).To(Equal("user1"))
+
+			rec = serve(handler, "Authorization", "Bearer wrong")
+			Expect(rec.Code).To(Equal(http.StatusUnauthorized))
+			Expect(rec.Header().Get("WWW-Authenticate")).To(ContainSubstring("Bearer"))
+
+			rec = serve(handler, "Authorization", "Basic secret")
+			Expect(rec.Code).To(Equal(http.StatusUnauthorized))
+
+			rec = serve(handler, "", "")
+			Expect(rec.Code).To(Equal(http.StatusUnauthorized))
+		})
+
+	})
+
+	Context("API key", func() {
+
+		It("should accept only requests with a known API key", func() {
+			handler := APIKeyAuth("", map[string]string{"key1": "user1"})(principalHandler)
+
+			rec := serve(handler, DefaultAPIKeyHeader, "key1")
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			Expect(rec.Body.String()).To(Equal("user1"))
+
+			rec = serve(handler, DefaultAPIKeyHeader, "key2")
+			Expect(rec.Code).To(Equal(http.StatusUnauthorized))
+		})
+
+		It("should read the API key from the configured header", func() {
+			handler := APIKeyAuth("X-Token", map[string]string{"key1": "user1"})(principalHandler)
+
+			rec := serve(handler, "X-Token", "key1")
+			Expect(rec.Code).To(Equal(http.StatusOK))
+
+			rec = serve(handler, DefaultAPIKeyHeader, "key1")
+			Expect(rec.Code).To(Equal(http.StatusUnauthorized))
+		})
+
+	})
+
+	Context("client certificates", func() {
+
+		It("should reject requests without verified client certificate", func() {
+			rec := serve(ClientCertAuth()(principalHandler), "", "")
+			Expect(rec.Code).To(Equal(http.StatusUnauthorized))
+		})
+
+	})
+
+	Context("token file", func() {
+
+		It("should read tokens and user names", func() {
+			path := filepath.Join(GinkgoT().TempDir(), "tokens")
+			err := os.WriteFile(path, []byte("# comment\n\ntoken1 user1\n  token2\tuser2\n"), 0600)
+			Expect(err).To(BeNil())
+			tokens, err := LoadTokenFile(path)
+			Expect(err).To(BeNil())
+			Expect(tokens).To(Equal(map[string]string{"token1": "user1", "token2": "user2"}))
+		})
+
+		It("should fail for invalid token files", func() {
+			path := filepath.Join(GinkgoT().TempDir(), "tokens")
+			err := os.WriteFile(path, []byte("token1\n"), 0600)
+			Expect(err).To(BeNil())
+			_, err = LoadTokenFile(path)
+			Expect(err).NotTo(BeNil())
+
+			err = os.WriteFile(path, []byte("# no tokens\n"), 0600)
+			Expect(err).To(BeNil())
+			_, err = LoadTokenFile(path)
+			Expect(err).NotTo(BeNil())
+
+			_, err = LoadTokenFile(filepath.Join(GinkgoT().TempDir(), "missing"))
+			Expect(err).NotTo(BeNil())
+		})
+
+	})
+
+	Context("TLS", func() {
+
+		It("should require certificate and key", func() {
+			_, err := TLSConfig("", "", "")
+			Expect(err).NotTo(BeNil())
+		})
+
+	})
+
+})
@@ -0,0 +1,191 @@
+// jobtrackerserver serves the remote JobTracker API for OS processes
+// (simpletracker) over https. Clients are authenticated by bearer
+// tokens, API keys, or client certificates (mutual TLS).
+//
+//	jobtrackerserver -tls-cert server.crt -tls-key server.key \
+//		-token-file tokens.txt
+//
+// The token file contains one token and the name of its user per line.
+//
+// Jobs can only be controlled and deleted by the user who submitted
+// them and by admins. The admins and the actions which all users may
+// perform are set in the policy file (-policy-file):
+//
+//	admin alice bob
+//	public template
+//
+// The owners of jobs and job sessions are stored in the owner file
+// (-owner-file) so that they are kept when the server is restarted.
+//
+// With -sessions the server hosts multiple job sessions, each of them
+// using one of the given backends (process, docker, kubernetes):
+//
+//	jobtrackerserver -sessions -backends process,docker ...
+//
+// A job session can only be destroyed by the user who created it and by
+// admins unless destroy is a public action.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+
+	"github.com/dgruber/drmaa2os"
+	_ "github.com/dgruber/drmaa2os/pkg/jobtracker/dockertracker"
+	_ "github.com/dgruber/drmaa2os/pkg/jobtracker/kubernetestracker"
+	"github.com/dgruber/drmaa2os/pkg/jobtracker/remote/server"
+	genserver "github.com/dgruber/drmaa2os/pkg/jobtracker/remote/server/generated"
+	"github.com/dgruber/drmaa2os/pkg/jobtracker/simpletracker"
+)
+
+func main() {
+	addr := flag.String("addr", ":32320", "address the server listens on")
+	path := flag.String("path", "", "base path of the API (like /jobtracker)")
+	db := flag.String("db", "job.db", "file in which the jobs are persisted")
+	session := flag.String("session", "jobsession", "name of the job session")
+	tlsCert := flag.String("tls-cert", "", "PEM encoded certificate of the server")
+	tlsKey := flag.String("tls-key", "", "PEM encoded key of the server")
+	clientCA := flag.String("client-ca", "", "PEM encoded CA bundle for verifying client certificates (enables mutual TLS)")
+	tokenFile := flag.String("token-file", "", "file with accepted bearer tokens and their users")
+	apiKeyFile := flag.String("api-key-file", "", "file with accepted API keys and their users")
+	apiKeyHeader := flag.String("api-key-header", server.DefaultAPIKeyHeader, "HTTP header containing the API key")
+	sessions := flag.Bool("sessions", false, "host multiple job sessions")
+	backends := flag.String("backends", "process", "comma separated backends of the job sessions (process, docker, kubernetes)")
+	defaultBackend := flag.String("default-backend", "", "backend of job sessions created without backend (first of -backends if not set)")
+	sessionDir := flag.String("session-dir", ".", "directory in which the job sessions of the backends are stored")
+	policyFile := flag.String("policy-file", "", "file with the admins and the public actions on jobs and job sessions")
+	ownerFile := flag.String("owner-file", "owners.json", "file in which the owners of jobs and job sessions are persisted")
+	insecure := flag.Bool("insecure", false, "allow serving plain http and unauthenticated requests")
+	flag.Parse()
+
+	if *tokenFile != "" && *apiKeyFile != "" {
+		log.Fatal("-token-file and -api-key-file are mutually exclusive")
+	}
+	tlsEnabled := *tlsCert != "" || *tlsKey != ""
+	authEnabled := *clientCA != "" || *tokenFile != "" || *apiKeyFile != ""
+	if !*insecure && (!tlsEnabled || !authEnabled) {
+		log.Fatal("TLS and authentication are required (use -insecure for testing)")
+	}
+	if *clientCA != "" && !tlsEnabled {
+		log.Fatal("-client-ca requires -tls-cert and -tls-key")
+	}
+
+	var policy server.Policy = &server.OwnerPolicy{}
+	if *policyFile != "" {
+		ownerPolicy, err := server.LoadPolicyFile(*policyFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		policy = ownerPolicy
+	}
+
+	var register func(router chi.Router) http.Handler
+	if *sessions {
+		names := strings.Split(*backends, ",")
+		sessionManagers := make(map[string]*drmaa2os.SessionManager, len(names))
+		for _, name := range names {
+			sm, err := newSessionManager(name, filepath.Join(*sessionDir, name+"-sessions.db"))
+			if err != nil {
+				log.Fatal(err)
+			}
+			sessionManagers[name] = sm
+		}
+		if *defaultBackend == "" {
+			*defaultBackend = names[0]
+		}
+		sessionServer, err := server.NewSessionServer(sessionManagers, *defaultBackend)
+		if err != nil {
+			log.Fatal(err)
+		}
+		sessionServer.SetPolicy(policy)
+		if err := sessionServer.SetOwnerFile(*ownerFile); err != nil {
+			log.Fatal(err)
+		}
+		register = func(router chi.Router) http.Handler {
+			return sessionServer.HandlerFromMuxWithBaseURL(router, *path)
+		}
+	} else {
+		jobStore, err := simpletracker.NewPersistentJobStore(*db)
+		if err != nil {
+			log.Fatalf("failed opening job store: %v", err)
+		}
+		tracker, err := simpletracker.NewWithJobStore(*session, jobStore, true)
+		if err != nil {
+			log.Fatalf("failed creating job tracker: %v", err)
+		}
+		impl, err := server.NewJobTrackerImpl(tracker)
+		if err != nil {
+			log.Fatalf("failed creating server: %v", err)
+		}
+		impl.SetPolicy(policy)
+		if err := impl.SetOwnerFile(*ownerFile); err != nil {
+			log.Fatal(err)
+		}
+		register = func(router chi.Router) http.Handler {
+			return genserver.HandlerFromMuxWithBaseURL(impl, router, *path)
+		}
+	}
+
+	router := chi.NewRouter()
+	router.Use(middleware.Logger)
+	if *clientCA != "" {
+		router.Use(server.ClientCertAuth())
+	}
+	if *tokenFile != "" {
+		tokens, err := server.LoadTokenFile(*tokenFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		router.Use(server.BearerTokenAuth(tokens))
+	}
+	if *apiKeyFile != "" {
+		keys, err := server.LoadTokenFile(*apiKeyFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		router.Use(server.APIKeyAuth(*apiKeyHeader, keys))
+	}
+
+	s := &http.Server{
+		Addr:              *addr,
+		Handler:           register(router),
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       10 * time.Second,
+		// wait and event requests extend the deadline
+		WriteTimeout:   10 * time.Second,
+		MaxHeaderBytes: 1 << 20,
+	}
+	if !tlsEnabled {
+		log.Printf("serving plain http on %s", *addr)
+		log.Fatal(s.ListenAndServe())
+	}
+	var err error
+	s.TLSConfig, err = server.TLSConfig(*tlsCert, *tlsKey, *clientCA)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("serving https on %s", *addr)
+	log.Fatal(s.ListenAndServeTLS("", ""))
+}
+
+// newSessionManager creates the SessionManager of a backend which
+// stores its job sessions in the given file.
+func newSessionManager(backend, dbpath string) (*drmaa2os.SessionManager, error) {
+	switch backend {
+	case "process":
+		return drmaa2os.NewDefaultSessionManager(dbpath)
+	case "docker":
+		return drmaa2os.NewDockerSessionManager(dbpath)
+	case "kubernetes":
+		return drmaa2os.NewKubernetesSessionManager(nil, dbpath)
+	}
+	return nil, fmt.Errorf("unknown backend %s", backend)
+}
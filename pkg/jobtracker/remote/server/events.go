@@ -0,0 +1,167 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/dgruber/drmaa2interface"
+	"github.com/dgruber/drmaa2os/pkg/helper"
+	"github.com/dgruber/drmaa2os/pkg/jobtracker"
+	genserver "github.com/dgruber/drmaa2os/pkg/jobtracker/remote/server/generated"
+)
+
+// eventPollInterval is the interval in which job trackers which
+// can't push events are polled.
+const eventPollInterval = 500 * time.Millisecond
+
+// eventKeepAliveInterval is the interval in which comments are sent
+// to idle event streams so that closed connections are detected.
+const eventKeepAliveInterval = 15 * time.Second
+
+// Events streams the job events as Server-Sent Events until the client
+// closes the connection.
+func (jti *JobTrackerImpl) Events(w http.ResponseWriter, r *http.Request, params genserver.EventsParams) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+	events, unsubscribe, err := jti.events.subscribe()
+	if err != nil {
+		log.Printf("failed subscribing to job events: %v\n", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	defer unsubscribe()
+
+	// the write timeout of the server must not abort the stream
+	http.NewResponseController(w).SetWriteDeadline(time.Time{})
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(eventKeepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+		case notification := <-events:
+			if params.JobID != nil && *params.JobID != notification.JobID {
+				continue
+			}
+			data, err := json.Marshal(ConvertNotification(notification))
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "event: job\ndata: %s\n\n", data)
+		}
+		flusher.Flush()
+	}
+}
+
+// eventBroker distributes the events of the job tracker to all event
+// streams. The job tracker is only observed while there are streams.
+type eventBroker struct {
+	sync.Mutex
+	tracker     jobtracker.JobTracker
+	subscribers map[chan drmaa2interface.Notification]struct{}
+	stopSource  func()
+}
+
+func newEventBroker(tracker jobtracker.JobTracker) *eventBroker {
+	return &eventBroker{
+		tracker:     tracker,
+		subscribers: make(map[chan drmaa2interface.Notification]struct{}),
+	}
+}
+
+// subscribe returns a channel emitting all job events until the
+// returned function is called. Events are dropped for subscribers
+// which don't consume them in time.
+func (b *eventBroker) subscribe() (chan drmaa2interface.Notification, func(), error) {
+	b.Lock()
+	defer b.Unlock()
+	if len(b.subscribers) == 0 {
+		if err := b.startSource(); err != nil {
+			return nil, nil, err
+		}
+	}
+	ch := make(chan drmaa2interface.Notification, 128)
+	b.subscribers[ch] = struct{}{}
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.Lock()
+			delete(b.subscribers, ch)
+			var stopSource func()
+			if len(b.subscribers) == 0 {
+				stopSource = b.stopSource
+				b.stopSource = nil
+			}
+			b.Unlock()
+			if stopSource != nil {
+				stopSource()
+			}
+		})
+	}, nil
+}
+
+// startSource forwards the events of the job tracker to the subscribers.
+// Job trackers which implement the jobtracker.EventNotifier interface
+// push their events, all others are polled. Must be called while
+// holding the lock.
+func (b *eventBroker) startSource() error {
+	var events drmaa2interface.EventChannel
+	var stopEvents func()
+	if notifier, ok := b.tracker.(jobtracker.EventNotifier); ok {
+		var err error
+		events, stopEvents, err = notifier.RegisterEventNotification()
+		if err != nil {
+			return err
+		}
+	} else {
+		events, stopEvents = helper.PollEvents(b.tracker, "", eventPollInterval)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case notification, open := <-events:
+				if !open {
+					return
+				}
+				b.publish(notification)
+			}
+		}
+	}()
+
+	b.stopSource = func() {
+		close(done)
+		stopEvents()
+	}
+	return nil
+}
+
+func (b *eventBroker) publish(notification drmaa2interface.Notification) {
+	b.Lock()
+	defer b.Unlock()
+	for ch := range b.subscribers {
+		select {
+		case ch <- notification:
+		default:
+		}
+	}
+}
@@ -0,0 +1,314 @@
+// Package gensessionserver provides primitives to interact with the openapi HTTP API.
+//
+// Code generated by github.com/deepmap/oapi-codegen version v1.8.2 DO NOT EDIT.
+package gensessionserver
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/deepmap/oapi-codegen/pkg/runtime"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/go-chi/chi/v5"
+)
+
+// CreateSessionInput defines model for CreateSessionInput.
+type CreateSessionInput struct {
+	Backend *string `json:"backend,omitempty"`
+	Contact *string `json:"contact,omitempty"`
+	Name    string  `json:"name"`
+}
+
+// Session defines model for Session.
+type Session struct {
+	Backend string `json:"backend"`
+	Name    string `json:"name"`
+}
+
+// CreateSessionJSONBody defines parameters for CreateSession.
+type CreateSessionJSONBody CreateSessionInput
+
+// CreateSessionJSONRequestBody defines body for CreateSession for application/json ContentType.
+type CreateSessionJSONRequestBody CreateSessionJSONBody
+
+// ServerInterface represents all server handlers.
+type ServerInterface interface {
+	// lists the backends of the server
+	// (GET /backends)
+	ListBackends(w http.ResponseWriter, r *http.Request)
+	// lists the job sessions
+	// (GET /sessions)
+	ListSessions(w http.ResponseWriter, r *http.Request)
+	// creates a job session
+	// (POST /sessions)
+	CreateSession(w http.ResponseWriter, r *http.Request)
+	// destroys a job session
+	// (DELETE /sessions/{name})
+	DestroySession(w http.ResponseWriter, r *http.Request, name string)
+	// opens a job session
+	// (GET /sessions/{name})
+	OpenSession(w http.ResponseWriter, r *http.Request, name string)
+}
+
+// ServerInterfaceWrapper converts contexts to parameters.
+type ServerInterfaceWrapper struct {
+	Handler            ServerInterface
+	HandlerMiddlewares []MiddlewareFunc
+}
+
+type MiddlewareFunc func(http.HandlerFunc) http.HandlerFunc
+
+// ListBackends operation middleware
+func (siw *ServerInterfaceWrapper) ListBackends(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListBackends(w, r)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
+// ListSessions operation middleware
+func (siw *ServerInterfaceWrapper) ListSessions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListSessions(w, r)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
+// CreateSession operation middleware
+func (siw *ServerInterfaceWrapper) CreateSession(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CreateSession(w, r)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
+// DestroySession operation middleware
+func (siw *ServerInterfaceWrapper) DestroySession(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "name" -------------
+	var name string
+
+	err = runtime.BindStyledParameter("simple", false, "name", chi.URLParam(r, "name"), &name)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter name: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DestroySession(w, r, name)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
+// OpenSession operation middleware
+func (siw *ServerInterfaceWrapper) OpenSession(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "name" -------------
+	var name string
+
+	err = runtime.BindStyledParameter("simple", false, "name", chi.URLParam(r, "name"), &name)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter name: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.OpenSession(w, r, name)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
+// Handler creates http.Handler with routing matching OpenAPI spec.
+func Handler(si ServerInterface) http.Handler {
+	return HandlerWithOptions(si, ChiServerOptions{})
+}
+
+type ChiServerOptions struct {
+	BaseURL     string
+	BaseRouter  chi.Router
+	Middlewares []MiddlewareFunc
+}
+
+// HandlerFromMux creates http.Handler with routing matching OpenAPI spec based on the provided mux.
+func HandlerFromMux(si ServerInterface, r chi.Router) http.Handler {
+	return HandlerWithOptions(si, ChiServerOptions{
+		BaseRouter: r,
+	})
+}
+
+func HandlerFromMuxWithBaseURL(si ServerInterface, r chi.Router, baseURL string) http.Handler {
+	return HandlerWithOptions(si, ChiServerOptions{
+		BaseURL:    baseURL,
+		BaseRouter: r,
+	})
+}
+
+// HandlerWithOptions creates http.Handler with additional options
+func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handler {
+	r := options.BaseRouter
+
+	if r == nil {
+		r = chi.NewRouter()
+	}
+	wrapper := ServerInterfaceWrapper{
+		Handler:            si,
+		HandlerMiddlewares: options.Middlewares,
+	}
+
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/backends", wrapper.ListBackends)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/sessions", wrapper.ListSessions)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/sessions", wrapper.CreateSession)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/sessions/{name}", wrapper.DestroySession)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/sessions/{name}", wrapper.OpenSession)
+	})
+
+	return r
+}
+
+// Base64 encoded, gzipped, json marshaled Swagger object
+var swaggerSpec = []string{
+
+	"H4sIAAAAAAAC/7RWT2/jthP9KgR/v0MLqJLj7KW6ZTdBkWK3KTZ7S3OgpbHFhCJZDmWvEfi7F0NKiv4l",
+	"SJv2ZIvkzDy+ecOZJ16Y2hoN2iPPnzgWFdQi/P3kQHi4BURp9LW2jadV64wF5yWEMxtRPIIu6S98F7VV",
+	"wHNemuIRHE+4P1r6Ru+k3vFTwgujvSiCn9meFjWM/XgQ9dnczSnhDv5spIOS53fR7r4/ZTYPUHhy2CJ/",
+	"N+h3AUv6aHOIZCD11pDzATMxHr8UWoJiv7hmE3A1TvGcV95bzLNsJ33VbNLC1Fm5C0ey0tVCrA0S5hKw",
+	"cNL6cH9++fXLxcX65pa1lHwRWuzAsYvfr5lQyhyQecPqsMp8BezBbBjGs8jMlgnmoDYeGILbg2OHShYV",
+	"qwx6ZHWjvLRqbJSyK1FUwyXWICAzGsgfxWh5we47uk7Ztxi/jTv0IBy0IEu2OQajX83mmyNH8TIbUObA",
+	"sg5F9kRcnlKecCUL0AgDfi+sKCpg63Q1IjfPssPhkIqwmxq3y1pTzD5ff7r67fbqp3W6SitfKyLaSx8k",
+	"MWeWJ3wPLiqQn6WrdEXnjQUtrOQ5Pw9LCbfCV0GWWccIfewgaGGcx6/gG6cx3JxugTMuf1DyEZh1pgDE",
+	"hEVRJ+yRBKLBA/7Y5q4Qmm2AklKyrXHj5P2heUDqBAW+LnnOP0v0Hzt8pHO0hkghkOvVqpMw6ABbWKtk",
+	"EayzBzT6+WUZFdIdb5HypKu/+4RLDzUuPhHtgnBOHGP5jPlZllQoTGzqWrgjz7mSpNqXFRiO9xJ6Uy5m",
+	"5aJU7/wlMm+7AO8ks2fr/w62POf/y54f9Cwew6wN9hYOp3d5A49DEwphDS4QFtsJTmq6Qal3wctO7kF3",
+	"tDHjwmIJW9Eo/7w8BMTklmnT70mMPpYYH/UyHt9pQP/RlMe/xfZrJC/0y9O4J3jXwOmd+X5TmudpLQK4",
+	"csg9pepDjD4to5JJws+scKIGD47y0ehHbQ59iqL5z3PzYXrhe1CJUA5EeZwoqFhSxLj82hc8BlHgYUFX",
+	"yiA50SUrAb0zx4nHlLpEr+VpQ9HGM7HdQuGhTNmNVrGxNBjanGEdcTNbHddEWUuNwVVopXTUdEiY9Etq",
+	"vIy7QznOFfEyqa1zaFNwPj8d0EuMl5ujmtwluvnwelBytTWNLidJXOacXC4+mzcWiK1WGVT7wyBomK+E",
+	"Z9JjnAHaNvWPuv4C8RT9ddb/6zo0FjSU/xr7JtI59dfXLfL8buqX2FmoBk7DKM/DPMK7qTf+TN+wZMDD",
+	"dAa+DwDD+7wUnGZGpkyktR+8nqLBKdvTWL0XToqNinmJO5Gd0AsGQ7CwMm2HCZqEecJBNzXNFd0JiqQo",
+	"Zn6+Pl/TuNXOeOONM35/Iuh/DQDw9tfXDg0AAA==",
+}
+
+// GetSwagger returns the content of the embedded swagger specification file
+// or error if failed to decode
+func decodeSpec() ([]byte, error) {
+	zipped, err := base64.StdEncoding.DecodeString(strings.Join(swaggerSpec, ""))
+	if err != nil {
+		return nil, fmt.Errorf("error base64 decoding spec: %s", err)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(zipped))
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing spec: %s", err)
+	}
+	var buf bytes.Buffer
+	_, err = buf.ReadFrom(zr)
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing spec: %s", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+var rawSpec = decodeSpecCached()
+
+// a naive cached of a decoded swagger spec
+func decodeSpecCached() func() ([]byte, error) {
+	data, err := decodeSpec()
+	return func() ([]byte, error) {
+		return data, err
+	}
+}
+
+// Constructs a synthetic filesystem for resolving external references when loading openapi specifications.
+func PathToRawSpec(pathToFile string) map[string]func() ([]byte, error) {
+	var res = make(map[string]func() ([]byte, error))
+	if len(pathToFile) > 0 {
+		res[pathToFile] = rawSpec
+	}
+
+	return res
+}
+
+// GetSwagger returns the Swagger specification corresponding to the generated code
+// in this file. The external references of Swagger specification are resolved.
+// The logic of resolving external references is tightly connected to "import-mapping" feature.
+// Externally referenced files must be embedded in the corresponding golang packages.
+// Urls can be supported but this task was out of the scope.
+func GetSwagger() (swagger *openapi3.T, err error) {
+	var resolvePath = PathToRawSpec("")
+
+	loader := openapi3.NewLoader()
+	loader.IsExternalRefsAllowed = true
+	loader.ReadFromURIFunc = func(loader *openapi3.Loader, url *url.URL) ([]byte, error) {
+		var pathToFile = url.String()
+		pathToFile = path.Clean(pathToFile)
+		getSpec, ok := resolvePath[pathToFile]
+		if !ok {
+			err1 := fmt.Errorf("path not found: %s", pathToFile)
+			return nil, err1
+		}
+		return getSpec()
+	}
+	var specData []byte
+	specData, err = rawSpec()
+	if err != nil {
+		return
+	}
+	swagger, err = loader.LoadFromData(specData)
+	if err != nil {
+		return
+	}
+	return
+}
+
@@ -28,6 +28,10 @@ func ConvertJobInfo(in drmaa2interface.JobInfo) genserver.JobInfo {
 }
 
 func ConvertJobTemplateToDRMAA2(in genserver.JobTemplate) drmaa2interface.JobTemplate {
+	// the extension is optional
+	if in.Extension == nil {
+		in.Extension = &genserver.JobTemplate_Extension{}
+	}
 	return drmaa2interface.JobTemplate{
 		AccountingID:      in.AccountingID,
 		Args:              in.Args,
@@ -88,3 +92,100 @@ func ConvertJobState(in string) genserver.JobState {
 	}
 	return genserver.JobStateUndetermined
 }
+
+func ConvertJobStateToDRMAA2(in string) drmaa2interface.JobState {
+	switch in {
+	case string(genserver.JobStateDone):
+		return drmaa2interface.Done
+	case string(genserver.JobStateFailed):
+		return drmaa2interface.Failed
+	case string(genserver.JobStateQueued):
+		return drmaa2interface.Queued
+	case string(genserver.JobStateQueuedHeld):
+		return drmaa2interface.QueuedHeld
+	case string(genserver.JobStateRequeued):
+		return drmaa2interface.Requeued
+	case string(genserver.JobStateRequeuedHeld):
+		return drmaa2interface.RequeuedHeld
+	case string(genserver.JobStateRunning):
+		return drmaa2interface.Running
+	case string(genserver.JobStateSuspended):
+		return drmaa2interface.Suspended
+	case string(genserver.JobStateUndetermined):
+		return drmaa2interface.Undetermined
+	case string(genserver.JobStateUnset):
+		return drmaa2interface.Unset
+	}
+	return drmaa2interface.Undetermined
+}
+
+func ConvertNotification(in drmaa2interface.Notification) genserver.JobEvent {
+	event := genserver.JobEventEventNewState
+	switch in.Evt {
+	case drmaa2interface.Migrated:
+		event = genserver.JobEventEventMigrated
+	case drmaa2interface.AttributeChange:
+		event = genserver.JobEventEventAttributeChange
+	}
+	return genserver.JobEvent{
+		JobID:    genserver.JobID(in.JobID),
+		Event:    event,
+		JobState: ConvertJobState(in.State.String()),
+	}
+}
+
+func ConvertJobTemplate(in drmaa2interface.JobTemplate) genserver.JobTemplate {
+	return genserver.JobTemplate{
+		AccountingID:      in.AccountingID,
+		Args:              in.Args,
+		CandidateMachines: in.CandidateMachines,
+		DeadlineTime:      in.DeadlineTime,
+		Email:             in.Email,
+		EmailOnStarted:    in.EmailOnStarted,
+		EmailOnTerminated: in.EmailOnTerminated,
+		ErrorPath:         in.ErrorPath,
+		Extension:         &genserver.JobTemplate_Extension{AdditionalProperties: in.ExtensionList},
+		InputPath:         in.InputPath,
+		JobCategory:       in.JobCategory,
+		JobEnvironment:    genserver.JobTemplate_JobEnvironment{AdditionalProperties: in.JobEnvironment},
+		JobName:           in.JobName,
+		JoinFiles:         in.JoinFiles,
+		MachineArch:       in.MachineArch,
+		MachineOs:         in.MachineOs,
+		MaxSlots:          in.MaxSlots,
+		MinPhysMemory:     in.MinPhysMemory,
+		MinSlots:          in.MinSlots,
+		OutputPath:        in.OutputPath,
+		Priority:          in.Priority,
+		QueueName:         in.QueueName,
+		RemoteCommand:     in.RemoteCommand,
+		ReRunnable:        in.ReRunnable,
+		ReservationID:     in.ReservationID,
+		ResourceLimits:    genserver.JobTemplate_ResourceLimits{AdditionalProperties: in.ResourceLimits},
+		StageInFiles:      genserver.JobTemplate_StageInFiles{AdditionalProperties: in.StageInFiles},
+		StageOutFiles:     genserver.JobTemplate_StageOutFiles{AdditionalProperties: in.StageOutFiles},
+		StartTime:         in.StartTime,
+		SubmitAsHold:      in.SubmitAsHold,
+		WorkingDirectory:  in.WorkingDirectory,
+	}
+}
+
+func ConvertMachine(in drmaa2interface.Machine) genserver.Machine {
+	return genserver.Machine{
+		Name:           in.Name,
+		Available:      in.Available,
+		Sockets:        in.Sockets,
+		CoresPerSocket: in.CoresPerSocket,
+		ThreadsPerCore: in.ThreadsPerCore,
+		Load:           in.Load,
+		PhysicalMemory: in.PhysicalMemory,
+		VirtualMemory:  in.VirtualMemory,
+		Architecture:   in.Architecture.String(),
+		OsVersion: genserver.Version{
+			Major: in.OSVersion.Major,
+			Minor: in.OSVersion.Minor,
+		},
+		Os:        in.OS.String(),
+		Extension: &genserver.Machine_Extension{AdditionalProperties: in.ExtensionList},
+	}
+}
@@ -3,6 +3,7 @@ package server_test
 import (
 	"github.com/dgruber/drmaa2interface"
 	"github.com/dgruber/drmaa2os/pkg/jobtracker/remote/client"
+	genclient "github.com/dgruber/drmaa2os/pkg/jobtracker/remote/client/generated"
 	. "github.com/dgruber/drmaa2os/pkg/jobtracker/remote/server"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -22,6 +23,28 @@ var _ = Describe("Helper", func() {
 				drmaa2interface.Unset,
 				drmaa2interface.Undetermined} {
 				Expect(client.ConvertJobStateToDRMAA2(string(ConvertJobState(state.String())))).To(Equal(state))
+				Expect(ConvertJobStateToDRMAA2(string(client.ConvertJobState(state)))).To(Equal(state))
+			}
+
+		})
+
+		It("should map job events between DRMAA2 and OpenAPI spec back and forth", func() {
+
+			for _, event := range []drmaa2interface.Event{
+				drmaa2interface.NewState, drmaa2interface.Migrated,
+				drmaa2interface.AttributeChange} {
+				n := drmaa2interface.Notification{
+					Evt:         event,
+					JobID:       "1",
+					SessionName: "session",
+					State:       drmaa2interface.Running,
+				}
+				jobEvent := ConvertNotification(n)
+				var converted genclient.JobEvent
+				converted.Event = genclient.JobEventEvent(jobEvent.Event)
+				converted.JobID = genclient.JobID(jobEvent.JobID)
+				converted.JobState = genclient.JobState(jobEvent.JobState)
+				Expect(client.ConvertJobEventToDRMAA2(converted, "session")).To(Equal(n))
 			}
 
 		})
@@ -5,21 +5,53 @@ import (
 	"io/ioutil"
 	"log"
 	"net/http"
+	"time"
 
+	"github.com/dgruber/drmaa2interface"
+	"github.com/dgruber/drmaa2os/pkg/helper"
 	"github.com/dgruber/drmaa2os/pkg/jobtracker"
 	genserver "github.com/dgruber/drmaa2os/pkg/jobtracker/remote/server/generated"
 )
 
+// maxWaitTime limits the time a wait request blocks.
+const maxWaitTime = time.Minute
+
 type JobTrackerImpl struct {
 	jobTracker jobtracker.JobTracker
+	events     *eventBroker
+	policy     Policy
+	owners     *ownerStore
 }
 
 func NewJobTrackerImpl(jobTracker jobtracker.JobTracker) (*JobTrackerImpl, error) {
 	return &JobTrackerImpl{
 		jobTracker: jobTracker,
+		events:     newEventBroker(jobTracker),
+		policy:     &OwnerPolicy{},
+		owners:     newOwnerStore(),
 	}, nil
 }
 
+// SetPolicy replaces the default OwnerPolicy which decides who may
+// control and delete jobs and read their job templates. It must be
+// called before serving requests.
+func (jti *JobTrackerImpl) SetPolicy(policy Policy) {
+	jti.policy = policy
+}
+
+// SetOwnerFile persists the owners of the jobs in the given file so
+// that they are kept when the server is restarted with a persistent job
+// tracker. The owners are read from the file if it exists. It must be
+// called before serving requests.
+func (jti *JobTrackerImpl) SetOwnerFile(path string) error {
+	owners, err := loadOwnerStore(path)
+	if err != nil {
+		return err
+	}
+	jti.owners = owners
+	return nil
+}
+
 func (jti *JobTrackerImpl) AddArrayJob(w http.ResponseWriter, r *http.Request) {
 	body, err := ioutil.ReadAll(r.Body)
 	if err != nil {
@@ -47,6 +79,9 @@ func (jti *JobTrackerImpl) AddArrayJob(w http.ResponseWriter, r *http.Request) {
 	var o genserver.AddArrayJobOutput
 	if err != nil {
 		o.Error = genserver.Error(err.Error())
+	} else {
+		tasks, _ := jti.jobTracker.ListArrayJobs(id)
+		jti.recordOwner(r, append(tasks, id)...)
 	}
 	o.JobID = genserver.JobID(id)
 	out, _ := json.Marshal(o)
@@ -75,6 +110,7 @@ func (jti *JobTrackerImpl) AddJob(w http.ResponseWriter, r *http.Request) {
 		addJobOutput.Error = genserver.Error(err.Error())
 	} else {
 		addJobOutput.Error = genserver.Error("")
+		jti.recordOwner(r, id)
 	}
 
 	out, err := json.Marshal(addJobOutput)
@@ -87,10 +123,15 @@ func (jti *JobTrackerImpl) AddJob(w http.ResponseWriter, r *http.Request) {
 }
 
 func (jti *JobTrackerImpl) DeleteJob(w http.ResponseWriter, r *http.Request, params genserver.DeleteJobParams) {
+	if !jti.authorize(w, r, params.JobID, ActionDelete) {
+		return
+	}
 	err := jti.jobTracker.DeleteJob(params.JobID)
 	var response genserver.Error
 	if err != nil {
 		response = genserver.Error(err.Error())
+	} else {
+		jti.owners.remove(params.JobID)
 	}
 	out, err := json.Marshal(response)
 	if err != nil {
@@ -102,6 +143,9 @@ func (jti *JobTrackerImpl) DeleteJob(w http.ResponseWriter, r *http.Request, par
 }
 
 func (jti *JobTrackerImpl) JobControl(w http.ResponseWriter, r *http.Request, params genserver.JobControlParams) {
+	if !jti.authorize(w, r, params.JobID, ActionControl) {
+		return
+	}
 	err := jti.jobTracker.JobControl(params.JobID, string(params.Action))
 	var response genserver.Error
 	if err != nil {
@@ -122,6 +166,9 @@ func (jti *JobTrackerImpl) JobInfo(w http.ResponseWriter, r *http.Request, param
 	if err != nil {
 		output.Error = genserver.Error(err.Error())
 	}
+	if owner, exists := jti.owners.get(params.JobID); exists {
+		ji.JobOwner = owner
+	}
 	output.JobInfo = ConvertJobInfo(ji)
 	out, err := json.Marshal(output)
 	if err != nil {
@@ -188,6 +235,59 @@ func (jti *JobTrackerImpl) ListJobs(w http.ResponseWriter, r *http.Request, para
 	success(w, out)
 }
 
+// Wait blocks until the job is in one of the given states, the timeout
+// (which is limited to maxWaitTime) is reached, or the request is
+// cancelled. The state of the job is returned unless the request was
+// cancelled.
+func (jti *JobTrackerImpl) Wait(w http.ResponseWriter, r *http.Request, params genserver.WaitParams) {
+	states := make([]drmaa2interface.JobState, 0, len(params.State))
+	for _, state := range params.State {
+		states = append(states, ConvertJobStateToDRMAA2(string(state)))
+	}
+	var timeout time.Duration
+	if params.Timeout != nil {
+		timeout = time.Duration(*params.Timeout * float64(time.Second))
+	}
+	if timeout > maxWaitTime {
+		timeout = maxWaitTime
+	}
+	if timeout > 0 {
+		// the write timeout of the server must not abort the long-poll
+		http.NewResponseController(w).SetWriteDeadline(time.Now().Add(timeout + 10*time.Second))
+		// errors are reported by the job state query below (note
+		// that a job tracker might interpret a timeout of 0 as infinite)
+		waited := make(chan struct{})
+		go func() {
+			jti.jobTracker.Wait(params.JobID, timeout, states...)
+			close(waited)
+		}()
+		select {
+		case <-waited:
+		case <-r.Context().Done():
+			// the client is gone, the job tracker returns at the
+			// latest when the timeout is reached
+			return
+		}
+	}
+
+	var output genserver.WaitOutput
+	state, substate, err := jti.jobTracker.JobState(params.JobID)
+	if err != nil {
+		e := genserver.Error(err.Error())
+		output.Error = &e
+	}
+	output.JobState = ConvertJobState(state.String())
+	output.JobSubState = genserver.JobSubState(substate)
+	output.Timeout = err == nil && !helper.IsInExpectedState(state, states...)
+	out, err := json.Marshal(output)
+	if err != nil {
+		log.Printf("failed marshalling body for wait response: %v\n", err)
+		http.Error(w, "internal error", http.StatusBadRequest)
+		return
+	}
+	success(w, out)
+}
+
 func success(w http.ResponseWriter, out []byte) {
 	w.Header().Set("Content-Type", "json")
 	w.WriteHeader(200)
@@ -0,0 +1,71 @@
+package server_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	. "github.com/dgruber/drmaa2os/pkg/jobtracker/remote/server"
+	genserver "github.com/dgruber/drmaa2os/pkg/jobtracker/remote/server/generated"
+	"github.com/dgruber/drmaa2os/pkg/jobtracker/simpletracker"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("JobTrackerImpl", func() {
+
+	Context("wait", func() {
+
+		var (
+			testServer *httptest.Server
+			finished   chan struct{}
+		)
+
+		BeforeEach(func() {
+			impl, err := NewJobTrackerImpl(simpletracker.New("drmaa2ostestjobsession"))
+			Expect(err).To(BeNil())
+			handler := genserver.Handler(impl)
+			finished = make(chan struct{}, 1)
+			testServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				handler.ServeHTTP(w, r)
+				if r.URL.Path == "/wait" {
+					finished <- struct{}{}
+				}
+			}))
+		})
+
+		AfterEach(func() {
+			testServer.Close()
+		})
+
+		It("should stop waiting when the request is cancelled", func() {
+			resp, err := http.Post(testServer.URL+"/addjob", "application/json",
+				strings.NewReader(`{"remoteCommand":"/bin/sleep","args":["30"]}`))
+			Expect(err).To(BeNil())
+			var output struct {
+				JobID string
+			}
+			Expect(json.NewDecoder(resp.Body).Decode(&output)).To(Succeed())
+			resp.Body.Close()
+			defer http.Get(testServer.URL + "/jobcontrol?jobID=" + output.JobID + "&action=terminate")
+
+			ctx, cancel := context.WithCancel(context.Background())
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+				testServer.URL+"/wait?jobID="+output.JobID+"&state=done&timeout=30", nil)
+			Expect(err).To(BeNil())
+			go func() {
+				defer GinkgoRecover()
+				_, err := http.DefaultClient.Do(req)
+				Expect(err).NotTo(BeNil())
+			}()
+			Consistently(finished, time.Millisecond*300).ShouldNot(Receive())
+			cancel()
+			Eventually(finished, time.Second*5).Should(Receive())
+		})
+
+	})
+
+})
@@ -0,0 +1,158 @@
+package server
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/dgruber/drmaa2os/pkg/jobtracker"
+	genserver "github.com/dgruber/drmaa2os/pkg/jobtracker/remote/server/generated"
+)
+
+// JobTemplate returns the job template of a job. When the job tracker
+// does not store job templates 501 is returned, when the user may not
+// read the job template 403.
+func (jti *JobTrackerImpl) JobTemplate(w http.ResponseWriter, r *http.Request, params genserver.JobTemplateParams) {
+	templater, ok := jti.jobTracker.(jobtracker.JobTemplater)
+	if !ok {
+		http.Error(w, "job tracker does not provide job templates", http.StatusNotImplemented)
+		return
+	}
+	if !jti.authorize(w, r, params.JobID, ActionTemplate) {
+		return
+	}
+	var output genserver.JobTemplateOutput
+	jt, err := templater.JobTemplate(params.JobID)
+	if err != nil {
+		output.Error = genserver.Error(err.Error())
+	}
+	output.JobTemplate = ConvertJobTemplate(jt)
+	out, err := json.Marshal(output)
+	if err != nil {
+		log.Printf("failed marshalling body for jobtemplate response: %v\n", err)
+		http.Error(w, "internal error", http.StatusBadRequest)
+		return
+	}
+	success(w, out)
+}
+
+// GetAllJobIDs returns the IDs of all jobs of the backend. When the job
+// tracker does not implement the Monitorer interface 501 is returned.
+func (jti *JobTrackerImpl) GetAllJobIDs(w http.ResponseWriter, r *http.Request) {
+	monitorer, ok := jti.monitorer(w)
+	if !ok {
+		return
+	}
+	var output genserver.JobIDsOutput
+	ids, err := monitorer.GetAllJobIDs(nil)
+	if err != nil {
+		output.Error = errorOutput(err)
+	}
+	output.JobIDs = make([]genserver.JobID, 0, len(ids))
+	for _, id := range ids {
+		output.JobIDs = append(output.JobIDs, genserver.JobID(id))
+	}
+	out, err := json.Marshal(output)
+	if err != nil {
+		log.Printf("failed marshalling body for getalljobids response: %v\n", err)
+		http.Error(w, "internal error", http.StatusBadRequest)
+		return
+	}
+	success(w, out)
+}
+
+// GetAllQueueNames returns the queue names of the backend. When the job
+// tracker does not implement the Monitorer interface 501 is returned.
+func (jti *JobTrackerImpl) GetAllQueueNames(w http.ResponseWriter, r *http.Request, params genserver.GetAllQueueNamesParams) {
+	monitorer, ok := jti.monitorer(w)
+	if !ok {
+		return
+	}
+	var names []string
+	if params.Name != nil {
+		names = *params.Name
+	}
+	var output genserver.QueueNamesOutput
+	queues, err := monitorer.GetAllQueueNames(names)
+	if err != nil {
+		output.Error = errorOutput(err)
+	}
+	output.QueueNames = queues
+	if output.QueueNames == nil {
+		output.QueueNames = []string{}
+	}
+	out, err := json.Marshal(output)
+	if err != nil {
+		log.Printf("failed marshalling body for getallqueuenames response: %v\n", err)
+		http.Error(w, "internal error", http.StatusBadRequest)
+		return
+	}
+	success(w, out)
+}
+
+// GetAllMachines returns the machines of the backend. When the job
+// tracker does not implement the Monitorer interface 501 is returned.
+func (jti *JobTrackerImpl) GetAllMachines(w http.ResponseWriter, r *http.Request, params genserver.GetAllMachinesParams) {
+	monitorer, ok := jti.monitorer(w)
+	if !ok {
+		return
+	}
+	var names []string
+	if params.Name != nil {
+		names = *params.Name
+	}
+	var output genserver.MachinesOutput
+	machines, err := monitorer.GetAllMachines(names)
+	if err != nil {
+		output.Error = errorOutput(err)
+	}
+	output.Machines = make([]genserver.Machine, 0, len(machines))
+	for _, machine := range machines {
+		output.Machines = append(output.Machines, ConvertMachine(machine))
+	}
+	out, err := json.Marshal(output)
+	if err != nil {
+		log.Printf("failed marshalling body for getallmachines response: %v\n", err)
+		http.Error(w, "internal error", http.StatusBadRequest)
+		return
+	}
+	success(w, out)
+}
+
+// JobInfoFromMonitor returns the JobInfo of any job of the backend. When
+// the job tracker does not implement the Monitorer interface 501 is
+// returned.
+func (jti *JobTrackerImpl) JobInfoFromMonitor(w http.ResponseWriter, r *http.Request, params genserver.JobInfoFromMonitorParams) {
+	monitorer, ok := jti.monitorer(w)
+	if !ok {
+		return
+	}
+	var output genserver.JobInfoOutput
+	ji, err := monitorer.JobInfoFromMonitor(params.JobID)
+	if err != nil {
+		output.Error = genserver.Error(err.Error())
+	}
+	output.JobInfo = ConvertJobInfo(ji)
+	out, err := json.Marshal(output)
+	if err != nil {
+		log.Printf("failed marshalling body for jobinfofrommonitor response: %v\n", err)
+		http.Error(w, "internal error", http.StatusBadRequest)
+		return
+	}
+	success(w, out)
+}
+
+// monitorer returns the Monitorer interface of the job tracker or
+// responds with 501 when the job tracker does not implement it.
+func (jti *JobTrackerImpl) monitorer(w http.ResponseWriter) (jobtracker.Monitorer, bool) {
+	monitorer, ok := jti.jobTracker.(jobtracker.Monitorer)
+	if !ok {
+		http.Error(w, "job tracker does not implement monitoring", http.StatusNotImplemented)
+	}
+	return monitorer, ok
+}
+
+func errorOutput(err error) *genserver.Error {
+	e := genserver.Error(err.Error())
+	return &e
+}
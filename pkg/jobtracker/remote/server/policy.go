@@ -0,0 +1,227 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Action is an operation on a job or a job session which requires an
+// authorization.
+type Action string
+
+const (
+	// ActionControl changes the state of a job (JobControl).
+	ActionControl Action = "control"
+	// ActionDelete removes a finished job (DeleteJob).
+	ActionDelete Action = "delete"
+	// ActionTemplate reads the job template of a job (JobTemplate).
+	ActionTemplate Action = "template"
+	// ActionDestroy destroys a job session (DestroySession).
+	ActionDestroy Action = "destroy"
+)
+
+// Policy decides if a user may perform an action on a job or a job
+// session. The user is the principal set by the authentication
+// middleware and the owner is the user who submitted the job or created
+// the job session. Both are empty when the server does not authenticate
+// users. The owner is also empty when the job or job session was not
+// created through the server (or before the server was restarted when
+// the owners are not persisted in an owner file).
+type Policy interface {
+	Allowed(principal, owner string, action Action) bool
+}
+
+// OwnerPolicy allows the owners of a job or job session and the admins
+// to perform all actions on it. Public actions can be performed by all
+// users. It is the default policy of the server.
+type OwnerPolicy struct {
+	// Admins may perform all actions on all jobs and job sessions.
+	Admins []string
+	// Public lists the actions all users may perform on all jobs and
+	// job sessions.
+	Public []Action
+}
+
+// Allowed implements the Policy interface.
+func (p *OwnerPolicy) Allowed(principal, owner string, action Action) bool {
+	if principal == owner {
+		return true
+	}
+	for _, admin := range p.Admins {
+		if principal != "" && principal == admin {
+			return true
+		}
+	}
+	for _, public := range p.Public {
+		if public == action {
+			return true
+		}
+	}
+	return false
+}
+
+// LoadPolicyFile reads an OwnerPolicy from a file. Each line contains
+// either "admin" followed by the names of admins or "public" followed
+// by actions (control, delete, template, destroy) which all users may
+// perform.
+// Empty lines and lines starting with # are ignored.
+func LoadPolicyFile(path string) (*OwnerPolicy, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading policy file: %v", err)
+	}
+	policy := &OwnerPolicy{}
+	for i, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("line %d of policy file %s: expected rule and values", i+1, path)
+		}
+		switch fields[0] {
+		case "admin":
+			policy.Admins = append(policy.Admins, fields[1:]...)
+		case "public":
+			for _, action := range fields[1:] {
+				switch Action(action) {
+				case ActionControl, ActionDelete, ActionTemplate, ActionDestroy:
+					policy.Public = append(policy.Public, Action(action))
+				default:
+					return nil, fmt.Errorf("line %d of policy file %s: unknown action %s", i+1, path, action)
+				}
+			}
+		default:
+			return nil, fmt.Errorf("line %d of policy file %s: unknown rule %s", i+1, path, fields[0])
+		}
+	}
+	return policy, nil
+}
+
+// ownerRecords contains the owners of jobs and job sessions. When a
+// file is set the owners are persisted in it so that they are kept
+// when the server is restarted.
+type ownerRecords struct {
+	sync.Mutex
+	owners map[string]string
+	// path is the JSON file of the owners or empty if the owners are
+	// kept in memory only
+	path string
+}
+
+// save writes the owners into the file. Must be called while holding
+// the lock.
+func (r *ownerRecords) save() {
+	if r.path == "" {
+		return
+	}
+	content, err := json.Marshal(r.owners)
+	if err != nil {
+		log.Printf("failed marshalling owners: %v\n", err)
+		return
+	}
+	tmp := r.path + ".tmp"
+	if err := os.WriteFile(tmp, content, 0600); err != nil {
+		log.Printf("failed writing owners file: %v\n", err)
+		return
+	}
+	if err := os.Rename(tmp, r.path); err != nil {
+		log.Printf("failed writing owners file: %v\n", err)
+	}
+}
+
+// ownerStore records the owners of the jobs (or job sessions) which
+// were created through the server. The IDs are prefixed so that the
+// owners of different kinds of objects can share the records.
+type ownerStore struct {
+	records *ownerRecords
+	prefix  string
+}
+
+func newOwnerStore() *ownerStore {
+	return &ownerStore{records: &ownerRecords{owners: make(map[string]string)}}
+}
+
+// loadOwnerStore returns an ownerStore which is persisted in the given
+// file. The owners are read from the file if it exists.
+func loadOwnerStore(path string) (*ownerStore, error) {
+	records := &ownerRecords{owners: make(map[string]string), path: path}
+	content, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed reading owners file: %v", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(content, &records.owners); err != nil {
+			return nil, fmt.Errorf("failed reading owners file %s: %v", path, err)
+		}
+	}
+	return &ownerStore{records: records}, nil
+}
+
+// scope returns an ownerStore which shares the records but prefixes
+// the IDs.
+func (s *ownerStore) scope(prefix string) *ownerStore {
+	return &ownerStore{records: s.records, prefix: s.prefix + prefix}
+}
+
+func (s *ownerStore) set(owner string, jobIDs ...string) {
+	s.records.Lock()
+	defer s.records.Unlock()
+	for _, id := range jobIDs {
+		s.records.owners[s.prefix+id] = owner
+	}
+	s.records.save()
+}
+
+func (s *ownerStore) get(jobID string) (string, bool) {
+	s.records.Lock()
+	defer s.records.Unlock()
+	owner, exists := s.records.owners[s.prefix+jobID]
+	return owner, exists
+}
+
+func (s *ownerStore) remove(jobID string) {
+	s.records.Lock()
+	defer s.records.Unlock()
+	delete(s.records.owners, s.prefix+jobID)
+	s.records.save()
+}
+
+// clear removes all owners of the scope.
+func (s *ownerStore) clear() {
+	s.records.Lock()
+	defer s.records.Unlock()
+	for id := range s.records.owners {
+		if strings.HasPrefix(id, s.prefix) {
+			delete(s.records.owners, id)
+		}
+	}
+	s.records.save()
+}
+
+// authorize checks if the user of the request may perform the action
+// on the job. Otherwise it responds with 403 Forbidden.
+func (jti *JobTrackerImpl) authorize(w http.ResponseWriter, r *http.Request, jobID string, action Action) bool {
+	principal, _ := Principal(r.Context())
+	owner, _ := jti.owners.get(jobID)
+	if jti.policy.Allowed(principal, owner, action) {
+		return true
+	}
+	http.Error(w, fmt.Sprintf("user %q is not allowed to %s job %s",
+		principal, action, jobID), http.StatusForbidden)
+	return false
+}
+
+// recordOwner stores the authenticated user of the request as owner of
+// the jobs.
+func (jti *JobTrackerImpl) recordOwner(r *http.Request, jobIDs ...string) {
+	if principal, ok := Principal(r.Context()); ok && principal != "" {
+		jti.owners.set(principal, jobIDs...)
+	}
+}
@@ -0,0 +1,179 @@
+package server_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/dgruber/drmaa2os/pkg/jobtracker/remote/server"
+	genserver "github.com/dgruber/drmaa2os/pkg/jobtracker/remote/server/generated"
+	"github.com/dgruber/drmaa2os/pkg/jobtracker/simpletracker"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Policy", func() {
+
+	Context("owner policy", func() {
+
+		It("should allow owners and admins to perform all actions", func() {
+			policy := &OwnerPolicy{Admins: []string{"admin"}}
+			for _, action := range []Action{ActionControl, ActionDelete, ActionTemplate, ActionDestroy} {
+				Expect(policy.Allowed("user1", "user1", action)).To(BeTrue())
+				Expect(policy.Allowed("admin", "user1", action)).To(BeTrue())
+				Expect(policy.Allowed("user2", "user1", action)).To(BeFalse())
+				Expect(policy.Allowed("user2", "", action)).To(BeFalse())
+				Expect(policy.Allowed("", "", action)).To(BeTrue())
+			}
+		})
+
+		It("should allow all users to perform public actions", func() {
+			policy := &OwnerPolicy{Public: []Action{ActionTemplate}}
+			Expect(policy.Allowed("user2", "user1", ActionTemplate)).To(BeTrue())
+			Expect(policy.Allowed("user2", "user1", ActionControl)).To(BeFalse())
+		})
+
+	})
+
+	Context("policy file", func() {
+
+		writeFile := func(content string) string {
+			path := filepath.Join(GinkgoT().TempDir(), "policy.txt")
+			Expect(os.WriteFile(path, []byte(content), 0600)).To(Succeed())
+			return path
+		}
+
+		It("should read the admins and public actions", func() {
+			policy, err := LoadPolicyFile(writeFile(
+				"# admins\nadmin alice bob\n\nadmin carol\npublic template destroy\n"))
+			Expect(err).To(BeNil())
+			Expect(policy.Admins).To(Equal([]string{"alice", "bob", "carol"}))
+			Expect(policy.Public).To(Equal([]Action{ActionTemplate, ActionDestroy}))
+		})
+
+		It("should reject invalid policy files", func() {
+			_, err := LoadPolicyFile(writeFile("public reboot\n"))
+			Expect(err).NotTo(BeNil())
+			_, err = LoadPolicyFile(writeFile("owner alice\n"))
+			Expect(err).NotTo(BeNil())
+			_, err = LoadPolicyFile(writeFile("admin\n"))
+			Expect(err).NotTo(BeNil())
+			_, err = LoadPolicyFile(filepath.Join(GinkgoT().TempDir(), "missing"))
+			Expect(err).NotTo(BeNil())
+		})
+
+	})
+
+	Context("job ownership", func() {
+
+		var testServer *httptest.Server
+
+		request := func(token, method, path, body string) (int, string) {
+			req, err := http.NewRequest(method, testServer.URL+path, strings.NewReader(body))
+			Expect(err).To(BeNil())
+			req.Header.Set("Authorization", "Bearer "+token)
+			resp, err := http.DefaultClient.Do(req)
+			Expect(err).To(BeNil())
+			defer resp.Body.Close()
+			out, err := io.ReadAll(resp.Body)
+			Expect(err).To(BeNil())
+			return resp.StatusCode, string(out)
+		}
+
+		addJob := func(token string) string {
+			code, body := request(token, http.MethodPost, "/addjob",
+				`{"remoteCommand":"/bin/sleep","args":["10"]}`)
+			Expect(code).To(Equal(http.StatusOK))
+			var output struct {
+				JobID string
+				Error string
+			}
+			Expect(json.Unmarshal([]byte(body), &output)).To(Succeed())
+			Expect(output.Error).To(Equal(""))
+			return output.JobID
+		}
+
+		BeforeEach(func() {
+			impl, err := NewJobTrackerImpl(simpletracker.New("drmaa2ostestjobsession"))
+			Expect(err).To(BeNil())
+			impl.SetPolicy(&OwnerPolicy{Admins: []string{"admin"}})
+			testServer = httptest.NewServer(BearerTokenAuth(map[string]string{
+				"token1":     "user1",
+				"token2":     "user2",
+				"adminToken": "admin",
+			})(genserver.Handler(impl)))
+		})
+
+		AfterEach(func() {
+			testServer.Close()
+		})
+
+		It("should keep the job owners when the server is restarted", func() {
+			tracker := simpletracker.New("drmaa2ostestjobsession")
+			ownerFile := filepath.Join(GinkgoT().TempDir(), "owners.json")
+			serve := func() {
+				testServer.Close()
+				impl, err := NewJobTrackerImpl(tracker)
+				Expect(err).To(BeNil())
+				Expect(impl.SetOwnerFile(ownerFile)).To(Succeed())
+				testServer = httptest.NewServer(BearerTokenAuth(map[string]string{
+					"token1": "user1",
+					"token2": "user2",
+				})(genserver.Handler(impl)))
+			}
+
+			serve()
+			jobID := addJob("token1")
+			serve()
+			code, _ := request("token2", http.MethodGet, "/jobcontrol?jobID="+jobID+"&action=terminate", "")
+			Expect(code).To(Equal(http.StatusForbidden))
+			code, body := request("token1", http.MethodGet, "/jobcontrol?jobID="+jobID+"&action=terminate", "")
+			Expect(code).To(Equal(http.StatusOK))
+			Expect(body).To(Equal(`""`))
+		})
+
+		It("should reject invalid owner files", func() {
+			ownerFile := filepath.Join(GinkgoT().TempDir(), "owners.json")
+			Expect(os.WriteFile(ownerFile, []byte("{"), 0600)).To(Succeed())
+			impl, err := NewJobTrackerImpl(simpletracker.New("drmaa2ostestjobsession"))
+			Expect(err).To(BeNil())
+			Expect(impl.SetOwnerFile(ownerFile)).NotTo(Succeed())
+		})
+
+		It("should report the submitting user as job owner", func() {
+			jobID := addJob("token1")
+			code, body := request("token2", http.MethodGet, "/jobinfo?jobID="+jobID, "")
+			Expect(code).To(Equal(http.StatusOK))
+			Expect(body).To(ContainSubstring(`"jobOwner":"user1"`))
+			request("token1", http.MethodGet, "/jobcontrol?jobID="+jobID+"&action=terminate", "")
+		})
+
+		It("should allow only the owner and admins to access a job", func() {
+			jobID := addJob("token1")
+
+			code, body := request("token2", http.MethodGet, "/jobtemplate?jobID="+jobID, "")
+			Expect(code).To(Equal(http.StatusForbidden))
+			Expect(body).To(ContainSubstring("user2"))
+			code, _ = request("token1", http.MethodGet, "/jobtemplate?jobID="+jobID, "")
+			Expect(code).To(Equal(http.StatusOK))
+
+			code, _ = request("token2", http.MethodGet, "/jobcontrol?jobID="+jobID+"&action=suspend", "")
+			Expect(code).To(Equal(http.StatusForbidden))
+			code, body = request("token1", http.MethodGet, "/jobcontrol?jobID="+jobID+"&action=suspend", "")
+			Expect(code).To(Equal(http.StatusOK))
+			Expect(body).To(Equal(`""`))
+			code, body = request("adminToken", http.MethodGet, "/jobcontrol?jobID="+jobID+"&action=terminate", "")
+			Expect(code).To(Equal(http.StatusOK))
+			Expect(body).To(Equal(`""`))
+
+			code, _ = request("token2", http.MethodGet, "/deletejob?jobID="+jobID, "")
+			Expect(code).To(Equal(http.StatusForbidden))
+		})
+
+	})
+
+})
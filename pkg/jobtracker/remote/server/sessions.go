@@ -0,0 +1,360 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/dgruber/drmaa2interface"
+	"github.com/dgruber/drmaa2os"
+	genserver "github.com/dgruber/drmaa2os/pkg/jobtracker/remote/server/generated"
+	gensessionserver "github.com/dgruber/drmaa2os/pkg/jobtracker/remote/server/generated/sessionmanager"
+)
+
+// errSessionNotFound is returned when no backend has a job session
+// with the requested name.
+var errSessionNotFound = errors.New("job session not found")
+
+// SessionServer hosts the job sessions of multiple SessionManagers
+// (backends) so that one server can serve many users. The job sessions
+// are managed by the SessionManager API and the jobs of a job session
+// by the JobTracker API below /sessions/{name}.
+type SessionServer struct {
+	sync.Mutex
+	backends       map[string]*drmaa2os.SessionManager
+	defaultBackend string
+	// policy authorizes the job operations in all job sessions and
+	// destroying job sessions
+	policy Policy
+	// sessions contains the open job sessions by name
+	sessions map[string]*hostedSession
+	// owners contains the users who created the job sessions and
+	// submitted the jobs of the job sessions
+	owners *ownerStore
+}
+
+// hostedSession is an open job session served by the JobTracker API.
+type hostedSession struct {
+	backend string
+	js      *drmaa2os.JobSession
+	handler http.Handler
+}
+
+// NewSessionServer creates a SessionServer for the given backends which
+// are SessionManagers by their names (like "process" or "docker"). Job
+// sessions which are created without a backend use the default backend.
+// If there is only one backend it is the default backend.
+func NewSessionServer(backends map[string]*drmaa2os.SessionManager, defaultBackend string) (*SessionServer, error) {
+	if len(backends) == 0 {
+		return nil, errors.New("no backends given")
+	}
+	if defaultBackend == "" && len(backends) == 1 {
+		for name := range backends {
+			defaultBackend = name
+		}
+	}
+	if _, exists := backends[defaultBackend]; !exists {
+		return nil, fmt.Errorf("default backend %q does not exist", defaultBackend)
+	}
+	return &SessionServer{
+		backends:       backends,
+		defaultBackend: defaultBackend,
+		policy:         &OwnerPolicy{},
+		sessions:       make(map[string]*hostedSession),
+		owners:         newOwnerStore(),
+	}, nil
+}
+
+// SetOwnerFile persists the owners of the job sessions and of their
+// jobs in the given file so that they are kept when the server is
+// restarted. The owners are read from the file if it exists. It must be
+// called before serving requests.
+func (s *SessionServer) SetOwnerFile(path string) error {
+	owners, err := loadOwnerStore(path)
+	if err != nil {
+		return err
+	}
+	s.owners = owners
+	return nil
+}
+
+// sessionOwners returns the owners of the job sessions.
+func (s *SessionServer) sessionOwners() *ownerStore {
+	return s.owners.scope("session/")
+}
+
+// jobOwners returns the owners of the jobs of a job session.
+func (s *SessionServer) jobOwners(name string) *ownerStore {
+	return s.owners.scope("job/" + name + "/")
+}
+
+// SetPolicy replaces the default OwnerPolicy of the job sessions. It
+// must be called before serving requests. Listing, creating, and opening
+// job sessions is allowed for all users, destroying a job session
+// requires the ActionDestroy permission.
+func (s *SessionServer) SetPolicy(policy Policy) {
+	s.policy = policy
+}
+
+// Handler returns the handler serving the SessionManager API and the
+// JobTracker API of the job sessions.
+func (s *SessionServer) Handler() http.Handler {
+	return s.HandlerFromMuxWithBaseURL(chi.NewRouter(), "")
+}
+
+// HandlerFromMuxWithBaseURL registers the SessionManager API and the
+// JobTracker API of the job sessions at the router (which might use
+// authentication middlewares) below the base URL.
+func (s *SessionServer) HandlerFromMuxWithBaseURL(r chi.Router, baseURL string) http.Handler {
+	r.HandleFunc(baseURL+"/sessions/{name}/*", s.serveSession)
+	return gensessionserver.HandlerFromMuxWithBaseURL(s, r, baseURL)
+}
+
+// ListBackends returns the names of the backends.
+func (s *SessionServer) ListBackends(w http.ResponseWriter, r *http.Request) {
+	backends := make([]string, 0, len(s.backends))
+	for name := range s.backends {
+		backends = append(backends, name)
+	}
+	sort.Strings(backends)
+	out, err := json.Marshal(backends)
+	if err != nil {
+		log.Printf("failed marshalling body for listbackends response: %v\n", err)
+		http.Error(w, "internal error", http.StatusBadRequest)
+		return
+	}
+	success(w, out)
+}
+
+// ListSessions returns the job sessions of all backends.
+func (s *SessionServer) ListSessions(w http.ResponseWriter, r *http.Request) {
+	sessions := make([]gensessionserver.Session, 0)
+	for backend, sm := range s.backends {
+		names, err := sm.GetJobSessionNames()
+		if err != nil {
+			log.Printf("failed listing job sessions of backend %s: %v\n", backend, err)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+		for _, name := range names {
+			sessions = append(sessions, gensessionserver.Session{
+				Name:    name,
+				Backend: backend,
+			})
+		}
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].Name < sessions[j].Name
+	})
+	out, err := json.Marshal(sessions)
+	if err != nil {
+		log.Printf("failed marshalling body for listsessions response: %v\n", err)
+		http.Error(w, "internal error", http.StatusBadRequest)
+		return
+	}
+	success(w, out)
+}
+
+// CreateSession creates a job session at the requested backend.
+func (s *SessionServer) CreateSession(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		log.Printf("failed reading body from createsession request: %v\n", err)
+		http.Error(w, "can't read body", http.StatusBadRequest)
+		return
+	}
+	var input gensessionserver.CreateSessionInput
+	if err := json.Unmarshal(body, &input); err != nil {
+		http.Error(w, "can't unmarshal body", http.StatusBadRequest)
+		return
+	}
+	if input.Name == "" {
+		http.Error(w, "job session name is required", http.StatusBadRequest)
+		return
+	}
+	backend := s.defaultBackend
+	if input.Backend != nil && *input.Backend != "" {
+		backend = *input.Backend
+	}
+	sm, exists := s.backends[backend]
+	if !exists {
+		http.Error(w, fmt.Sprintf("unknown backend %s", backend), http.StatusBadRequest)
+		return
+	}
+	contact := ""
+	if input.Contact != nil {
+		contact = *input.Contact
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	if _, _, err := s.findBackend(input.Name); err == nil {
+		http.Error(w, "job session exists already", http.StatusConflict)
+		return
+	}
+	js, err := sm.CreateJobSession(input.Name, contact)
+	if err != nil {
+		log.Printf("failed creating job session %s: %v\n", input.Name, err)
+		http.Error(w, fmt.Sprintf("failed creating job session: %v", err), http.StatusInternalServerError)
+		return
+	}
+	// forget the owners of a job session with the same name which was
+	// destroyed without the server
+	s.sessionOwners().remove(input.Name)
+	s.jobOwners(input.Name).clear()
+	if principal, ok := Principal(r.Context()); ok && principal != "" {
+		s.sessionOwners().set(principal, input.Name)
+	}
+	s.host(input.Name, backend, js)
+	s.respondSession(w, input.Name, backend)
+}
+
+// OpenSession opens an existing job session.
+func (s *SessionServer) OpenSession(w http.ResponseWriter, r *http.Request, name string) {
+	session, err := s.open(name)
+	if err != nil {
+		sessionError(w, err)
+		return
+	}
+	s.respondSession(w, name, session.backend)
+}
+
+// DestroySession closes and destroys a job session. Only the user who
+// created the job session and admins are allowed to destroy it.
+func (s *SessionServer) DestroySession(w http.ResponseWriter, r *http.Request, name string) {
+	s.Lock()
+	defer s.Unlock()
+	_, sm, err := s.findBackend(name)
+	if err != nil {
+		sessionError(w, err)
+		return
+	}
+	principal, _ := Principal(r.Context())
+	owner, _ := s.sessionOwners().get(name)
+	if !s.policy.Allowed(principal, owner, ActionDestroy) {
+		http.Error(w, fmt.Sprintf("user %q is not allowed to destroy job session %s",
+			principal, name), http.StatusForbidden)
+		return
+	}
+	if session, open := s.sessions[name]; open {
+		session.js.Close()
+		delete(s.sessions, name)
+	}
+	if err := sm.DestroyJobSession(name); err != nil {
+		log.Printf("failed destroying job session %s: %v\n", name, err)
+		http.Error(w, fmt.Sprintf("failed destroying job session: %v", err), http.StatusInternalServerError)
+		return
+	}
+	s.sessionOwners().remove(name)
+	s.jobOwners(name).clear()
+	w.WriteHeader(http.StatusOK)
+}
+
+// Close closes all open job sessions.
+func (s *SessionServer) Close() error {
+	s.Lock()
+	defer s.Unlock()
+	var lastErr error
+	for name, session := range s.sessions {
+		if err := session.js.Close(); err != nil {
+			lastErr = err
+		}
+		delete(s.sessions, name)
+	}
+	return lastErr
+}
+
+// serveSession serves the JobTracker API of a job session. The job
+// session is opened when it is not open yet.
+func (s *SessionServer) serveSession(w http.ResponseWriter, r *http.Request) {
+	session, err := s.open(chi.URLParam(r, "name"))
+	if err != nil {
+		sessionError(w, err)
+		return
+	}
+	// the JobTracker API routes the remaining path on its own
+	sessionRequest := r.Clone(context.WithValue(r.Context(), chi.RouteCtxKey, nil))
+	sessionRequest.URL.Path = "/" + chi.URLParam(r, "*")
+	sessionRequest.URL.RawPath = ""
+	session.handler.ServeHTTP(w, sessionRequest)
+}
+
+// open returns the open job session or opens it at its backend.
+func (s *SessionServer) open(name string) (*hostedSession, error) {
+	s.Lock()
+	defer s.Unlock()
+	if session, open := s.sessions[name]; open {
+		return session, nil
+	}
+	backend, sm, err := s.findBackend(name)
+	if err != nil {
+		return nil, err
+	}
+	js, err := sm.OpenJobSession(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed opening job session: %v", err)
+	}
+	return s.host(name, backend, js), nil
+}
+
+// host serves the job session by the JobTracker API. Must be called
+// while holding the lock.
+func (s *SessionServer) host(name, backend string, js drmaa2interface.JobSession) *hostedSession {
+	session := &hostedSession{
+		backend: backend,
+		js:      js.(*drmaa2os.JobSession),
+	}
+	impl, _ := NewJobTrackerImpl(session.js.JobTracker())
+	impl.SetPolicy(s.policy)
+	impl.owners = s.jobOwners(name)
+	session.handler = genserver.Handler(impl)
+	s.sessions[name] = session
+	return session
+}
+
+// findBackend returns the backend which has a job session with the
+// given name. Must be called while holding the lock.
+func (s *SessionServer) findBackend(name string) (string, *drmaa2os.SessionManager, error) {
+	if session, open := s.sessions[name]; open {
+		return session.backend, s.backends[session.backend], nil
+	}
+	for backend, sm := range s.backends {
+		names, err := sm.GetJobSessionNames()
+		if err != nil {
+			return "", nil, err
+		}
+		for _, n := range names {
+			if n == name {
+				return backend, sm, nil
+			}
+		}
+	}
+	return "", nil, errSessionNotFound
+}
+
+func (s *SessionServer) respondSession(w http.ResponseWriter, name, backend string) {
+	out, err := json.Marshal(gensessionserver.Session{Name: name, Backend: backend})
+	if err != nil {
+		log.Printf("failed marshalling body for session response: %v\n", err)
+		http.Error(w, "internal error", http.StatusBadRequest)
+		return
+	}
+	success(w, out)
+}
+
+func sessionError(w http.ResponseWriter, err error) {
+	if errors.Is(err, errSessionNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	log.Printf("failed accessing job session: %v\n", err)
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
@@ -0,0 +1,195 @@
+package server_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+
+	"github.com/dgruber/drmaa2os"
+	. "github.com/dgruber/drmaa2os/pkg/jobtracker/remote/server"
+	_ "github.com/dgruber/drmaa2os/pkg/jobtracker/simpletracker"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SessionServer", func() {
+
+	var (
+		backends      map[string]*drmaa2os.SessionManager
+		sessionServer *SessionServer
+		testServer    *httptest.Server
+	)
+
+	request := func(method, path, body string) (int, string) {
+		req, err := http.NewRequest(method, testServer.URL+path, strings.NewReader(body))
+		Expect(err).To(BeNil())
+		resp, err := http.DefaultClient.Do(req)
+		Expect(err).To(BeNil())
+		defer resp.Body.Close()
+		out, err := io.ReadAll(resp.Body)
+		Expect(err).To(BeNil())
+		return resp.StatusCode, string(out)
+	}
+
+	BeforeEach(func() {
+		dir := GinkgoT().TempDir()
+		backends = make(map[string]*drmaa2os.SessionManager)
+		for _, name := range []string{"process", "other"} {
+			sm, err := drmaa2os.NewDefaultSessionManager(filepath.Join(dir, name+".db"))
+			Expect(err).To(BeNil())
+			backends[name] = sm
+		}
+		var err error
+		sessionServer, err = NewSessionServer(backends, "process")
+		Expect(err).To(BeNil())
+		testServer = httptest.NewServer(sessionServer.Handler())
+	})
+
+	AfterEach(func() {
+		testServer.Close()
+		sessionServer.Close()
+	})
+
+	It("should require a valid default backend", func() {
+		_, err := NewSessionServer(nil, "")
+		Expect(err).NotTo(BeNil())
+		sm, err := drmaa2os.NewDefaultSessionManager(filepath.Join(GinkgoT().TempDir(), "sm.db"))
+		Expect(err).To(BeNil())
+		_, err = NewSessionServer(map[string]*drmaa2os.SessionManager{"process": sm}, "docker")
+		Expect(err).NotTo(BeNil())
+		_, err = NewSessionServer(map[string]*drmaa2os.SessionManager{"process": sm}, "")
+		Expect(err).To(BeNil())
+	})
+
+	It("should list the backends", func() {
+		code, body := request(http.MethodGet, "/backends", "")
+		Expect(code).To(Equal(http.StatusOK))
+		Expect(body).To(MatchJSON(`["other","process"]`))
+	})
+
+	It("should create, list, open, and destroy job sessions", func() {
+		code, body := request(http.MethodPost, "/sessions", `{"name":"team1"}`)
+		Expect(code).To(Equal(http.StatusOK))
+		Expect(body).To(MatchJSON(`{"name":"team1","backend":"process"}`))
+
+		code, _ = request(http.MethodPost, "/sessions", `{"name":"team2","backend":"other"}`)
+		Expect(code).To(Equal(http.StatusOK))
+
+		code, body = request(http.MethodGet, "/sessions", "")
+		Expect(code).To(Equal(http.StatusOK))
+		Expect(body).To(MatchJSON(`[{"name":"team1","backend":"process"},{"name":"team2","backend":"other"}]`))
+
+		code, body = request(http.MethodGet, "/sessions/team2", "")
+		Expect(code).To(Equal(http.StatusOK))
+		Expect(body).To(MatchJSON(`{"name":"team2","backend":"other"}`))
+
+		code, _ = request(http.MethodDelete, "/sessions/team2", "")
+		Expect(code).To(Equal(http.StatusOK))
+		code, _ = request(http.MethodGet, "/sessions/team2", "")
+		Expect(code).To(Equal(http.StatusNotFound))
+		code, _ = request(http.MethodDelete, "/sessions/team2", "")
+		Expect(code).To(Equal(http.StatusNotFound))
+	})
+
+	It("should reject invalid job sessions", func() {
+		code, _ := request(http.MethodPost, "/sessions", `{"name":"team1"}`)
+		Expect(code).To(Equal(http.StatusOK))
+		code, _ = request(http.MethodPost, "/sessions", `{"name":"team1","backend":"other"}`)
+		Expect(code).To(Equal(http.StatusConflict))
+		code, _ = request(http.MethodPost, "/sessions", `{"name":"team3","backend":"unknown"}`)
+		Expect(code).To(Equal(http.StatusBadRequest))
+		code, _ = request(http.MethodPost, "/sessions", `{"backend":"other"}`)
+		Expect(code).To(Equal(http.StatusBadRequest))
+	})
+
+	It("should route the JobTracker API by job session", func() {
+		for _, name := range []string{"team1", "team2"} {
+			code, _ := request(http.MethodPost, "/sessions", `{"name":"`+name+`"}`)
+			Expect(code).To(Equal(http.StatusOK))
+		}
+		code, body := request(http.MethodPost, "/sessions/team1/addjob",
+			`{"remoteCommand":"/bin/sleep","args":["0"]}`)
+		Expect(code).To(Equal(http.StatusOK))
+		var output struct {
+			JobID string
+			Error string
+		}
+		Expect(json.Unmarshal([]byte(body), &output)).To(Succeed())
+		Expect(output.Error).To(Equal(""))
+
+		code, body = request(http.MethodGet, "/sessions/team1/listjobs", "")
+		Expect(code).To(Equal(http.StatusOK))
+		Expect(body).To(MatchJSON(`["` + output.JobID + `"]`))
+
+		code, body = request(http.MethodGet, "/sessions/team2/listjobs", "")
+		Expect(code).To(Equal(http.StatusOK))
+		Expect(body).To(MatchJSON(`[]`))
+
+		code, _ = request(http.MethodGet, "/sessions/unknown/listjobs", "")
+		Expect(code).To(Equal(http.StatusNotFound))
+	})
+
+	It("should keep the owners of job sessions and jobs when the server is restarted", func() {
+		ownerFile := filepath.Join(GinkgoT().TempDir(), "owners.json")
+		tokens := map[string]string{"token1": "user1", "token2": "user2"}
+		serve := func() {
+			testServer.Close()
+			sessionServer.Close()
+			var err error
+			sessionServer, err = NewSessionServer(backends, "process")
+			Expect(err).To(BeNil())
+			Expect(sessionServer.SetOwnerFile(ownerFile)).To(Succeed())
+			testServer = httptest.NewServer(BearerTokenAuth(tokens)(sessionServer.Handler()))
+		}
+		requestAs := func(token, method, path, body string) (int, string) {
+			req, err := http.NewRequest(method, testServer.URL+path, strings.NewReader(body))
+			Expect(err).To(BeNil())
+			req.Header.Set("Authorization", "Bearer "+token)
+			resp, err := http.DefaultClient.Do(req)
+			Expect(err).To(BeNil())
+			defer resp.Body.Close()
+			out, err := io.ReadAll(resp.Body)
+			Expect(err).To(BeNil())
+			return resp.StatusCode, string(out)
+		}
+
+		serve()
+		code, _ := requestAs("token1", http.MethodPost, "/sessions", `{"name":"team1"}`)
+		Expect(code).To(Equal(http.StatusOK))
+		code, body := requestAs("token1", http.MethodPost, "/sessions/team1/addjob",
+			`{"remoteCommand":"/bin/sleep","args":["0"]}`)
+		Expect(code).To(Equal(http.StatusOK))
+		var output struct {
+			JobID string
+			Error string
+		}
+		Expect(json.Unmarshal([]byte(body), &output)).To(Succeed())
+		Expect(output.Error).To(Equal(""))
+
+		serve()
+		code, body = requestAs("token2", http.MethodGet,
+			"/sessions/team1/jobinfo?jobID="+output.JobID, "")
+		Expect(code).To(Equal(http.StatusOK))
+		Expect(body).To(ContainSubstring(`"jobOwner":"user1"`))
+		code, _ = requestAs("token2", http.MethodGet,
+			"/sessions/team1/jobtemplate?jobID="+output.JobID, "")
+		Expect(code).To(Equal(http.StatusForbidden))
+		code, _ = requestAs("token2", http.MethodDelete, "/sessions/team1", "")
+		Expect(code).To(Equal(http.StatusForbidden))
+		code, _ = requestAs("token1", http.MethodDelete, "/sessions/team1", "")
+		Expect(code).To(Equal(http.StatusOK))
+
+		// a new job session with the same name has no owners of the
+		// destroyed job session
+		code, _ = requestAs("token2", http.MethodPost, "/sessions", `{"name":"team1"}`)
+		Expect(code).To(Equal(http.StatusOK))
+		code, body = requestAs("token1", http.MethodGet,
+			"/sessions/team1/jobinfo?jobID="+output.JobID, "")
+		Expect(code).To(Equal(http.StatusOK))
+		Expect(body).NotTo(ContainSubstring(`"jobOwner":"user1"`))
+	})
+
+})
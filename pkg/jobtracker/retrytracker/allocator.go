@@ -0,0 +1,64 @@
+package retrytracker
+
+import (
+	"github.com/dgruber/drmaa2interface"
+	"github.com/dgruber/drmaa2os/pkg/jobtracker"
+)
+
+// NewAllocator wraps the Allocator of a JobTracker so that the
+// JobTrackers it creates resubmit failed ReRunnable jobs according
+// to the policy. It can be registered at the SessionManager like:
+//
+// drmaa2os.RegisterJobTracker(drmaa2os.ExternalSession,
+//
+//	retrytracker.NewAllocator(simpletracker.NewAllocator(), policy))
+//
+// When the wrapped Allocator declares its Capabilities the returned
+// Allocator declares them as well.
+func NewAllocator(backend jobtracker.Allocator, policy Policy) jobtracker.Allocator {
+	a := &allocator{backend: backend, policy: policy}
+	if capabilities, ok := backend.(jobtracker.Capabilities); ok {
+		return &capableAllocator{allocator: a, capabilities: capabilities}
+	}
+	return a
+}
+
+type allocator struct {
+	backend jobtracker.Allocator
+	policy  Policy
+}
+
+// New creates the JobTracker of the wrapped Allocator and wraps it.
+func (a *allocator) New(jobSessionName string, jobTrackerInitParams interface{}) (jobtracker.JobTracker, error) {
+	backend, err := a.backend.New(jobSessionName, jobTrackerInitParams)
+	if err != nil {
+		return nil, err
+	}
+	return New(backend, a.policy), nil
+}
+
+type capableAllocator struct {
+	*allocator
+	capabilities jobtracker.Capabilities
+}
+
+// DrmsName returns the name of the wrapped backend.
+func (a *capableAllocator) DrmsName() string {
+	return a.capabilities.DrmsName()
+}
+
+// DrmsVersion returns the version of the wrapped backend.
+func (a *capableAllocator) DrmsVersion() (drmaa2interface.Version, error) {
+	return a.capabilities.DrmsVersion()
+}
+
+// Supports returns the capabilities of the wrapped backend.
+func (a *capableAllocator) Supports(capability drmaa2interface.Capability) bool {
+	return a.capabilities.Supports(capability)
+}
+
+// SupportsJobControl returns the job control actions of the wrapped
+// backend.
+func (a *capableAllocator) SupportsJobControl(action string) bool {
+	return a.capabilities.SupportsJobControl(action)
+}
@@ -0,0 +1,68 @@
+package retrytracker
+
+import (
+	"strings"
+	"time"
+
+	"github.com/dgruber/drmaa2interface"
+)
+
+// Policy defines when and how often failed ReRunnable jobs are
+// resubmitted.
+type Policy struct {
+	// MaxRetries is the maximum number of resubmissions of a job.
+	// When it is 0 jobs are not resubmitted.
+	MaxRetries int
+	// Backoff is the time to wait before the first resubmission.
+	Backoff time.Duration
+	// BackoffFactor multiplies the waiting time after each attempt.
+	// Values lower than 1 keep the waiting time constant.
+	BackoffFactor float64
+	// MaxBackoff limits the waiting time when set.
+	MaxBackoff time.Duration
+	// ExitCodes selects the exit codes for which a job is resubmitted.
+	ExitCodes []int
+	// Signals selects the terminating signals (as reported in the
+	// TerminatingSignal field of the JobInfo of the backend, like
+	// "killed" for the process backend) for which a job is resubmitted.
+	// When ExitCodes and Signals are empty all failed jobs are
+	// resubmitted.
+	Signals []string
+}
+
+// matches returns true if the failed job is selected for a
+// resubmission by its exit code or its terminating signal.
+func (p Policy) matches(ji drmaa2interface.JobInfo) bool {
+	if len(p.ExitCodes) == 0 && len(p.Signals) == 0 {
+		return true
+	}
+	for _, code := range p.ExitCodes {
+		if ji.ExitStatus == code {
+			return true
+		}
+	}
+	for _, signal := range p.Signals {
+		if strings.EqualFold(ji.TerminatingSignal, signal) {
+			return true
+		}
+	}
+	return false
+}
+
+// backoff returns the time to wait before the given resubmission
+// (starting with 1).
+func (p Policy) backoff(retry int) time.Duration {
+	backoff := float64(p.Backoff)
+	if p.BackoffFactor > 1 {
+		for i := 1; i < retry; i++ {
+			backoff *= p.BackoffFactor
+			if p.MaxBackoff > 0 && backoff >= float64(p.MaxBackoff) {
+				break
+			}
+		}
+	}
+	if p.MaxBackoff > 0 && backoff > float64(p.MaxBackoff) {
+		return p.MaxBackoff
+	}
+	return time.Duration(backoff)
+}
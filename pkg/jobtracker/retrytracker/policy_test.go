@@ -0,0 +1,45 @@
+package retrytracker
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/dgruber/drmaa2interface"
+)
+
+var _ = Describe("Policy", func() {
+
+	It("should increase the backoff up to the limit", func() {
+		p := Policy{
+			Backoff:       time.Second,
+			BackoffFactor: 2,
+			MaxBackoff:    5 * time.Second,
+		}
+		Expect(p.backoff(1)).To(Equal(time.Second))
+		Expect(p.backoff(2)).To(Equal(2 * time.Second))
+		Expect(p.backoff(3)).To(Equal(4 * time.Second))
+		Expect(p.backoff(4)).To(Equal(5 * time.Second))
+		Expect(p.backoff(100)).To(Equal(5 * time.Second))
+	})
+
+	It("should keep the backoff constant without a factor", func() {
+		p := Policy{Backoff: time.Second}
+		Expect(p.backoff(1)).To(Equal(time.Second))
+		Expect(p.backoff(3)).To(Equal(time.Second))
+	})
+
+	It("should select failed jobs by exit code and signal", func() {
+		Expect(Policy{}.matches(drmaa2interface.JobInfo{ExitStatus: 1})).To(BeTrue())
+
+		p := Policy{ExitCodes: []int{2, 3}, Signals: []string{"killed"}}
+		Expect(p.matches(drmaa2interface.JobInfo{ExitStatus: 3})).To(BeTrue())
+		Expect(p.matches(drmaa2interface.JobInfo{ExitStatus: 1})).To(BeFalse())
+		Expect(p.matches(drmaa2interface.JobInfo{ExitStatus: -1,
+			TerminatingSignal: "Killed"})).To(BeTrue())
+		Expect(p.matches(drmaa2interface.JobInfo{ExitStatus: -1,
+			TerminatingSignal: "terminated"})).To(BeFalse())
+	})
+
+})
@@ -0,0 +1,412 @@
+package retrytracker
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/dgruber/drmaa2interface"
+	"github.com/dgruber/drmaa2os/pkg/extension"
+	"github.com/dgruber/drmaa2os/pkg/helper"
+	"github.com/dgruber/drmaa2os/pkg/jobtracker"
+)
+
+// Tracker wraps a JobTracker and resubmits failed jobs which are
+// ReRunnable according to a Policy. The job ID of the first attempt
+// is the job ID of all attempts. While the job waits for its
+// resubmission it is in Requeued state.
+type Tracker struct {
+	sync.Mutex
+	backend jobtracker.JobTracker
+	policy  Policy
+	// jobs contains the ReRunnable jobs by their job ID
+	jobs map[string]*retryJob
+	// attempts maps the backend job IDs of resubmissions to the job ID
+	attempts map[string]string
+	done     chan struct{}
+	closed   bool
+}
+
+// retryJob is a ReRunnable job.
+type retryJob struct {
+	template drmaa2interface.JobTemplate
+	// current is the backend job ID of the current attempt
+	current string
+	// finished contains the failed attempts which were resubmitted
+	finished []attempt
+	// requeued is true while the job waits for its resubmission
+	requeued bool
+	// terminated is true when the job was terminated by the user
+	terminated bool
+	// resubmitErr is set when the resubmission failed
+	resubmitErr error
+	cancel      chan struct{}
+}
+
+// attempt is a failed attempt of a job.
+type attempt struct {
+	jobID      string
+	exitStatus int
+	signal     string
+}
+
+// New wraps the given JobTracker so that failed ReRunnable jobs are
+// resubmitted according to the policy.
+func New(backend jobtracker.JobTracker, policy Policy) *Tracker {
+	return &Tracker{
+		backend:  backend,
+		policy:   policy,
+		jobs:     make(map[string]*retryJob),
+		attempts: make(map[string]string),
+		done:     make(chan struct{}),
+	}
+}
+
+// ListJobs returns the jobs of the backend without the resubmissions.
+func (t *Tracker) ListJobs() ([]string, error) {
+	jobs, err := t.backend.ListJobs()
+	if err != nil {
+		return nil, err
+	}
+	t.Lock()
+	defer t.Unlock()
+	ids := make([]string, 0, len(jobs))
+	for _, id := range jobs {
+		if _, isAttempt := t.attempts[id]; !isAttempt {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// ListArrayJobs returns the job IDs of the tasks of an array job.
+func (t *Tracker) ListArrayJobs(arrayjobID string) ([]string, error) {
+	return t.backend.ListArrayJobs(arrayjobID)
+}
+
+// AddJob submits a job to the backend. When the job is ReRunnable
+// it is resubmitted after a failure according to the policy.
+func (t *Tracker) AddJob(jt drmaa2interface.JobTemplate) (string, error) {
+	id, err := t.backend.AddJob(jt)
+	if err != nil || !jt.ReRunnable || t.policy.MaxRetries <= 0 {
+		return id, err
+	}
+	job := &retryJob{
+		template: jt,
+		current:  id,
+		cancel:   make(chan struct{}),
+	}
+	t.Lock()
+	t.jobs[id] = job
+	t.Unlock()
+	go t.supervise(id, job)
+	return id, nil
+}
+
+// AddArrayJob submits an array job to the backend. Tasks of array
+// jobs are not resubmitted.
+func (t *Tracker) AddArrayJob(jt drmaa2interface.JobTemplate, begin, end, step, maxParallel int) (string, error) {
+	return t.backend.AddArrayJob(jt, begin, end, step, maxParallel)
+}
+
+// JobState returns the state of the current attempt of the job. A
+// failed attempt which is resubmitted is reported as Requeued.
+func (t *Tracker) JobState(jobID string) (drmaa2interface.JobState, string, error) {
+	t.Lock()
+	job, exists := t.jobs[jobID]
+	if !exists {
+		t.Unlock()
+		return t.backend.JobState(jobID)
+	}
+	if job.requeued {
+		t.Unlock()
+		return drmaa2interface.Requeued, "", nil
+	}
+	current := job.current
+	t.Unlock()
+
+	state, subState, err := t.backend.JobState(current)
+	if err != nil || state != drmaa2interface.Failed {
+		return state, subState, err
+	}
+	ji, err := t.backend.JobInfo(current)
+	if err != nil {
+		return state, subState, nil
+	}
+	if t.isRequeued(job, current, ji) {
+		return drmaa2interface.Requeued, "", nil
+	}
+	return state, t.subState(job, subState), nil
+}
+
+// JobInfo returns the JobInfo of the current attempt of the job. The
+// number of attempts and the failed attempts are listed in the
+// ExtensionList.
+func (t *Tracker) JobInfo(jobID string) (drmaa2interface.JobInfo, error) {
+	t.Lock()
+	job, exists := t.jobs[jobID]
+	if !exists {
+		t.Unlock()
+		return t.backend.JobInfo(jobID)
+	}
+	current := job.current
+	t.Unlock()
+
+	ji, err := t.backend.JobInfo(current)
+	if err != nil {
+		return ji, err
+	}
+	ji.ID = jobID
+	if ji.State == drmaa2interface.Failed && t.isRequeued(job, current, ji) {
+		ji.State = drmaa2interface.Requeued
+		ji.SubState = ""
+	} else if ji.State == drmaa2interface.Failed {
+		ji.SubState = t.subState(job, ji.SubState)
+	}
+
+	t.Lock()
+	defer t.Unlock()
+	extensions := make(map[string]string, len(ji.ExtensionList)+len(job.finished)+1)
+	for key, value := range ji.ExtensionList {
+		extensions[key] = value
+	}
+	attempts := len(job.finished)
+	if job.current == current && !job.requeued {
+		// the current attempt is not yet in the history
+		attempts++
+	}
+	extensions[extension.JobInfoRetryAttempts] = strconv.Itoa(attempts)
+	for i, a := range job.finished {
+		extensions[extension.JobInfoRetryAttemptPrefix+strconv.Itoa(i+1)] =
+			fmt.Sprintf("job_id=%s exit_status=%d signal=%s",
+				a.jobID, a.exitStatus, a.signal)
+	}
+	ji.ExtensionList = extensions
+	return ji, nil
+}
+
+// JobControl sends the action to the current attempt of the job.
+// Terminating a job stops further resubmissions.
+func (t *Tracker) JobControl(jobID, action string) error {
+	t.Lock()
+	job, exists := t.jobs[jobID]
+	if !exists {
+		t.Unlock()
+		return t.backend.JobControl(jobID, action)
+	}
+	if action == jobtracker.JobControlTerminate {
+		job.terminated = true
+		if job.requeued {
+			// the last attempt failed already
+			job.unqueue()
+			close(job.cancel)
+			t.Unlock()
+			return nil
+		}
+	} else if job.requeued {
+		t.Unlock()
+		return fmt.Errorf("job %s is requeued", jobID)
+	}
+	current := job.current
+	t.Unlock()
+	return t.backend.JobControl(current, action)
+}
+
+// Wait blocks until the job is in one of the given states or the
+// timeout is reached.
+func (t *Tracker) Wait(jobID string, timeout time.Duration, states ...drmaa2interface.JobState) error {
+	t.Lock()
+	_, exists := t.jobs[jobID]
+	t.Unlock()
+	if !exists {
+		return t.backend.Wait(jobID, timeout, states...)
+	}
+	return helper.WaitForState(t, jobID, timeout, states...)
+}
+
+// DeleteJob removes all attempts of a finished job from the backend.
+func (t *Tracker) DeleteJob(jobID string) error {
+	t.Lock()
+	job, exists := t.jobs[jobID]
+	t.Unlock()
+	if !exists {
+		return t.backend.DeleteJob(jobID)
+	}
+	state, _, err := t.JobState(jobID)
+	if err != nil {
+		return err
+	}
+	if state != drmaa2interface.Done && state != drmaa2interface.Failed {
+		return errors.New("job is not in an end state")
+	}
+	t.Lock()
+	defer t.Unlock()
+	if err := t.backend.DeleteJob(job.current); err != nil {
+		return err
+	}
+	for _, a := range job.finished {
+		t.backend.DeleteJob(a.jobID)
+		delete(t.attempts, a.jobID)
+	}
+	delete(t.attempts, job.current)
+	delete(t.jobs, jobID)
+	return nil
+}
+
+// ListJobCategories returns the job categories of the backend.
+func (t *Tracker) ListJobCategories() ([]string, error) {
+	return t.backend.ListJobCategories()
+}
+
+// JobTemplate returns the job template of a job when the backend
+// supports it.
+func (t *Tracker) JobTemplate(jobID string) (drmaa2interface.JobTemplate, error) {
+	t.Lock()
+	job, exists := t.jobs[jobID]
+	t.Unlock()
+	if exists {
+		return job.template, nil
+	}
+	templater, ok := t.backend.(jobtracker.JobTemplater)
+	if !ok {
+		return drmaa2interface.JobTemplate{}, errors.New("job template is not available")
+	}
+	return templater.JobTemplate(jobID)
+}
+
+// Close stops the resubmissions and closes the backend.
+func (t *Tracker) Close() error {
+	t.Lock()
+	if !t.closed {
+		t.closed = true
+		close(t.done)
+	}
+	t.Unlock()
+	if closer, ok := t.backend.(jobtracker.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// isRequeued returns true if the failed attempt will be resubmitted
+// or was resubmitted already.
+func (t *Tracker) isRequeued(job *retryJob, current string, ji drmaa2interface.JobInfo) bool {
+	t.Lock()
+	defer t.Unlock()
+	if job.current != current || job.requeued {
+		return true
+	}
+	return t.retries(job, ji)
+}
+
+// retries returns true if the failed attempt of the job needs to be
+// resubmitted. Must be called while holding the lock.
+func (t *Tracker) retries(job *retryJob, ji drmaa2interface.JobInfo) bool {
+	return !job.terminated && !t.closed && job.resubmitErr == nil &&
+		len(job.finished) < t.policy.MaxRetries && t.policy.matches(ji)
+}
+
+// subState adds the resubmission error to the sub-state of a failed job.
+func (t *Tracker) subState(job *retryJob, subState string) string {
+	t.Lock()
+	defer t.Unlock()
+	if job.resubmitErr != nil {
+		return fmt.Sprintf("failed to resubmit job: %v", job.resubmitErr)
+	}
+	return subState
+}
+
+// unqueue stops the resubmission of a requeued job. The failed attempt
+// remains the current attempt. Must be called while holding the lock.
+func (job *retryJob) unqueue() {
+	if !job.requeued {
+		return
+	}
+	job.requeued = false
+	job.finished = job.finished[:len(job.finished)-1]
+}
+
+// supervise waits until the current attempt of the job is finished
+// and resubmits the job after the backoff time when it failed.
+func (t *Tracker) supervise(id string, job *retryJob) {
+	for {
+		t.Lock()
+		current := job.current
+		t.Unlock()
+		if !t.waitFinished(current) {
+			return
+		}
+		ji, err := t.backend.JobInfo(current)
+		if err != nil || ji.State != drmaa2interface.Failed {
+			return
+		}
+
+		t.Lock()
+		if !t.retries(job, ji) {
+			t.Unlock()
+			return
+		}
+		job.finished = append(job.finished, attempt{
+			jobID:      current,
+			exitStatus: ji.ExitStatus,
+			signal:     ji.TerminatingSignal,
+		})
+		job.requeued = true
+		backoff := t.policy.backoff(len(job.finished))
+		t.Unlock()
+
+		select {
+		case <-time.After(backoff):
+		case <-job.cancel:
+			return
+		case <-t.done:
+			t.Lock()
+			job.unqueue()
+			t.Unlock()
+			return
+		}
+
+		t.Lock()
+		if job.terminated || t.closed {
+			job.unqueue()
+			t.Unlock()
+			return
+		}
+		next, err := t.backend.AddJob(job.template)
+		if err != nil {
+			job.resubmitErr = err
+			job.unqueue()
+			t.Unlock()
+			return
+		}
+		job.current = next
+		job.requeued = false
+		t.attempts[next] = id
+		t.Unlock()
+	}
+}
+
+// waitFinished blocks until the attempt is finished. It returns false
+// when the tracker is closed or the attempt can't be found. Backends
+// which don't return an error for unknown jobs report them as
+// Undetermined.
+func (t *Tracker) waitFinished(jobID string) bool {
+	for {
+		select {
+		case <-t.done:
+			return false
+		default:
+		}
+		err := t.backend.Wait(jobID, time.Second,
+			drmaa2interface.Done, drmaa2interface.Failed)
+		if err == nil {
+			return true
+		}
+		state, _, err := t.backend.JobState(jobID)
+		if err != nil || state == drmaa2interface.Undetermined {
+			return false
+		}
+	}
+}
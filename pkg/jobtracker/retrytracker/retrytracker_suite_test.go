@@ -0,0 +1,13 @@
+package retrytracker_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestRetrytracker(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Retrytracker Suite")
+}
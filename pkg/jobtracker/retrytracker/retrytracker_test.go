@@ -0,0 +1,241 @@
+package retrytracker_test
+
+import (
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	. "github.com/dgruber/drmaa2os/pkg/jobtracker/retrytracker"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/dgruber/drmaa2interface"
+	"github.com/dgruber/drmaa2os/pkg/extension"
+	"github.com/dgruber/drmaa2os/pkg/jobtracker"
+	"github.com/dgruber/drmaa2os/pkg/jobtracker/simpletracker"
+)
+
+// lostJobTracker returns job IDs which are unknown to the backend.
+type lostJobTracker struct {
+	jobtracker.JobTracker
+	calls int64
+}
+
+func (l *lostJobTracker) AddJob(jt drmaa2interface.JobTemplate) (string, error) {
+	return "lost", nil
+}
+
+func (l *lostJobTracker) JobState(jobID string) (drmaa2interface.JobState, string, error) {
+	atomic.AddInt64(&l.calls, 1)
+	return l.JobTracker.JobState(jobID)
+}
+
+func (l *lostJobTracker) jobStateCalls() int64 {
+	return atomic.LoadInt64(&l.calls)
+}
+
+var _ = Describe("Retrytracker", func() {
+
+	var tracker *Tracker
+	var policy Policy
+
+	shell := func(script string) drmaa2interface.JobTemplate {
+		return drmaa2interface.JobTemplate{
+			RemoteCommand: "/bin/sh",
+			Args:          []string{"-c", script},
+			ReRunnable:    true,
+		}
+	}
+
+	BeforeEach(func() {
+		policy = Policy{
+			MaxRetries: 2,
+			Backoff:    10 * time.Millisecond,
+		}
+	})
+
+	JustBeforeEach(func() {
+		tracker = New(simpletracker.New("retrytest"), policy)
+	})
+
+	AfterEach(func() {
+		tracker.Close()
+	})
+
+	It("should resubmit a failed job until the retries are exhausted", func() {
+		jobID, err := tracker.AddJob(shell("exit 3"))
+		Expect(err).To(BeNil())
+		Expect(tracker.Wait(jobID, 10*time.Second,
+			drmaa2interface.Failed)).To(BeNil())
+
+		ji, err := tracker.JobInfo(jobID)
+		Expect(err).To(BeNil())
+		Expect(ji.ID).To(Equal(jobID))
+		Expect(ji.State).To(Equal(drmaa2interface.Failed))
+		Expect(ji.ExitStatus).To(Equal(3))
+		Expect(ji.ExtensionList).To(HaveKeyWithValue(
+			extension.JobInfoRetryAttempts, "3"))
+		Expect(ji.ExtensionList).To(HaveKeyWithValue(
+			extension.JobInfoRetryAttemptPrefix+"1",
+			"job_id="+jobID+" exit_status=3 signal=signal -1"))
+		Expect(ji.ExtensionList).To(HaveKey(extension.JobInfoRetryAttemptPrefix + "2"))
+		Expect(ji.ExtensionList).NotTo(HaveKey(extension.JobInfoRetryAttemptPrefix + "3"))
+
+		// resubmissions are not visible as jobs
+		jobs, err := tracker.ListJobs()
+		Expect(err).To(BeNil())
+		Expect(jobs).To(ConsistOf(jobID))
+
+		Expect(tracker.DeleteJob(jobID)).To(BeNil())
+		jobs, err = tracker.ListJobs()
+		Expect(err).To(BeNil())
+		Expect(jobs).To(BeEmpty())
+	})
+
+	It("should keep the job ID when a resubmission succeeds", func() {
+		tmpDir, err := os.MkdirTemp("", "retrytest")
+		Expect(err).To(BeNil())
+		defer os.RemoveAll(tmpDir)
+		marker := filepath.Join(tmpDir, "failed")
+
+		jobID, err := tracker.AddJob(shell(
+			"if [ -f " + marker + " ]; then exit 0; fi; touch " + marker + "; exit 1"))
+		Expect(err).To(BeNil())
+		Expect(tracker.Wait(jobID, 10*time.Second,
+			drmaa2interface.Done)).To(BeNil())
+
+		ji, err := tracker.JobInfo(jobID)
+		Expect(err).To(BeNil())
+		Expect(ji.ID).To(Equal(jobID))
+		Expect(ji.ExitStatus).To(Equal(0))
+		Expect(ji.ExtensionList).To(HaveKeyWithValue(
+			extension.JobInfoRetryAttempts, "2"))
+	})
+
+	Context("with a long backoff", func() {
+
+		BeforeEach(func() {
+			policy.Backoff = time.Minute
+		})
+
+		It("should report Requeued between the attempts", func() {
+			jobID, err := tracker.AddJob(shell("exit 1"))
+			Expect(err).To(BeNil())
+			Expect(tracker.Wait(jobID, 10*time.Second,
+				drmaa2interface.Requeued)).To(BeNil())
+			ji, err := tracker.JobInfo(jobID)
+			Expect(err).To(BeNil())
+			Expect(ji.State).To(Equal(drmaa2interface.Requeued))
+			Expect(ji.ExtensionList).To(HaveKeyWithValue(
+				extension.JobInfoRetryAttempts, "1"))
+
+			// a requeued job can't be deleted
+			Expect(tracker.DeleteJob(jobID)).NotTo(BeNil())
+			Expect(tracker.JobControl(jobID,
+				jobtracker.JobControlSuspend)).NotTo(BeNil())
+
+			// terminating stops the resubmission
+			Expect(tracker.JobControl(jobID,
+				jobtracker.JobControlTerminate)).To(BeNil())
+			state, _, err := tracker.JobState(jobID)
+			Expect(err).To(BeNil())
+			Expect(state).To(Equal(drmaa2interface.Failed))
+			// the failed attempt is the last attempt
+			ji, err = tracker.JobInfo(jobID)
+			Expect(err).To(BeNil())
+			Expect(ji.ExtensionList).To(HaveKeyWithValue(
+				extension.JobInfoRetryAttempts, "1"))
+			Expect(ji.ExtensionList).NotTo(HaveKey(extension.JobInfoRetryAttemptPrefix + "1"))
+			Expect(tracker.DeleteJob(jobID)).To(BeNil())
+		})
+
+		It("should stop the resubmission when the tracker is closed", func() {
+			jobID, err := tracker.AddJob(shell("exit 1"))
+			Expect(err).To(BeNil())
+			Expect(tracker.Wait(jobID, 10*time.Second,
+				drmaa2interface.Requeued)).To(BeNil())
+
+			Expect(tracker.Close()).To(BeNil())
+			Eventually(func() drmaa2interface.JobState {
+				state, _, _ := tracker.JobState(jobID)
+				return state
+			}).Should(Equal(drmaa2interface.Failed))
+			ji, err := tracker.JobInfo(jobID)
+			Expect(err).To(BeNil())
+			Expect(ji.ExtensionList).To(HaveKeyWithValue(
+				extension.JobInfoRetryAttempts, "1"))
+			Expect(ji.ExtensionList).NotTo(HaveKey(extension.JobInfoRetryAttemptPrefix + "1"))
+		})
+
+	})
+
+	Context("with selected exit codes and signals", func() {
+
+		BeforeEach(func() {
+			policy.ExitCodes = []int{2}
+			policy.Signals = []string{"killed"}
+		})
+
+		It("should not resubmit jobs with other exit codes", func() {
+			jobID, err := tracker.AddJob(shell("exit 1"))
+			Expect(err).To(BeNil())
+			Expect(tracker.Wait(jobID, 10*time.Second,
+				drmaa2interface.Failed)).To(BeNil())
+			ji, err := tracker.JobInfo(jobID)
+			Expect(err).To(BeNil())
+			Expect(ji.ExtensionList).To(HaveKeyWithValue(
+				extension.JobInfoRetryAttempts, "1"))
+		})
+
+		It("should resubmit jobs with the selected exit code or signal", func() {
+			jobID, err := tracker.AddJob(shell("exit 2"))
+			Expect(err).To(BeNil())
+			Expect(tracker.Wait(jobID, 10*time.Second,
+				drmaa2interface.Failed)).To(BeNil())
+			ji, err := tracker.JobInfo(jobID)
+			Expect(err).To(BeNil())
+			Expect(ji.ExtensionList).To(HaveKeyWithValue(
+				extension.JobInfoRetryAttempts, "3"))
+
+			jobID, err = tracker.AddJob(shell("kill -9 $$"))
+			Expect(err).To(BeNil())
+			Expect(tracker.Wait(jobID, 10*time.Second,
+				drmaa2interface.Failed)).To(BeNil())
+			ji, err = tracker.JobInfo(jobID)
+			Expect(err).To(BeNil())
+			Expect(ji.TerminatingSignal).To(Equal("killed"))
+			Expect(ji.ExtensionList).To(HaveKeyWithValue(
+				extension.JobInfoRetryAttempts, "3"))
+		})
+
+	})
+
+	It("should stop supervising jobs which are unknown to the backend", func() {
+		backend := &lostJobTracker{JobTracker: simpletracker.New("retrytest")}
+		lostTracker := New(backend, policy)
+		defer lostTracker.Close()
+		_, err := lostTracker.AddJob(shell("exit 1"))
+		Expect(err).To(BeNil())
+		Eventually(backend.jobStateCalls).Should(BeNumerically("==", 1))
+		Consistently(backend.jobStateCalls, "200ms").Should(BeNumerically("==", 1))
+	})
+
+	It("should not resubmit jobs which are not ReRunnable", func() {
+		jt := shell("exit 1")
+		jt.ReRunnable = false
+		jobID, err := tracker.AddJob(jt)
+		Expect(err).To(BeNil())
+		Expect(tracker.Wait(jobID, 10*time.Second,
+			drmaa2interface.Failed)).To(BeNil())
+		Consistently(func() drmaa2interface.JobState {
+			state, _, _ := tracker.JobState(jobID)
+			return state
+		}, "200ms").Should(Equal(drmaa2interface.Failed))
+		ji, err := tracker.JobInfo(jobID)
+		Expect(err).To(BeNil())
+		Expect(ji.ExtensionList).NotTo(HaveKey(extension.JobInfoRetryAttempts))
+	})
+
+})
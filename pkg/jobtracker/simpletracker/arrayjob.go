@@ -2,6 +2,7 @@ package simpletracker
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/dgruber/drmaa2interface"
 )
@@ -9,75 +10,138 @@ import (
 // arrayJobSubmissionController starts and supervises all jobs of a job array.
 // It takes care that not more jobs than _maxParallel_ jobs are running
 // at the same time. When jobs are finished it starts more jobs and
-// put their state from _queued_ into _running_ state.
+// put their state from _queued_ into _running_ state. Held tasks are
+// skipped and started when they are released. No task is started
+// before the StartTime of the job template.
 func arrayJobSubmissionController(jt *JobTracker, arrayjobid string, t drmaa2interface.JobTemplate,
 	begin, end, step, maxParallel int) chan error {
 	firstJobErrorCh := make(chan error, 1)
+	wakeCh := jt.registerArrayJobController(arrayjobid)
 
 	go func() {
+		defer jt.unregisterArrayJobController(arrayjobid)
+
+		// the first task is visited again when it was held, its
+		// result must be reported only once
+		firstJobReported := false
+		reportFirstJob := func(err error) {
+			if !firstJobReported {
+				firstJobReported = true
+				firstJobErrorCh <- err
+			}
+		}
+
+		if isDelayed(t) {
+			// the tasks stay in Queued state until the start time
+			reportFirstJob(nil)
+			<-time.After(time.Until(t.StartTime))
+		}
+
 		waitCh := make(chan int, maxParallel)
+		tasks := make([]int, 0, (end-begin)/step+1)
 		for i := begin; i <= end; i += step {
-			if maxParallel > 0 {
-				// block when buffer is full - wait until jobs are finished
-				waitCh <- i
-			}
-			jobid := fmt.Sprintf("%s.%d", arrayjobid, i)
+			tasks = append(tasks, i)
+		}
 
-			jt.ps.Lock()
-			// check if job was cancelled while waiting
-			if jt.ps.jobState[jobid] == drmaa2interface.Failed {
-				jt.ps.Unlock()
-				if i == begin {
-					firstJobErrorCh <- fmt.Errorf(
-						"job %s was cancelled before it was started", jobid)
-				}
-				// skip task
-				continue
-			}
-			jt.ps.Unlock()
+		for len(tasks) > 0 {
+			var heldTasks []int
+			for _, i := range tasks {
+				jobid := fmt.Sprintf("%s.%d", arrayjobid, i)
 
-			pid, err := StartProcess(jobid, i, t, jt.ps.jobch)
-			if err != nil {
-				// job failed
-				jt.ps.Lock()
-				jt.ps.jobState[jobid] = drmaa2interface.Failed
-				jt.ps.Unlock()
-				if i == begin {
-					firstJobErrorCh <- err
+				// check if job was cancelled or held while waiting
+				if state := taskState(jt, jobid); state == drmaa2interface.Failed {
+					if i == begin {
+						reportFirstJob(fmt.Errorf(
+							"job %s was cancelled before it was started", jobid))
+					}
+					// skip task
+					continue
+				} else if state == drmaa2interface.QueuedHeld {
+					if i == begin {
+						reportFirstJob(nil)
+					}
+					heldTasks = append(heldTasks, i)
+					continue
 				}
+
 				if maxParallel > 0 {
-					<-waitCh
+					// block when buffer is full - wait until jobs are finished
+					waitCh <- i
 				}
-				continue
-			}
 
-			if maxParallel > 0 {
-				go func() {
-					jt.Wait(jobid, 0.0, drmaa2interface.Done,
-						drmaa2interface.Failed)
-					<-waitCh
-				}()
-			}
+				// check again as waiting for a free slot can take a while,
+				// holding the lock ensures that the task is not held or
+				// cancelled while it is started
+				jt.Lock()
+				if state := taskState(jt, jobid); state != drmaa2interface.Queued {
+					jt.Unlock()
+					if maxParallel > 0 {
+						<-waitCh
+					}
+					if state == drmaa2interface.QueuedHeld {
+						heldTasks = append(heldTasks, i)
+					}
+					if i == begin && state == drmaa2interface.Failed {
+						reportFirstJob(fmt.Errorf(
+							"job %s was cancelled before it was started", jobid))
+					} else if i == begin {
+						reportFirstJob(nil)
+					}
+					continue
+				}
 
-			jt.Lock()
-			// double check if process was cancelled while starting
-			jt.ps.Lock()
-			jt.js.SaveArrayJobPID(arrayjobid, i, pid)
-			if jt.ps.jobState[jobid] == drmaa2interface.Failed {
-				if running, _ := IsPidRunning(pid); running {
-					KillPid(pid)
-					jt.ps.NotifyAndWait(JobEvent{
-						JobState: drmaa2interface.Failed,
-						JobID:    jobid})
+				var pid int
+				var err error
+				if jt.scheduler != nil {
+					// the task waits in Queued state for free slots
+					err = jt.scheduleJob(jobid, i, t)
+				} else {
+					pid, err = startProcess(jobid, i, t, jt.ps.jobch, jt.jobCgroup(jobid))
 				}
-			}
-			jt.ps.Unlock()
-			jt.Unlock()
+				if err != nil {
+					// job failed
+					jt.ps.Lock()
+					jt.ps.jobState[jobid] = drmaa2interface.Failed
+					jt.ps.Unlock()
+					jt.Unlock()
+					if i == begin {
+						reportFirstJob(err)
+					}
+					if maxParallel > 0 {
+						<-waitCh
+					}
+					continue
+				}
+				if jt.scheduler == nil {
+					jt.js.SaveArrayJobPID(arrayjobid, i, pid)
+				}
+				jt.Unlock()
 
-			if i == begin {
-				firstJobErrorCh <- nil
+				if maxParallel > 0 {
+					go func() {
+						jt.Wait(jobid, 0.0, drmaa2interface.Done,
+							drmaa2interface.Failed)
+						<-waitCh
+					}()
+				}
+
+				if i == begin {
+					reportFirstJob(nil)
+				}
 			}
+			if len(heldTasks) > 0 {
+				// wait until a held task is released or terminated
+				<-wakeCh
+			}
+			tasks = heldTasks
 		}
 	}()
 	return firstJobErrorCh
 }
+
+// taskState returns the current state of a task of an array job.
+func taskState(jt *JobTracker, jobid string) drmaa2interface.JobState {
+	jt.ps.Lock()
+	defer jt.ps.Unlock()
+	return jt.ps.jobState[jobid]
+}
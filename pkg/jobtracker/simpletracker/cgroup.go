@@ -0,0 +1,125 @@
+package simpletracker
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/dgruber/drmaa2interface"
+	"github.com/dgruber/drmaa2os/pkg/extension"
+)
+
+// Each job can be placed in its own cgroup v2 leaf below a parent cgroup
+// which needs to be delegated to the user running the job tracker. The
+// parent must not contain processes itself so that the memory, cpu, and
+// pids controllers can be enabled for the leafs.
+
+var jobNameInvalidChars = regexp.MustCompile(`[^a-zA-Z0-9_.-]`)
+
+// uniqueJobName returns a name of a job which is unique across job
+// sessions. It is used for the cgroup and the checkpoint image
+// directory of the job.
+func uniqueJobName(jobsession, jobid string) string {
+	return jobNameInvalidChars.ReplaceAllString(
+		fmt.Sprintf("drmaa2os-%s-%s", jobsession, jobid), "_")
+}
+
+// createCgroup creates the cgroup with the given path and sets the
+// limits. It fails when the parent is not a cgroup v2 or the required
+// controllers are not available.
+func createCgroup(path string, limits processLimits) error {
+	parent := filepath.Dir(path)
+	if _, err := os.Stat(filepath.Join(parent, "cgroup.controllers")); err != nil {
+		return fmt.Errorf("%s is not a cgroup v2: %v", parent, err)
+	}
+	files := limits.cgroupFiles()
+	// enabling fails when the controller is already enabled or not
+	// available; the latter is detected when setting the limits
+	for _, controller := range []string{"memory", "cpu", "pids"} {
+		os.WriteFile(filepath.Join(parent, "cgroup.subtree_control"),
+			[]byte("+"+controller), 0644)
+	}
+	if err := os.Mkdir(path, 0755); err != nil {
+		return fmt.Errorf("failed to create cgroup %s: %v", path, err)
+	}
+	for file, value := range files {
+		if err := os.WriteFile(filepath.Join(path, file), []byte(value), 0644); err != nil {
+			removeCgroup(path)
+			return fmt.Errorf("failed to set %s of cgroup %s: %v", file, path, err)
+		}
+	}
+	return nil
+}
+
+// removeCgroup removes the cgroup of a job. That fails if the cgroup
+// still contains processes.
+func removeCgroup(path string) error {
+	return os.Remove(path)
+}
+
+// cgroupUsage is the resource usage of all processes of a job.
+type cgroupUsage struct {
+	// memoryPeak in bytes
+	memoryPeak int64
+	// usageUsec, userUsec, systemUsec are CPU times in microseconds
+	usageUsec  int64
+	userUsec   int64
+	systemUsec int64
+}
+
+// readCgroupUsage reads memory.peak and cpu.stat of the cgroup. memory.peak
+// is not available in older kernels.
+func readCgroupUsage(path string) (cgroupUsage, error) {
+	var usage cgroupUsage
+	if peak, err := os.ReadFile(filepath.Join(path, "memory.peak")); err == nil {
+		usage.memoryPeak, _ = strconv.ParseInt(strings.TrimSpace(string(peak)), 10, 64)
+	}
+	file, err := os.Open(filepath.Join(path, "cpu.stat"))
+	if err != nil {
+		return usage, fmt.Errorf("failed to read cpu.stat of cgroup %s: %v", path, err)
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		value, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		switch fields[0] {
+		case "usage_usec":
+			usage.usageUsec = value
+		case "user_usec":
+			usage.userUsec = value
+		case "system_usec":
+			usage.systemUsec = value
+		}
+	}
+	return usage, scanner.Err()
+}
+
+// addCgroupUsage replaces the usage of the job process in the job info
+// by the usage of all processes of the job taken from its cgroup.
+func addCgroupUsage(ji drmaa2interface.JobInfo, path string) (drmaa2interface.JobInfo, error) {
+	usage, err := readCgroupUsage(path)
+	if err != nil {
+		return ji, err
+	}
+	if ji.ExtensionList == nil {
+		ji.ExtensionList = make(map[string]string)
+	}
+	ji.CPUTime = usage.usageUsec / 1000000
+	ji.ExtensionList[extension.JobInfoDefaultJSessionUserTime] = fmt.Sprintf("%d", usage.userUsec/1000)
+	ji.ExtensionList[extension.JobInfoDefaultJSessionSystemTime] = fmt.Sprintf("%d", usage.systemUsec/1000)
+	if usage.memoryPeak > 0 {
+		ji.ExtensionList[extension.JobInfoDefaultJSessionMemoryPeak] = fmt.Sprintf("%d", usage.memoryPeak/1024)
+	}
+	return ji, nil
+}
@@ -0,0 +1,82 @@
+package simpletracker
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+
+	"golang.org/x/sys/unix"
+)
+
+// placeInCgroup lets the process be started directly in the given
+// cgroup so that all of its child processes are accounted and limited.
+// The returned function needs to be called after the process is started.
+func placeInCgroup(cmd *exec.Cmd, path string) (func(), error) {
+	dir, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open cgroup %s: %v", path, err)
+	}
+	cmd.SysProcAttr.UseCgroupFD = true
+	cmd.SysProcAttr.CgroupFD = int(dir.Fd())
+	return func() { dir.Close() }, nil
+}
+
+// withoutCgroup returns a copy of the not yet started command which is
+// not placed in a cgroup. Starting a process in a cgroup requires
+// Linux 5.7 or later.
+func withoutCgroup(cmd *exec.Cmd) *exec.Cmd {
+	attr := *cmd.SysProcAttr
+	attr.UseCgroupFD = false
+	attr.CgroupFD = 0
+	return &exec.Cmd{
+		Path:        cmd.Path,
+		Args:        append([]string{}, cmd.Args...),
+		Env:         cmd.Env,
+		Dir:         cmd.Dir,
+		Stdin:       cmd.Stdin,
+		Stdout:      cmd.Stdout,
+		Stderr:      cmd.Stderr,
+		SysProcAttr: &attr,
+		Err:         cmd.Err,
+	}
+}
+
+// prlimitOptions are the options of the prlimit command by resource.
+var prlimitOptions = map[int]string{
+	unix.RLIMIT_CORE:   "--core",
+	unix.RLIMIT_DATA:   "--data",
+	unix.RLIMIT_FSIZE:  "--fsize",
+	unix.RLIMIT_STACK:  "--stack",
+	unix.RLIMIT_NOFILE: "--nofile",
+	unix.RLIMIT_CPU:    "--cpu",
+	unix.RLIMIT_AS:     "--as",
+}
+
+// startWithRlimits starts the process with the given resource limits.
+// As exec.Cmd offers no way to set them for the child process only, the
+// job is executed by the prlimit command (util-linux) which sets the
+// limits for itself before it executes the job. Limits are rejected
+// when the prlimit command is not available.
+func startWithRlimits(cmd *exec.Cmd, limits []rlimit) error {
+	if len(limits) == 0 {
+		return cmd.Start()
+	}
+	if cmd.Err != nil {
+		return cmd.Err
+	}
+	prlimit, err := exec.LookPath("prlimit")
+	if err != nil {
+		return fmt.Errorf("resource limits require the prlimit command: %v", err)
+	}
+	args := []string{"prlimit"}
+	for _, limit := range limits {
+		option, supported := prlimitOptions[limit.resource]
+		if !supported {
+			return fmt.Errorf("resource limit %d is not supported", limit.resource)
+		}
+		args = append(args, fmt.Sprintf("%s=%d:%d", option, limit.value, limit.value))
+	}
+	cmd.Args = append(append(args, "--", cmd.Path), cmd.Args[1:]...)
+	cmd.Path = prlimit
+	return cmd.Start()
+}
@@ -0,0 +1,24 @@
+//go:build !linux
+
+package simpletracker
+
+import (
+	"errors"
+	"os/exec"
+)
+
+// placeInCgroup fails as cgroups are only available on Linux.
+func placeInCgroup(cmd *exec.Cmd, path string) (func(), error) {
+	return nil, errors.New("cgroups are only supported on Linux")
+}
+
+// startWithRlimits starts the process without the resource limits as
+// they can only be enforced on Linux.
+func startWithRlimits(cmd *exec.Cmd, limits []rlimit) error {
+	return cmd.Start()
+}
+
+// withoutCgroup returns the command as it is never placed in a cgroup.
+func withoutCgroup(cmd *exec.Cmd) *exec.Cmd {
+	return cmd
+}
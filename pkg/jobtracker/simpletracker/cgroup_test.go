@@ -0,0 +1,207 @@
+package simpletracker
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/dgruber/drmaa2interface"
+	"github.com/dgruber/drmaa2os/pkg/extension"
+)
+
+var _ = Describe("Resource limits", func() {
+
+	Context("conversion of the job template", func() {
+
+		It("should derive the limits from the job template", func() {
+			limits, err := newProcessLimits(drmaa2interface.JobTemplate{
+				MinPhysMemory: 2048,
+				MaxSlots:      2,
+				ResourceLimits: map[string]string{
+					extension.ResourceLimitProcesses:     "100",
+					extension.ResourceLimitOpenFiles:     "64",
+					extension.ResourceLimitCPUTime:       "1m",
+					extension.ResourceLimitFileSize:      "10",
+					extension.ResourceLimitWallclockTime: "10",
+				},
+			})
+			Ω(err).Should(BeNil())
+			Ω(limits.memoryMax).Should(BeNumerically("==", 2048*1024))
+			Ω(limits.cpuMax).Should(BeNumerically("==", 2))
+			Ω(limits.pidsMax).Should(BeNumerically("==", 100))
+			Ω(limits.rlimits).Should(ConsistOf(
+				rlimit{resource: syscall.RLIMIT_NOFILE, value: 64},
+				rlimit{resource: syscall.RLIMIT_CPU, value: 60},
+				rlimit{resource: syscall.RLIMIT_FSIZE, value: 10 * 1024},
+			))
+			Ω(limits.cgroupFiles()).Should(Equal(map[string]string{
+				"memory.max": "2097152",
+				"cpu.max":    "200000 100000",
+				"pids.max":   "100",
+			}))
+		})
+
+		It("should use the lower memory limit", func() {
+			limits, err := newProcessLimits(drmaa2interface.JobTemplate{
+				MinPhysMemory: 2048,
+				ResourceLimits: map[string]string{
+					extension.ResourceLimitVirtualMemory: "1024",
+				},
+			})
+			Ω(err).Should(BeNil())
+			Ω(limits.memoryMax).Should(BeNumerically("==", 1024*1024))
+		})
+
+		It("should set only the virtual memory limit with setrlimit without cgroup", func() {
+			limits, err := newProcessLimits(drmaa2interface.JobTemplate{
+				MinPhysMemory: 1024,
+			})
+			Ω(err).Should(BeNil())
+			Ω(limits.processRlimits(true)).Should(BeEmpty())
+			Ω(limits.processRlimits(false)).Should(BeEmpty())
+
+			limits, err = newProcessLimits(drmaa2interface.JobTemplate{
+				MinPhysMemory: 1024,
+				ResourceLimits: map[string]string{
+					extension.ResourceLimitVirtualMemory: "4096",
+				},
+			})
+			Ω(err).Should(BeNil())
+			Ω(limits.memoryMax).Should(BeNumerically("==", 1024*1024))
+			Ω(limits.processRlimits(true)).Should(BeEmpty())
+			Ω(limits.processRlimits(false)).Should(ConsistOf(
+				rlimit{resource: syscall.RLIMIT_AS, value: 4096 * 1024}))
+		})
+
+		It("should reject invalid limits", func() {
+			_, err := newProcessLimits(drmaa2interface.JobTemplate{
+				ResourceLimits: map[string]string{
+					extension.ResourceLimitOpenFiles: "many",
+				},
+			})
+			Ω(err).ShouldNot(BeNil())
+			_, err = newProcessLimits(drmaa2interface.JobTemplate{
+				ResourceLimits: map[string]string{
+					extension.ResourceLimitCPUTime: "-1",
+				},
+			})
+			Ω(err).ShouldNot(BeNil())
+			_, err = newProcessLimits(drmaa2interface.JobTemplate{
+				MinPhysMemory: -1,
+			})
+			Ω(err).ShouldNot(BeNil())
+		})
+
+	})
+
+	Context("cgroups", func() {
+
+		var parent string
+
+		BeforeEach(func() {
+			var err error
+			parent, err = os.MkdirTemp("", "cgroup")
+			Ω(err).Should(BeNil())
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(parent)
+		})
+
+		It("should create a sanitized cgroup name", func() {
+			Ω(uniqueJobName("my session", "1.2")).Should(Equal("drmaa2os-my_session-1.2"))
+		})
+
+		It("should create a cgroup with the limits of the job", func() {
+			err := os.WriteFile(filepath.Join(parent, "cgroup.controllers"),
+				[]byte("cpu memory pids"), 0644)
+			Ω(err).Should(BeNil())
+
+			path := filepath.Join(parent, "job")
+			err = createCgroup(path, processLimits{memoryMax: 1024, cpuMax: 1})
+			Ω(err).Should(BeNil())
+			content, err := os.ReadFile(filepath.Join(path, "memory.max"))
+			Ω(err).Should(BeNil())
+			Ω(string(content)).Should(Equal("1024"))
+			content, err = os.ReadFile(filepath.Join(path, "cpu.max"))
+			Ω(err).Should(BeNil())
+			Ω(string(content)).Should(Equal("100000 100000"))
+			_, err = os.Stat(filepath.Join(path, "pids.max"))
+			Ω(os.IsNotExist(err)).Should(BeTrue())
+		})
+
+		It("should fail when the parent is not a cgroup v2", func() {
+			err := createCgroup(filepath.Join(parent, "job"), processLimits{})
+			Ω(err).ShouldNot(BeNil())
+		})
+
+		It("should take the resource usage from the cgroup", func() {
+			err := os.WriteFile(filepath.Join(parent, "memory.peak"),
+				[]byte("4194304\n"), 0644)
+			Ω(err).Should(BeNil())
+			err = os.WriteFile(filepath.Join(parent, "cpu.stat"),
+				[]byte("usage_usec 3500000\nuser_usec 3000000\nsystem_usec 500000\nnr_periods 0\n"), 0644)
+			Ω(err).Should(BeNil())
+
+			ji, err := addCgroupUsage(drmaa2interface.JobInfo{ID: "1"}, parent)
+			Ω(err).Should(BeNil())
+			Ω(ji.ID).Should(Equal("1"))
+			Ω(ji.CPUTime).Should(BeNumerically("==", 3))
+			Ω(ji.ExtensionList[extension.JobInfoDefaultJSessionUserTime]).Should(Equal("3000"))
+			Ω(ji.ExtensionList[extension.JobInfoDefaultJSessionSystemTime]).Should(Equal("500"))
+			Ω(ji.ExtensionList[extension.JobInfoDefaultJSessionMemoryPeak]).Should(Equal("4096"))
+		})
+
+		It("should fall back to setrlimit when cgroups can't be created", func() {
+			file, err := os.CreateTemp("", "d2ostest")
+			Ω(err).Should(BeNil())
+			file.Close()
+			defer os.Remove(file.Name())
+
+			events := make(chan JobEvent, 10)
+			_, err = startProcess("1", 0, drmaa2interface.JobTemplate{
+				RemoteCommand: "/bin/sh",
+				Args:          []string{"-c", "ulimit -n"},
+				OutputPath:    file.Name(),
+				ResourceLimits: map[string]string{
+					extension.ResourceLimitOpenFiles: "32",
+				},
+			}, events, filepath.Join(parent, "job"))
+			Ω(err).Should(BeNil())
+			Eventually(events, "10s").Should(Receive(
+				HaveField("JobState", drmaa2interface.Done)))
+
+			out, err := os.ReadFile(file.Name())
+			Ω(err).Should(BeNil())
+			Ω(strings.TrimSpace(string(out))).Should(Equal("32"))
+		})
+
+		It("should start the job without cgroup when it can't be started in the cgroup", func() {
+			// the directory is created but it is not a real cgroup
+			err := os.WriteFile(filepath.Join(parent, "cgroup.controllers"),
+				[]byte("cpu memory pids"), 0644)
+			Ω(err).Should(BeNil())
+
+			events := make(chan JobEvent, 10)
+			_, err = startProcess("1", 0, drmaa2interface.JobTemplate{
+				RemoteCommand: "/bin/sh",
+				Args:          []string{"-c", "exit 0"},
+				MinPhysMemory: 1024,
+			}, events, filepath.Join(parent, "job"))
+			Ω(err).Should(BeNil())
+			Eventually(events, "10s").Should(Receive(
+				HaveField("JobState", drmaa2interface.Done)))
+		})
+
+		It("should fail to take the resource usage when there is no cgroup", func() {
+			_, err := addCgroupUsage(drmaa2interface.JobInfo{}, filepath.Join(parent, "job"))
+			Ω(err).ShouldNot(BeNil())
+		})
+
+	})
+
+})
@@ -0,0 +1,231 @@
+package simpletracker
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/dgruber/drmaa2interface"
+)
+
+// criuCommand is the CRIU binary used for checkpointing and restoring
+// the process tree of a job.
+var criuCommand = "criu"
+
+// checkpoints contains the checkpoints of processes which are in
+// progress or which succeeded by the PID of the process so that the
+// termination of a checkpointed process is not reported as the end of
+// the job.
+var checkpoints sync.Map
+
+// checkpointMark marks a process which is checkpointed.
+type checkpointMark struct {
+	pid          int
+	done         chan struct{}
+	checkpointed bool
+}
+
+// beginCheckpoint marks a process as being checkpointed.
+func beginCheckpoint(pid int) *checkpointMark {
+	mark := &checkpointMark{pid: pid, done: make(chan struct{})}
+	checkpoints.Store(pid, mark)
+	return mark
+}
+
+// finish ends the checkpoint. When the process was not checkpointed
+// the mark is removed so that the end of the process is reported.
+func (m *checkpointMark) finish(checkpointed bool) {
+	m.checkpointed = checkpointed
+	if !checkpointed {
+		checkpoints.Delete(m.pid)
+	}
+	close(m.done)
+}
+
+// endedByCheckpoint waits until a checkpoint of the finished process
+// which is in progress is done. It returns true if the process was
+// checkpointed, i.e. it ended because it was dumped.
+func endedByCheckpoint(pid int) bool {
+	mark, marked := checkpoints.LoadAndDelete(pid)
+	if !marked {
+		return false
+	}
+	<-mark.(*checkpointMark).done
+	return mark.(*checkpointMark).checkpointed
+}
+
+// checkpointProcess dumps the process tree of the given process into
+// the image directory. The processes are killed afterwards so that
+// they don't use any memory.
+func checkpointProcess(pid int, imageDir string) error {
+	if err := os.MkdirAll(imageDir, 0700); err != nil {
+		return fmt.Errorf("failed to create checkpoint image directory: %v", err)
+	}
+	out, err := exec.Command(criuCommand, "dump",
+		"--tree", strconv.Itoa(pid),
+		"--images-dir", imageDir,
+		"--log-file", "dump.log",
+		// jobs are process group leaders but share the session
+		// of the job tracker
+		"--shell-job").CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to checkpoint process %d: %v: %s",
+			pid, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// restoreProcess restores the process tree of a job from the image
+// directory and returns the PID of the restored job process. The job
+// tracker becomes the parent of the restored process when CRIU exits
+// so that it can wait for the process.
+func restoreProcess(imageDir string) (int, error) {
+	pidFile := filepath.Join(imageDir, "restore.pid")
+	err := asSubreaper(func() error {
+		out, err := exec.Command(criuCommand, "restore",
+			"--images-dir", imageDir,
+			"--log-file", "restore.log",
+			"--pidfile", pidFile,
+			"--restore-detached",
+			"--shell-job").CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("failed to restore process from %s: %v: %s",
+				imageDir, err, strings.TrimSpace(string(out)))
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	content, err := os.ReadFile(pidFile)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read PID of restored process: %v", err)
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(content)))
+	if err != nil {
+		return 0, fmt.Errorf("invalid PID of restored process: %v", err)
+	}
+	return pid, nil
+}
+
+// checkpointJob dumps the process tree of a running job into its image
+// directory and frees the resources of the job. Must be called while
+// holding the lock.
+func (jt *JobTracker) checkpointJob(jobid string, pid int) error {
+	cs, ok := jt.js.(CheckpointJobStorer)
+	if !ok {
+		return errors.New("job store does not support checkpoint / restart")
+	}
+	imageDir := filepath.Join(jt.checkpointDir, uniqueJobName(jt.jobsession, jobid))
+	mark := beginCheckpoint(pid)
+	if err := checkpointProcess(pid, imageDir); err != nil {
+		// the end of the process is reported when it finished
+		// while it was dumped
+		mark.finish(false)
+		os.RemoveAll(imageDir)
+		return err
+	}
+	mark.finish(true)
+	if err := cs.SaveCheckpoint(jobid, imageDir); err != nil {
+		// the process was killed after the dump, without the
+		// checkpoint the job can't be resumed
+		err = fmt.Errorf("failed to save checkpoint of job %s in %s: %v",
+			jobid, imageDir, err)
+		os.RemoveAll(imageDir)
+		if cgroup := jt.jobCgroup(jobid); cgroup != "" {
+			removeCgroup(cgroup)
+		}
+		jt.savePID(jobid, 0)
+		jt.ps.Lock()
+		jt.ps.setJobState(jobid, drmaa2interface.Failed, err.Error())
+		jt.ps.Unlock()
+		return err
+	}
+	// the PID can be reused when the job is not running
+	jt.savePID(jobid, 0)
+	jt.ps.Lock()
+	jt.ps.setJobState(jobid, drmaa2interface.Suspended, "")
+	jt.ps.Unlock()
+	return nil
+}
+
+// restoreJob restores a checkpointed job and tracks the restored job
+// process. Must be called while holding the lock.
+func (jt *JobTracker) restoreJob(jobid, imageDir string) error {
+	pid, err := restoreProcess(imageDir)
+	if err != nil {
+		return err
+	}
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return fmt.Errorf("failed to find restored process %d: %v", pid, err)
+	}
+	jt.savePID(jobid, pid)
+	jt.removeCheckpoint(jobid)
+	os.RemoveAll(imageDir)
+
+	jt.ps.Lock()
+	startTime := jt.ps.jobInfo[jobid].DispatchTime
+	jt.ps.setJobState(jobid, drmaa2interface.Running, "")
+	jt.ps.Unlock()
+
+	go trackProcess(nil, process, jobid, startTime, jt.ps.jobch, 0, nil,
+		jt.jobCgroup(jobid), jt.jobRuntimeLimit(jobid, startTime), jt.jobStagingPlan(jobid))
+	return nil
+}
+
+// cancelCheckpointedJob removes the checkpoint of a suspended job and
+// sets the job into failed state. It returns false if the job is not
+// checkpointed. Must be called while holding the lock.
+func (jt *JobTracker) cancelCheckpointedJob(jobid string) bool {
+	imageDir, err := jt.checkpoint(jobid)
+	if err != nil {
+		return false
+	}
+	jt.removeCheckpoint(jobid)
+	os.RemoveAll(imageDir)
+	jt.ps.Lock()
+	jt.ps.setJobState(jobid, drmaa2interface.Failed, "")
+	jt.ps.Unlock()
+	return true
+}
+
+// checkpoint returns the image directory of a checkpointed job. It
+// fails when the job is not checkpointed.
+func (jt *JobTracker) checkpoint(jobid string) (string, error) {
+	cs, ok := jt.js.(CheckpointJobStorer)
+	if !ok {
+		return "", errors.New("job store does not support checkpoint / restart")
+	}
+	return cs.GetCheckpoint(jobid)
+}
+
+// removeCheckpoint removes the image directory of a job from the job
+// store.
+func (jt *JobTracker) removeCheckpoint(jobid string) {
+	if cs, ok := jt.js.(CheckpointJobStorer); ok {
+		cs.RemoveCheckpoint(jobid)
+	}
+}
+
+// savePID stores the PID of a job or a task of an array job.
+func (jt *JobTracker) savePID(jobid string, pid int) error {
+	arrayjobid, isTask := arrayJobOfTask(jobid)
+	if !isTask {
+		ps, ok := jt.js.(PIDJobStorer)
+		if !ok {
+			return fmt.Errorf("job store can't update the PID of job %s", jobid)
+		}
+		return ps.SaveJobPID(jobid, pid)
+	}
+	taskid, err := strconv.Atoi(strings.TrimPrefix(jobid, arrayjobid+"."))
+	if err != nil {
+		return fmt.Errorf("invalid task ID in job ID %s", jobid)
+	}
+	return jt.js.SaveArrayJobPID(arrayjobid, taskid, pid)
+}
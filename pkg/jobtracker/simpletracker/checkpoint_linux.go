@@ -0,0 +1,45 @@
+package simpletracker
+
+import (
+	"fmt"
+	"sync"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// subreaperMutex serializes the restores of process trees as this
+// process is a child subreaper only while CRIU restores a process tree.
+var subreaperMutex sync.Mutex
+
+// isSubreaper returns true if orphaned descendants of this process
+// become children of this process.
+func isSubreaper() (bool, error) {
+	var subreaper int32
+	if err := unix.Prctl(unix.PR_GET_CHILD_SUBREAPER,
+		uintptr(unsafe.Pointer(&subreaper)), 0, 0, 0); err != nil {
+		return false, fmt.Errorf("failed to get child subreaper attribute: %v", err)
+	}
+	return subreaper != 0, nil
+}
+
+// asSubreaper runs f while this process is a child subreaper so that
+// orphaned descendants, like a process restored by CRIU when CRIU
+// exits, become children of this process. Afterwards the attribute is
+// reset so that other orphans are adopted by init again. The adopted
+// children need to be waited for by the caller.
+func asSubreaper(f func() error) error {
+	subreaperMutex.Lock()
+	defer subreaperMutex.Unlock()
+	subreaper, err := isSubreaper()
+	if err != nil {
+		return err
+	}
+	if !subreaper {
+		if err := unix.Prctl(unix.PR_SET_CHILD_SUBREAPER, 1, 0, 0, 0); err != nil {
+			return fmt.Errorf("failed to become child subreaper: %v", err)
+		}
+		defer unix.Prctl(unix.PR_SET_CHILD_SUBREAPER, 0, 0, 0, 0)
+	}
+	return f()
+}
@@ -0,0 +1,18 @@
+//go:build !linux
+
+package simpletracker
+
+import (
+	"errors"
+)
+
+// isSubreaper returns false as child subreapers are Linux specific.
+func isSubreaper() (bool, error) {
+	return false, nil
+}
+
+// asSubreaper fails as checkpoint / restart is only supported
+// on Linux.
+func asSubreaper(f func() error) error {
+	return errors.New("checkpoint / restart is only supported on Linux")
+}
@@ -0,0 +1,261 @@
+package simpletracker
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/dgruber/drmaa2interface"
+	"github.com/dgruber/drmaa2os/pkg/jobtracker"
+)
+
+// fakeCRIU kills the process on dump and starts a new sleep process
+// on restore.
+const fakeCRIU = `#!/bin/sh
+cmd=$1
+shift
+while [ $# -gt 0 ]; do
+	case $1 in
+	--tree) pid=$2; shift ;;
+	--images-dir) dir=$2; shift ;;
+	--pidfile) pidfile=$2; shift ;;
+	esac
+	shift
+done
+case $cmd in
+dump)
+	kill -9 $pid || exit 1
+	echo $pid > $dir/dumped
+	;;
+restore)
+	[ -f $dir/dumped ] || exit 1
+	sleep 1 &
+	echo $! > $pidfile
+	;;
+esac
+`
+
+// failingCheckpointStore fails to store checkpoints.
+type failingCheckpointStore struct {
+	*PersistentJobStorage
+}
+
+func (fs failingCheckpointStore) SaveCheckpoint(jobid string, imageDir string) error {
+	return errors.New("disk full")
+}
+
+var _ = Describe("Checkpoint / restart", func() {
+
+	var tmpDir, dbPath, originalCRIU string
+	var store *PersistentJobStorage
+	var tracker *JobTracker
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = os.MkdirTemp("", "checkpointtest")
+		Expect(err).To(BeNil())
+		criu := filepath.Join(tmpDir, "criu")
+		Expect(os.WriteFile(criu, []byte(fakeCRIU), 0700)).To(BeNil())
+		originalCRIU = criuCommand
+		criuCommand = criu
+
+		dbPath = filepath.Join(tmpDir, "jobs.db")
+		store, err = NewPersistentJobStore(dbPath)
+		Expect(err).To(BeNil())
+		tracker, err = NewWithJobStore("testsession", store, true)
+		Expect(err).To(BeNil())
+		tracker = EnableCheckpointRestartInDir(tracker,
+			filepath.Join(tmpDir, "checkpoints"))
+	})
+
+	AfterEach(func() {
+		criuCommand = originalCRIU
+		tracker.Close()
+		os.RemoveAll(tmpDir)
+	})
+
+	suspend := func() string {
+		jobid, err := tracker.AddJob(drmaa2interface.JobTemplate{
+			RemoteCommand: "sleep",
+			Args:          []string{"10"},
+		})
+		Expect(err).To(BeNil())
+		Expect(tracker.Wait(jobid, time.Second*10,
+			drmaa2interface.Running)).To(BeNil())
+
+		err = tracker.JobControl(jobid, jobtracker.JobControlSuspend)
+		Expect(err).To(BeNil())
+		return jobid
+	}
+
+	It("should checkpoint a suspended job and restore it on resume", func() {
+		jobid := suspend()
+
+		state, _, err := tracker.JobState(jobid)
+		Expect(err).To(BeNil())
+		Expect(state).To(Equal(drmaa2interface.Suspended))
+		imageDir, err := store.GetCheckpoint(jobid)
+		Expect(err).To(BeNil())
+		Expect(filepath.Join(imageDir, "dumped")).To(BeAnExistingFile())
+		pid, err := store.GetPID(jobid)
+		Expect(err).To(BeNil())
+		Expect(pid).To(Equal(0))
+
+		// the killed process must not finish the job
+		Consistently(func() drmaa2interface.JobState {
+			state, _, _ := tracker.JobState(jobid)
+			return state
+		}, "500ms").Should(Equal(drmaa2interface.Suspended))
+
+		err = tracker.JobControl(jobid, jobtracker.JobControlResume)
+		Expect(err).To(BeNil())
+		state, _, err = tracker.JobState(jobid)
+		Expect(err).To(BeNil())
+		Expect(state).To(Equal(drmaa2interface.Running))
+		Expect(imageDir).NotTo(BeADirectory())
+		// orphans are adopted only while the job is restored
+		Expect(isSubreaper()).To(BeFalse())
+		_, err = store.GetCheckpoint(jobid)
+		Expect(err).NotTo(BeNil())
+
+		Expect(tracker.Wait(jobid, time.Second*10,
+			drmaa2interface.Done)).To(BeNil())
+	})
+
+	It("should not checkpoint jobs when the job store can't store checkpoints", func() {
+		// hides the optional interfaces of the job store
+		type basicJobStore struct{ JobStorer }
+		basicStore, err := NewPersistentJobStore(filepath.Join(tmpDir, "basic.db"))
+		Expect(err).To(BeNil())
+		basicTracker, err := NewWithJobStore("testsession",
+			basicJobStore{basicStore}, false)
+		Expect(err).To(BeNil())
+		basicTracker = EnableCheckpointRestartInDir(basicTracker,
+			filepath.Join(tmpDir, "checkpoints"))
+		jobid, err := basicTracker.AddJob(drmaa2interface.JobTemplate{
+			RemoteCommand: "sleep",
+			Args:          []string{"10"},
+		})
+		Expect(err).To(BeNil())
+		Expect(basicTracker.Wait(jobid, time.Second*10,
+			drmaa2interface.Running)).To(BeNil())
+
+		err = basicTracker.JobControl(jobid, jobtracker.JobControlSuspend)
+		Expect(err).NotTo(BeNil())
+		state, _, err := basicTracker.JobState(jobid)
+		Expect(err).To(BeNil())
+		Expect(state).To(Equal(drmaa2interface.Running))
+		Expect(basicTracker.JobControl(jobid, jobtracker.JobControlTerminate)).To(BeNil())
+	})
+
+	It("should remove the checkpoint when a suspended job is terminated", func() {
+		jobid := suspend()
+		imageDir, err := store.GetCheckpoint(jobid)
+		Expect(err).To(BeNil())
+
+		err = tracker.JobControl(jobid, jobtracker.JobControlTerminate)
+		Expect(err).To(BeNil())
+		state, _, err := tracker.JobState(jobid)
+		Expect(err).To(BeNil())
+		Expect(state).To(Equal(drmaa2interface.Failed))
+		Expect(imageDir).NotTo(BeADirectory())
+
+		err = tracker.JobControl(jobid, jobtracker.JobControlResume)
+		Expect(err).NotTo(BeNil())
+	})
+
+	It("should restore a checkpointed job after a restart", func() {
+		jobid := suspend()
+		Expect(tracker.Close()).To(BeNil())
+
+		var err error
+		store, err = NewPersistentJobStore(dbPath)
+		Expect(err).To(BeNil())
+		tracker, err = NewWithJobStore("testsession", store, true)
+		Expect(err).To(BeNil())
+		tracker = EnableCheckpointRestartInDir(tracker,
+			filepath.Join(tmpDir, "checkpoints"))
+
+		state, _, err := tracker.JobState(jobid)
+		Expect(err).To(BeNil())
+		Expect(state).To(Equal(drmaa2interface.Suspended))
+
+		err = tracker.JobControl(jobid, jobtracker.JobControlResume)
+		Expect(err).To(BeNil())
+		Expect(tracker.Wait(jobid, time.Second*10,
+			drmaa2interface.Done)).To(BeNil())
+	})
+
+	It("should fail to suspend a job when the checkpoint fails", func() {
+		criuCommand = filepath.Join(tmpDir, "missing")
+		jobid, err := tracker.AddJob(drmaa2interface.JobTemplate{
+			RemoteCommand: "sleep",
+			Args:          []string{"10"},
+		})
+		Expect(err).To(BeNil())
+		Expect(tracker.Wait(jobid, time.Second*10,
+			drmaa2interface.Running)).To(BeNil())
+
+		err = tracker.JobControl(jobid, jobtracker.JobControlSuspend)
+		Expect(err).NotTo(BeNil())
+		state, _, err := tracker.JobState(jobid)
+		Expect(err).To(BeNil())
+		Expect(state).To(Equal(drmaa2interface.Running))
+
+		err = tracker.JobControl(jobid, jobtracker.JobControlTerminate)
+		Expect(err).To(BeNil())
+		Expect(tracker.Wait(jobid, time.Second*10,
+			drmaa2interface.Failed)).To(BeNil())
+	})
+
+	It("should report a job which finishes while it is checkpointed", func() {
+		criu := filepath.Join(tmpDir, "slowcriu")
+		Expect(os.WriteFile(criu, []byte("#!/bin/sh\nsleep 1\nexit 1\n"), 0700)).To(BeNil())
+		criuCommand = criu
+		jobid, err := tracker.AddJob(drmaa2interface.JobTemplate{
+			RemoteCommand: "sleep",
+			Args:          []string{"0.2"},
+		})
+		Expect(err).To(BeNil())
+		Expect(tracker.Wait(jobid, time.Second*10,
+			drmaa2interface.Running)).To(BeNil())
+
+		err = tracker.JobControl(jobid, jobtracker.JobControlSuspend)
+		Expect(err).NotTo(BeNil())
+		Expect(tracker.Wait(jobid, time.Second*10,
+			drmaa2interface.Done)).To(BeNil())
+	})
+
+	It("should fail the job when the checkpoint can't be stored", func() {
+		failingStore, err := NewPersistentJobStore(filepath.Join(tmpDir, "failing.db"))
+		Expect(err).To(BeNil())
+		failingTracker, err := NewWithJobStore("testsession",
+			failingCheckpointStore{failingStore}, false)
+		Expect(err).To(BeNil())
+		failingTracker = EnableCheckpointRestartInDir(failingTracker,
+			filepath.Join(tmpDir, "checkpoints"))
+		jobid, err := failingTracker.AddJob(drmaa2interface.JobTemplate{
+			RemoteCommand: "sleep",
+			Args:          []string{"10"},
+		})
+		Expect(err).To(BeNil())
+		Expect(failingTracker.Wait(jobid, time.Second*10,
+			drmaa2interface.Running)).To(BeNil())
+
+		err = failingTracker.JobControl(jobid, jobtracker.JobControlSuspend)
+		Expect(err).NotTo(BeNil())
+		ji, err := failingTracker.JobInfo(jobid)
+		Expect(err).To(BeNil())
+		Expect(ji.State).To(Equal(drmaa2interface.Failed))
+		Expect(ji.SubState).To(ContainSubstring("checkpoint"))
+		Expect(filepath.Join(tmpDir, "checkpoints",
+			uniqueJobName("testsession", jobid))).NotTo(BeADirectory())
+		Expect(failingTracker.Wait(jobid, time.Second,
+			drmaa2interface.Failed)).To(BeNil())
+	})
+
+})
@@ -0,0 +1,155 @@
+package simpletracker
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dgruber/drmaa2interface"
+)
+
+// SubStateQueuedBeforeRestart is the SubState of a job which was
+// queued when the application stopped.
+const SubStateQueuedBeforeRestart = "queued before application started"
+
+// SubStateCancelledBeforeStart is the SubState of a job which was
+// terminated while it was waiting for its start time.
+const SubStateCancelledBeforeStart = "cancelled before start time"
+
+// isDelayed returns true if the job must not be started before its
+// start time.
+func isDelayed(t drmaa2interface.JobTemplate) bool {
+	return !t.StartTime.IsZero() && t.StartTime.After(time.Now())
+}
+
+// scheduleStart starts the queued job at the start time of the given
+// job template. Jobs with a start time in the past are started right
+// away. Must be called while holding the lock.
+func (jt *JobTracker) scheduleStart(jobid string, t drmaa2interface.JobTemplate) {
+	jt.delayedStarts[jobid] = time.AfterFunc(time.Until(t.StartTime), func() {
+		jt.startDelayedJob(jobid)
+	})
+}
+
+// addDelayedJob saves a job which waits for its start time. Must be
+// called while holding the lock.
+func (jt *JobTracker) addDelayedJob(jobid string, t drmaa2interface.JobTemplate) error {
+	jt.js.SaveJob(jobid, t, 0)
+	return jt.delayStart(jobid, t)
+}
+
+// delayStart lets a saved job wait for its start time. When the job
+// store is persistent the job is started after an application restart.
+// Must be called while holding the lock.
+func (jt *JobTracker) delayStart(jobid string, t drmaa2interface.JobTemplate) error {
+	if ds, ok := jt.js.(DelayedJobStorer); ok {
+		if err := ds.SaveDelayedJob(jobid); err != nil {
+			return fmt.Errorf("failed to save delayed job: %v", err)
+		}
+	}
+	jt.scheduleStart(jobid, t)
+	return nil
+}
+
+// removeDelayedStart stops the timer of a delayed job. It returns false
+// if the job is not waiting for its start time. Must be called while
+// holding the lock.
+func (jt *JobTracker) removeDelayedStart(jobid string) bool {
+	timer, exists := jt.delayedStarts[jobid]
+	if !exists {
+		return false
+	}
+	timer.Stop()
+	delete(jt.delayedStarts, jobid)
+	if ds, ok := jt.js.(DelayedJobStorer); ok {
+		ds.RemoveDelayedJob(jobid)
+	}
+	return true
+}
+
+// startDelayedJob starts the process of a job which reached its start
+// time.
+func (jt *JobTracker) startDelayedJob(jobid string) {
+	jt.Lock()
+	defer jt.Unlock()
+
+	if !jt.removeDelayedStart(jobid) {
+		// job was cancelled
+		return
+	}
+	t, err := jt.js.GetJobTemplate(jobid)
+	if err != nil {
+		jt.ps.NotifyAndWait(JobEvent{
+			JobState: drmaa2interface.Failed,
+			JobID:    jobid,
+			JobInfo: drmaa2interface.JobInfo{
+				State:    drmaa2interface.Failed,
+				SubState: fmt.Sprintf("job template not found: %v", err),
+			}})
+		return
+	}
+	jt.startSavedJob(jobid, t)
+}
+
+// startSavedJob starts the process of a job which was saved without
+// a process or queues it at the local scheduler. When the process can't
+// be created the job is set into failed state. Must be called while
+// holding the lock.
+func (jt *JobTracker) startSavedJob(jobid string, t drmaa2interface.JobTemplate) {
+	var pid int
+	var err error
+	if jt.scheduler != nil {
+		err = jt.scheduleJob(jobid, 0, t)
+	} else {
+		pid, err = startProcess(jobid, 0, t, jt.ps.jobch, jt.jobCgroup(jobid))
+	}
+	if err != nil {
+		jt.ps.NotifyAndWait(JobEvent{
+			JobState: drmaa2interface.Failed,
+			JobID:    jobid,
+			JobInfo: drmaa2interface.JobInfo{
+				State:    drmaa2interface.Failed,
+				SubState: fmt.Sprintf("failed to start job: %v", err),
+			}})
+		return
+	}
+	if jt.scheduler == nil {
+		jt.savePID(jobid, pid)
+	}
+}
+
+// cancelDelayedJob sets a job which waits for its start time into
+// failed state. It returns false if the job is not waiting for its
+// start time. Must be called while holding the lock.
+func (jt *JobTracker) cancelDelayedJob(jobid string) bool {
+	if !jt.removeDelayedStart(jobid) {
+		return false
+	}
+	jt.ps.Lock()
+	jt.ps.setJobState(jobid, drmaa2interface.Failed, SubStateCancelledBeforeStart)
+	jt.ps.Unlock()
+	return true
+}
+
+// restoreDelayedJobs schedules the jobs of a persistent job store
+// which were waiting for their start time when the application
+// stopped.
+func (jt *JobTracker) restoreDelayedJobs() {
+	ds, ok := jt.js.(DelayedJobStorer)
+	if !ok {
+		return
+	}
+	jt.Lock()
+	defer jt.Unlock()
+	for _, jobid := range ds.GetDelayedJobIDs() {
+		t, err := jt.js.GetJobTemplate(jobid)
+		if err != nil {
+			ds.RemoveDelayedJob(jobid)
+			continue
+		}
+		// job is set to undetermined when loaded as it has no process
+		jt.ps.Lock()
+		jt.ps.setJobState(jobid, drmaa2interface.Queued, "")
+		jt.ps.Unlock()
+		jt.scheduleStart(jobid, t)
+	}
+}
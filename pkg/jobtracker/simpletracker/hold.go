@@ -0,0 +1,119 @@
+package simpletracker
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/dgruber/drmaa2interface"
+)
+
+// Held jobs and tasks of array jobs have no process. They stay in
+// QueuedHeld state until they are released. Released jobs are started
+// at their start time, released tasks are started by the submission
+// controller of the array job.
+
+// arrayJobOfTask returns the ID of the array job of a task or false
+// if the job ID is not a task ID.
+func arrayJobOfTask(jobid string) (string, bool) {
+	parts := strings.Split(jobid, ".")
+	if len(parts) != 2 {
+		return "", false
+	}
+	return parts[0], true
+}
+
+// holdJob puts a job which was not yet started into QueuedHeld state.
+// Must be called while holding the lock.
+func (jt *JobTracker) holdJob(jobid string) error {
+	jt.ps.Lock()
+	state, exists := jt.ps.jobState[jobid]
+	jt.ps.Unlock()
+	if !exists {
+		return errors.New("job does not exist")
+	}
+	if state == drmaa2interface.QueuedHeld {
+		return nil
+	}
+	if state != drmaa2interface.Queued {
+		return errors.New("job is not queued")
+	}
+	if _, isTask := arrayJobOfTask(jobid); !isTask &&
+		!jt.removeDelayedStart(jobid) && !jt.isScheduled(jobid) {
+		return errors.New("job is not queued")
+	}
+	jt.ps.Lock()
+	jt.ps.setJobState(jobid, drmaa2interface.QueuedHeld, "")
+	jt.ps.Unlock()
+	return nil
+}
+
+// releaseJob puts a held job into Queued state. A job is started when
+// it reached its start time. Must be called while holding the lock.
+func (jt *JobTracker) releaseJob(jobid string) error {
+	jt.ps.Lock()
+	state, exists := jt.ps.jobState[jobid]
+	if !exists {
+		jt.ps.Unlock()
+		return errors.New("job does not exist")
+	}
+	if state != drmaa2interface.QueuedHeld {
+		jt.ps.Unlock()
+		return errors.New("job is not held")
+	}
+	jt.ps.setJobState(jobid, drmaa2interface.Queued, "")
+	jt.ps.Unlock()
+
+	if jt.isScheduled(jobid) {
+		// held jobs are kept in the queue of the local scheduler
+		return jt.dispatch("")
+	}
+	if arrayjobid, isTask := arrayJobOfTask(jobid); isTask {
+		jt.wakeArrayJobController(arrayjobid)
+		return nil
+	}
+	t, err := jt.js.GetJobTemplate(jobid)
+	if err != nil {
+		return err
+	}
+	if isDelayed(t) {
+		return jt.delayStart(jobid, t)
+	}
+	jt.startSavedJob(jobid, t)
+	return nil
+}
+
+// isScheduled returns true if the job waits for free slots of the
+// local scheduler. Must be called while holding the lock.
+func (jt *JobTracker) isScheduled(jobid string) bool {
+	return jt.scheduler != nil && jt.scheduler.isQueued(jobid)
+}
+
+// registerArrayJobController returns the channel which wakes up the
+// submission controller of an array job when a held task is released
+// or terminated.
+func (jt *JobTracker) registerArrayJobController(arrayjobid string) chan struct{} {
+	jt.Lock()
+	defer jt.Unlock()
+	wakeCh := make(chan struct{}, 1)
+	jt.arrayJobControllers[arrayjobid] = wakeCh
+	return wakeCh
+}
+
+func (jt *JobTracker) unregisterArrayJobController(arrayjobid string) {
+	jt.Lock()
+	defer jt.Unlock()
+	delete(jt.arrayJobControllers, arrayjobid)
+}
+
+// wakeArrayJobController informs the submission controller of an array
+// job that the state of a held task changed. Must be called while
+// holding the lock.
+func (jt *JobTracker) wakeArrayJobController(arrayjobid string) {
+	if wakeCh, exists := jt.arrayJobControllers[arrayjobid]; exists {
+		select {
+		case wakeCh <- struct{}{}:
+		default:
+			// controller is already woken up
+		}
+	}
+}
@@ -24,16 +24,19 @@ type JobStore struct {
 	jobs       map[string][]InternalJob
 	isArrayJob map[string]bool
 	jobinfo    map[string]drmaa2interface.JobInfo
+	// checkpoints are the image directories of suspended jobs
+	checkpoints map[string]string
 }
 
 // NewJobStore returns a new in memory job store for jobs.
 func NewJobStore() *JobStore {
 	return &JobStore{
-		jobids:     make([]string, 0, 512),
-		templates:  make(map[string]drmaa2interface.JobTemplate),
-		jobs:       make(map[string][]InternalJob),
-		isArrayJob: make(map[string]bool),
-		jobinfo:    make(map[string]drmaa2interface.JobInfo),
+		jobids:      make([]string, 0, 512),
+		templates:   make(map[string]drmaa2interface.JobTemplate),
+		jobs:        make(map[string][]InternalJob),
+		isArrayJob:  make(map[string]bool),
+		jobinfo:     make(map[string]drmaa2interface.JobInfo),
+		checkpoints: make(map[string]string),
 	}
 }
 
@@ -102,6 +105,7 @@ func (js *JobStore) RemoveJob(jobid string) {
 	delete(js.templates, jobid)
 	delete(js.jobs, jobid)
 	delete(js.isArrayJob, jobid)
+	delete(js.checkpoints, jobid)
 }
 
 // SaveArrayJob stores all process IDs of the tasks of an array job.
@@ -127,6 +131,19 @@ func (js *JobStore) SaveArrayJob(arrayjobid string, pids []int,
 	}
 }
 
+// SaveJobPID stores the PID of a job which was started after it was saved.
+func (js *JobStore) SaveJobPID(jobid string, pid int) error {
+	js.Lock()
+	defer js.Unlock()
+	if _, exists := js.jobs[jobid]; !exists || js.isArrayJob[jobid] {
+		return errors.New("job does not exist")
+	}
+	js.jobs[jobid] = []InternalJob{
+		{State: drmaa2interface.Running, PID: pid},
+	}
+	return nil
+}
+
 // SaveArrayJobPID stores the current PID of main process of the
 // job array task.
 func (js *JobStore) SaveArrayJobPID(arrayjobid string, taskid, pid int) error {
@@ -231,3 +248,31 @@ func (js *JobStore) GetJobInfo(jobid string) (drmaa2interface.JobInfo, error) {
 	}
 	return jobinfo, nil
 }
+
+// SaveCheckpoint stores the image directory of a checkpointed job.
+func (js *JobStore) SaveCheckpoint(jobid string, imageDir string) error {
+	js.Lock()
+	defer js.Unlock()
+	js.checkpoints[jobid] = imageDir
+	return nil
+}
+
+// GetCheckpoint returns the image directory of a checkpointed job.
+func (js *JobStore) GetCheckpoint(jobid string) (string, error) {
+	js.Lock()
+	defer js.Unlock()
+	imageDir, exists := js.checkpoints[jobid]
+	if !exists {
+		return "", fmt.Errorf("job %s is not checkpointed", jobid)
+	}
+	return imageDir, nil
+}
+
+// RemoveCheckpoint removes the image directory of a job from the job
+// store when the job was restored.
+func (js *JobStore) RemoveCheckpoint(jobid string) error {
+	js.Lock()
+	defer js.Unlock()
+	delete(js.checkpoints, jobid)
+	return nil
+}
@@ -57,6 +57,38 @@ var _ = Describe("Jobstore", func() {
 			}
 		})
 
+		It("should store the PID of a job started after it was saved", func() {
+			for _, store := range []interface {
+				JobStorer
+				PIDJobStorer
+			}{persistent, inmemory} {
+				store.SaveJob("1", drmaa2interface.JobTemplate{RemoteCommand: "rc"}, 0)
+				Ω(store.SaveJobPID("1", 77)).Should(BeNil())
+				pid, err := store.GetPID("1")
+				Ω(err).Should(BeNil())
+				Ω(pid).Should(BeNumerically("==", 77))
+				Ω(store.SaveJobPID("2", 78)).ShouldNot(BeNil())
+			}
+		})
+
+		It("should store the checkpoint of a job", func() {
+			for _, store := range []interface {
+				JobStorer
+				CheckpointJobStorer
+			}{persistent, inmemory} {
+				store.SaveJob("1", drmaa2interface.JobTemplate{RemoteCommand: "rc"}, 0)
+				_, err := store.GetCheckpoint("1")
+				Ω(err).ShouldNot(BeNil())
+				Ω(store.SaveCheckpoint("1", "/tmp/checkpoint")).Should(BeNil())
+				imageDir, err := store.GetCheckpoint("1")
+				Ω(err).Should(BeNil())
+				Ω(imageDir).Should(Equal("/tmp/checkpoint"))
+				Ω(store.RemoveCheckpoint("1")).Should(BeNil())
+				_, err = store.GetCheckpoint("1")
+				Ω(err).ShouldNot(BeNil())
+			}
+		})
+
 		It("should find PID of array job task", func() {
 			for _, store := range []JobStorer{persistent, inmemory} {
 				store.SaveArrayJob("13",
@@ -146,6 +178,18 @@ var _ = Describe("Jobstore", func() {
 			}
 		})
 
+		It("should distinguish jobs and array jobs", func() {
+			for _, store := range []JobStorer{persistent, inmemory} {
+				store.SaveJob("12", drmaa2interface.JobTemplate{RemoteCommand: "rc"}, 77)
+				store.SaveArrayJob("13",
+					[]int{77, 78},
+					drmaa2interface.JobTemplate{RemoteCommand: "rc"},
+					1, 2, 1)
+				Ω(store.IsArrayJob("12")).Should(BeFalse())
+				Ω(store.IsArrayJob("13")).Should(BeTrue())
+			}
+		})
+
 		It("should save and a job array and add the PID of a task afterwards", func() {
 			for _, store := range []JobStorer{persistent, inmemory} {
 				store.SaveArrayJob("13",
@@ -233,6 +277,27 @@ var _ = Describe("Jobstore", func() {
 
 	Context("Persistent JobStore operations", func() {
 
+		It("should store the delayed jobs", func() {
+			file, err := os.CreateTemp("", "jobstoretest")
+			Expect(err).To(BeNil())
+			file.Close()
+			defer os.Remove(file.Name())
+			store, err := NewPersistentJobStore(file.Name())
+			Expect(err).To(BeNil())
+			defer store.Close()
+
+			store.SaveJob("1", drmaa2interface.JobTemplate{RemoteCommand: "sleep"}, 0)
+			Expect(store.SaveDelayedJob("1")).To(BeNil())
+			store.SaveJob("2", drmaa2interface.JobTemplate{RemoteCommand: "sleep"}, 0)
+			Expect(store.SaveDelayedJob("2")).To(BeNil())
+			Expect(store.GetDelayedJobIDs()).To(ConsistOf("1", "2"))
+
+			Expect(store.RemoveDelayedJob("1")).To(BeNil())
+			Expect(store.GetDelayedJobIDs()).To(ConsistOf("2"))
+			store.RemoveJob("2")
+			Expect(store.GetDelayedJobIDs()).To(BeEmpty())
+		})
+
 		It("should fail to create a persistent job storage when DB file is not set", func() {
 			var err error
 			persistent, err := NewPersistentJobStore("")
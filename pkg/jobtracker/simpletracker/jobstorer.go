@@ -28,3 +28,28 @@ type JobStorer interface {
 type StoreCloser interface {
 	Close() error
 }
+
+// PIDJobStorer stores the PID of a job which was started after it was
+// saved. It is required for jobs which are not started right away, like
+// held jobs, jobs waiting for their start time, or jobs queued by the
+// local scheduler.
+type PIDJobStorer interface {
+	SaveJobPID(jobid string, pid int) error
+}
+
+// CheckpointJobStorer stores the image directories of jobs which were
+// checkpointed when they were suspended. It is required for checkpoint
+// / restart.
+type CheckpointJobStorer interface {
+	SaveCheckpoint(jobid string, imageDir string) error
+	GetCheckpoint(jobid string) (string, error)
+	RemoveCheckpoint(jobid string) error
+}
+
+// DelayedJobStorer stores the IDs of jobs which wait for their start
+// time so that they are started after a restart of the application.
+type DelayedJobStorer interface {
+	SaveDelayedJob(jobid string) error
+	RemoveDelayedJob(jobid string) error
+	GetDelayedJobIDs() []string
+}
@@ -24,6 +24,8 @@ const JobStorageKey string = "JobStorageKey"
 const IsArrayJobStorageKey string = "IsArrayJobStorageKey"
 const HighestJobIDStorageKey string = "HighestJobIDStorageKey"
 const JobInfoStorageKey string = "JobInfoStorageKey"
+const DelayedJobsStorageKey string = "DelayedJobsStorageKey"
+const CheckpointsStorageKey string = "CheckpointsStorageKey"
 
 // PersistentJobStorage is an internal storage for jobs and job templates
 // processed by the job tracker. Jobs are stored until Reap().
@@ -88,6 +90,14 @@ func NewPersistentJobStore(path string) (*PersistentJobStorage, error) {
 		if err != nil {
 			return err
 		}
+		_, err = tx.CreateBucketIfNotExists([]byte(DelayedJobsStorageKey))
+		if err != nil {
+			return err
+		}
+		_, err = tx.CreateBucketIfNotExists([]byte(CheckpointsStorageKey))
+		if err != nil {
+			return err
+		}
 		return nil
 	})
 
@@ -186,7 +196,7 @@ func (js *PersistentJobStorage) IsArrayJob(jobid string) bool {
 			return fmt.Errorf("bucket with name %s not found", IsArrayJobStorageKey)
 		}
 		isArrayJobDBEntry := db.Get([]byte(jobid))
-		if isArrayJobDBEntry == nil {
+		if isArrayJobDBEntry == nil || string(isArrayJobDBEntry) != "true" {
 			return fmt.Errorf("job %s is no array job", jobid)
 		}
 		// is array job
@@ -255,6 +265,18 @@ func (js *PersistentJobStorage) RemoveJob(jobid string) {
 		}
 		db.Delete([]byte(jobid))
 
+		db, err = tx.CreateBucketIfNotExists([]byte(DelayedJobsStorageKey))
+		if err != nil {
+			return err
+		}
+		db.Delete([]byte(jobid))
+
+		db, err = tx.CreateBucketIfNotExists([]byte(CheckpointsStorageKey))
+		if err != nil {
+			return err
+		}
+		db.Delete([]byte(jobid))
+
 		return nil
 	})
 
@@ -379,6 +401,23 @@ func (js *PersistentJobStorage) SaveArrayJob(arrayjobid string, pids []int,
 
 }
 
+// SaveJobPID stores the PID of a job which was started after it was saved.
+func (js *PersistentJobStorage) SaveJobPID(jobid string, pid int) error {
+	return js.db.Update(func(tx *bolt.Tx) error {
+		internalJobs, err := js.getInternalJobs(tx, jobid)
+		if err != nil {
+			return fmt.Errorf("could not get internal job for job id %s: %v",
+				jobid, err)
+		}
+		if len(internalJobs) != 1 {
+			return fmt.Errorf("job %s is an array job", jobid)
+		}
+		internalJobs[0].PID = pid
+		internalJobs[0].State = drmaa2interface.Running
+		return js.saveInternalJobs(tx, jobid, internalJobs)
+	})
+}
+
 // SaveArrayJobPID stores the current PID of main process of the
 // job array task.
 func (js *PersistentJobStorage) SaveArrayJobPID(arrayjobid string, taskid, pid int) error {
@@ -594,3 +633,96 @@ func (js *PersistentJobStorage) SaveJobInfo(jobid string, jobinfo drmaa2interfac
 		return nil
 	})
 }
+
+// SaveDelayedJob marks a job as waiting for its start time.
+func (js *PersistentJobStorage) SaveDelayedJob(jobid string) error {
+	return js.db.Update(func(tx *bolt.Tx) error {
+		db, err := tx.CreateBucketIfNotExists([]byte(DelayedJobsStorageKey))
+		if err != nil {
+			return err
+		}
+		err = db.Put([]byte(jobid), []byte(jobid))
+		if err != nil {
+			return fmt.Errorf("failed to save delayed job: %v", err)
+		}
+		return nil
+	})
+}
+
+// RemoveDelayedJob removes the mark of a job which was started or
+// cancelled before its start time.
+func (js *PersistentJobStorage) RemoveDelayedJob(jobid string) error {
+	return js.db.Update(func(tx *bolt.Tx) error {
+		db, err := tx.CreateBucketIfNotExists([]byte(DelayedJobsStorageKey))
+		if err != nil {
+			return err
+		}
+		return db.Delete([]byte(jobid))
+	})
+}
+
+// GetDelayedJobIDs returns the IDs of all jobs waiting for their
+// start time.
+func (js *PersistentJobStorage) GetDelayedJobIDs() []string {
+	jobids := make([]string, 0)
+	err := js.db.View(func(tx *bolt.Tx) error {
+		db := tx.Bucket([]byte(DelayedJobsStorageKey))
+		if db == nil {
+			return fmt.Errorf("bucket %s does not exist", DelayedJobsStorageKey)
+		}
+		return db.ForEach(func(k []byte, v []byte) error {
+			jobids = append(jobids, string(k))
+			return nil
+		})
+	})
+	if err != nil {
+		log.Printf("internal error during getting delayed job ids: %v", err)
+	}
+	return jobids
+}
+
+// SaveCheckpoint stores the image directory of a checkpointed job so
+// that the job can be restored after an application restart.
+func (js *PersistentJobStorage) SaveCheckpoint(jobid string, imageDir string) error {
+	return js.db.Update(func(tx *bolt.Tx) error {
+		db, err := tx.CreateBucketIfNotExists([]byte(CheckpointsStorageKey))
+		if err != nil {
+			return err
+		}
+		err = db.Put([]byte(jobid), []byte(imageDir))
+		if err != nil {
+			return fmt.Errorf("failed to save checkpoint: %v", err)
+		}
+		return nil
+	})
+}
+
+// GetCheckpoint returns the image directory of a checkpointed job.
+func (js *PersistentJobStorage) GetCheckpoint(jobid string) (string, error) {
+	var imageDir string
+	err := js.db.View(func(tx *bolt.Tx) error {
+		db := tx.Bucket([]byte(CheckpointsStorageKey))
+		if db == nil {
+			return fmt.Errorf("bucket %s does not exist", CheckpointsStorageKey)
+		}
+		dir := db.Get([]byte(jobid))
+		if dir == nil {
+			return fmt.Errorf("job %s is not checkpointed", jobid)
+		}
+		imageDir = string(dir)
+		return nil
+	})
+	return imageDir, err
+}
+
+// RemoveCheckpoint removes the image directory of a job from the job
+// store when the job was restored.
+func (js *PersistentJobStorage) RemoveCheckpoint(jobid string) error {
+	return js.db.Update(func(tx *bolt.Tx) error {
+		db, err := tx.CreateBucketIfNotExists([]byte(CheckpointsStorageKey))
+		if err != nil {
+			return err
+		}
+		return db.Delete([]byte(jobid))
+	})
+}
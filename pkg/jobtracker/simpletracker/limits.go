@@ -0,0 +1,139 @@
+package simpletracker
+
+import (
+	"fmt"
+	"strconv"
+	"syscall"
+
+	"github.com/dgruber/drmaa2interface"
+	"github.com/dgruber/drmaa2os/pkg/extension"
+	"github.com/dgruber/drmaa2os/pkg/helper"
+)
+
+// cpuPeriod is the period in microseconds used for cpu.max.
+const cpuPeriod = 100000
+
+// rlimit is a resource limit which is set with setrlimit for a process.
+type rlimit struct {
+	resource int
+	value    uint64
+}
+
+// processLimits are the resource limits of a job derived from the
+// JobTemplate. Unset values are 0.
+type processLimits struct {
+	// memoryMax is the max. amount of memory in bytes which is only
+	// enforced in a cgroup
+	memoryMax int64
+	// virtualMemory is the max. size of the address space in bytes
+	// requested in the ResourceLimits
+	virtualMemory int64
+	// cpuMax is the max. amount of CPUs the job can use
+	cpuMax int64
+	// pidsMax is the max. amount of processes and threads of the job
+	pidsMax int64
+	// rlimits are set independent of cgroups
+	rlimits []rlimit
+}
+
+// newProcessLimits converts the ResourceLimits, MinPhysMemory, and
+// MaxSlots of the job template into resource limits of a process.
+// MinPhysMemory is a requirement of the job which is only enforced as
+// memory limit of its cgroup.
+// Sizes in ResourceLimits are given in kilobytes. CPU_TIME is given
+// in seconds or as duration (like "1h").
+func newProcessLimits(t drmaa2interface.JobTemplate) (processLimits, error) {
+	limits := processLimits{
+		memoryMax: t.MinPhysMemory * 1024,
+		cpuMax:    t.MaxSlots,
+	}
+	if limits.memoryMax < 0 || limits.cpuMax < 0 {
+		return limits, fmt.Errorf("MinPhysMemory and MaxSlots must not be negative")
+	}
+
+	kilobytes := map[string]int{
+		extension.ResourceLimitCoreFileSize: syscall.RLIMIT_CORE,
+		extension.ResourceLimitDataSegSize:  syscall.RLIMIT_DATA,
+		extension.ResourceLimitFileSize:     syscall.RLIMIT_FSIZE,
+		extension.ResourceLimitStackSize:    syscall.RLIMIT_STACK,
+	}
+
+	for key, value := range t.ResourceLimits {
+		switch key {
+		case extension.ResourceLimitCoreFileSize, extension.ResourceLimitDataSegSize,
+			extension.ResourceLimitFileSize, extension.ResourceLimitStackSize:
+			size, err := parseLimit(key, value)
+			if err != nil {
+				return limits, err
+			}
+			limits.rlimits = append(limits.rlimits,
+				rlimit{resource: kilobytes[key], value: uint64(size) * 1024})
+		case extension.ResourceLimitOpenFiles:
+			files, err := parseLimit(key, value)
+			if err != nil {
+				return limits, err
+			}
+			limits.rlimits = append(limits.rlimits,
+				rlimit{resource: syscall.RLIMIT_NOFILE, value: uint64(files)})
+		case extension.ResourceLimitCPUTime:
+			cpuTime, err := helper.ParseDurationLimit(value)
+			if err != nil {
+				return limits, fmt.Errorf("invalid resource limit %s: %v", key, err)
+			}
+			limits.rlimits = append(limits.rlimits,
+				rlimit{resource: syscall.RLIMIT_CPU, value: uint64(cpuTime.Seconds())})
+		case extension.ResourceLimitVirtualMemory:
+			size, err := parseLimit(key, value)
+			if err != nil {
+				return limits, err
+			}
+			limits.virtualMemory = size * 1024
+			if limits.memoryMax == 0 || limits.virtualMemory < limits.memoryMax {
+				limits.memoryMax = limits.virtualMemory
+			}
+		case extension.ResourceLimitProcesses:
+			pids, err := parseLimit(key, value)
+			if err != nil {
+				return limits, err
+			}
+			limits.pidsMax = pids
+		}
+	}
+	return limits, nil
+}
+
+func parseLimit(key, value string) (int64, error) {
+	limit, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || limit < 0 {
+		return 0, fmt.Errorf("invalid resource limit %s: %q", key, value)
+	}
+	return limit, nil
+}
+
+// cgroupFiles returns the content of the cgroup v2 interface files
+// which limit the job.
+func (l processLimits) cgroupFiles() map[string]string {
+	files := make(map[string]string)
+	if l.memoryMax > 0 {
+		files["memory.max"] = strconv.FormatInt(l.memoryMax, 10)
+	}
+	if l.cpuMax > 0 {
+		files["cpu.max"] = fmt.Sprintf("%d %d", l.cpuMax*cpuPeriod, cpuPeriod)
+	}
+	if l.pidsMax > 0 {
+		files["pids.max"] = strconv.FormatInt(l.pidsMax, 10)
+	}
+	return files
+}
+
+// processRlimits returns the limits which need to be set with setrlimit.
+// Without a cgroup an explicitly requested virtual memory limit becomes
+// the limit of the address space. MinPhysMemory, CPU, and process limits
+// can't be enforced without a cgroup.
+func (l processLimits) processRlimits(inCgroup bool) []rlimit {
+	if inCgroup || l.virtualMemory == 0 {
+		return l.rlimits
+	}
+	return append(append([]rlimit{}, l.rlimits...),
+		rlimit{resource: syscall.RLIMIT_AS, value: uint64(l.virtualMemory)})
+}
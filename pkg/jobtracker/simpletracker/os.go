@@ -6,11 +6,14 @@ import (
 	"io"
 	"os"
 	"os/exec"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
 
 	"github.com/dgruber/drmaa2interface"
+	"github.com/dgruber/drmaa2os/pkg/extension"
+	"github.com/dgruber/drmaa2os/pkg/helper"
 )
 
 func currentEnv() map[string]string {
@@ -35,6 +38,22 @@ func restoreEnv(env map[string]string) {
 // created. The given channel is used for communicating back
 // when the job state changed.
 func StartProcess(jobid string, task int, t drmaa2interface.JobTemplate, finishedJobChannel chan JobEvent) (int, error) {
+	return startProcess(jobid, task, t, finishedJobChannel, "")
+}
+
+// startProcess creates a new process like StartProcess. If cgroup is
+// set the process is started in a new cgroup v2 with the given path
+// which enforces the resource limits of the job template. If the cgroup
+// can't be created the limits are set with setrlimit. The StageInFiles
+// are staged in before the process is created and removed again when
+// the process can't be created. The DRMAA placeholders
+// in the job template are replaced. With JoinFiles stderr is written
+// to the file descriptor of stdout.
+func startProcess(jobid string, task int, t drmaa2interface.JobTemplate, finishedJobChannel chan JobEvent, cgroup string) (int, error) {
+	_, isTask := arrayJobOfTask(jobid)
+	homeDir, _ := os.UserHomeDir()
+	t = helper.ReplacePlaceholders(t, task, isTask, homeDir)
+
 	cmd := exec.Command(t.RemoteCommand, t.Args...)
 
 	cmd.SysProcAttr = &syscall.SysProcAttr{
@@ -45,6 +64,33 @@ func StartProcess(jobid string, task int, t drmaa2interface.JobTemplate, finishe
 		return 0, err
 	}
 
+	limits, err := newProcessLimits(t)
+	if err != nil {
+		return 0, err
+	}
+
+	maxRuntime, err := newRuntimeLimit(t, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	staging, err := newStagingPlan(t, task, isTask)
+	if err != nil {
+		return 0, err
+	}
+	// the staged in files are removed when the process can't be started
+	started := false
+	defer func() {
+		if !started {
+			staging.removeStagedIn()
+		}
+	}()
+	if err := staging.stageIn(); err != nil {
+		return 0, err
+	}
+
+	appendOutput := strings.ToLower(t.ExtensionList[extension.JobTemplateOutputAppend]) == "true"
+
 	waitForFiles := 0
 	waitCh := make(chan bool, 3)
 
@@ -84,13 +130,13 @@ func StartProcess(jobid string, task int, t drmaa2interface.JobTemplate, finishe
 		} else if t.OutputPath == "/dev/stderr" {
 			cmd.Stdout = os.Stderr
 		} else {
-			outfile, err := os.Create(t.OutputPath)
+			outfile, err := openOutputFile(t.OutputPath, appendOutput)
 			if err != nil {
-				if err != nil {
-					return 0, fmt.Errorf("could not truncate/create output file %s: %v",
-						t.OutputPath, err)
-				}
+				return 0, fmt.Errorf("could not truncate/create output file %s: %v",
+					t.OutputPath, err)
 			}
+			// the process has its own file descriptor after start
+			defer outfile.Close()
 			cmd.Stdout = outfile
 		}
 
@@ -107,18 +153,27 @@ func StartProcess(jobid string, task int, t drmaa2interface.JobTemplate, finishe
 		*/
 
 	}
-	if t.ErrorPath != "" {
+	if t.JoinFiles {
+		// stderr shares the file descriptor of stdout so that the
+		// output is written in the order in which it is produced
+		cmd.Stderr = cmd.Stdout
+	} else if t.ErrorPath != "" {
 
 		if t.ErrorPath == "/dev/stdout" {
 			cmd.Stderr = os.Stdout
 		} else if t.ErrorPath == "/dev/stderr" {
 			cmd.Stderr = os.Stderr
+		} else if t.ErrorPath == t.OutputPath {
+			// opening the same file twice would truncate it twice
+			// and let both streams overwrite each other
+			cmd.Stderr = cmd.Stdout
 		} else {
-			outfile, err := os.Create(t.ErrorPath)
+			outfile, err := openOutputFile(t.ErrorPath, appendOutput)
 			if err != nil {
 				return 0, fmt.Errorf("failed to truncate/create error file %s: %v",
 					t.ErrorPath, err)
 			}
+			defer outfile.Close()
 			cmd.Stderr = outfile
 		}
 
@@ -150,9 +205,32 @@ func StartProcess(jobid string, task int, t drmaa2interface.JobTemplate, finishe
 		}
 	}
 
-	if err := cmd.Start(); err != nil {
+	// the process is started without cgroup when it can't be placed
+	// in the cgroup (like on kernels older than 5.7)
+	plainCmd := cmd
+	if cgroup != "" {
+		if err := createCgroup(cgroup, limits); err != nil {
+			cgroup = ""
+		} else if closeCgroup, err := placeInCgroup(cmd, cgroup); err != nil {
+			removeCgroup(cgroup)
+			cgroup = ""
+		} else {
+			defer closeCgroup()
+			plainCmd = withoutCgroup(cmd)
+		}
+	}
+
+	err = startWithRlimits(cmd, limits.processRlimits(cgroup != ""))
+	if err != nil && cgroup != "" {
+		removeCgroup(cgroup)
+		cgroup = ""
+		cmd = plainCmd
+		err = startWithRlimits(cmd, limits.processRlimits(false))
+	}
+	if err != nil {
 		return 0, err
 	}
+	started = true
 
 	host, _ := os.Hostname()
 	startTime := time.Now()
@@ -167,7 +245,7 @@ func StartProcess(jobid string, task int, t drmaa2interface.JobTemplate, finishe
 		},
 	}
 
-	go TrackProcess(cmd, nil, jobid, startTime, finishedJobChannel, waitForFiles, waitCh)
+	go trackProcess(cmd, nil, jobid, startTime, finishedJobChannel, waitForFiles, waitCh, cgroup, maxRuntime, staging)
 
 	if cmd.Process == nil {
 		return 0, errors.New("process is nil")
@@ -175,6 +253,15 @@ func StartProcess(jobid string, task int, t drmaa2interface.JobTemplate, finishe
 	return cmd.Process.Pid, nil
 }
 
+// openOutputFile creates the output or error file of a job. An existing
+// file is truncated unless appendOutput is set.
+func openOutputFile(path string, appendOutput bool) (*os.File, error) {
+	if appendOutput {
+		return os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0666)
+	}
+	return os.Create(path)
+}
+
 func redirectOut(src io.ReadCloser, outfilename string, waitCh chan bool) error {
 	var outfile *os.File
 	if outfilename == "/dev/stdout" {
@@ -10,11 +10,12 @@ import (
 	. "github.com/onsi/gomega"
 
 	"github.com/dgruber/drmaa2interface"
+	"github.com/dgruber/drmaa2os/pkg/extension"
 
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"strings"
-	_ "time"
 )
 
 func exists(pid int) bool {
@@ -175,6 +176,86 @@ var _ = Describe("OS specific functionality", func() {
 			os.Remove(file.Name())
 		})
 
+		It("should write stderr into the stdout file when JoinFiles is set", func() {
+			file, err := ioutil.TempFile(os.TempDir(), "d2ostest")
+			Ω(err).Should(BeNil())
+			file.Close()
+			defer os.Remove(file.Name())
+
+			jt.RemoteCommand = "/bin/sh"
+			jt.Args = []string{"-c", "echo out1; echo err1 >&2; echo out2; echo err2 >&2"}
+			jt.OutputPath = file.Name()
+			jt.ErrorPath = "/dev/stderr"
+			jt.JoinFiles = true
+			_, err = StartProcess("1", 0, jt, outCh)
+			Ω(err).Should(BeNil())
+
+			<-outCh
+			<-outCh
+
+			out, err := ioutil.ReadFile(file.Name())
+			Ω(err).Should(BeNil())
+			Ω(string(out)).Should(Equal("out1\nerr1\nout2\nerr2\n"))
+		})
+
+		It("should open the file only once when stdout and stderr point to it", func() {
+			file, err := ioutil.TempFile(os.TempDir(), "d2ostest")
+			Ω(err).Should(BeNil())
+			file.Close()
+			defer os.Remove(file.Name())
+
+			jt.RemoteCommand = "/bin/sh"
+			jt.Args = []string{"-c", "echo out1; echo err1 >&2; echo out2"}
+			jt.OutputPath = file.Name()
+			jt.ErrorPath = file.Name()
+			_, err = StartProcess("1", 0, jt, outCh)
+			Ω(err).Should(BeNil())
+
+			<-outCh
+			<-outCh
+
+			out, err := ioutil.ReadFile(file.Name())
+			Ω(err).Should(BeNil())
+			Ω(string(out)).Should(Equal("out1\nerr1\nout2\n"))
+		})
+
+		It("should append to the output files when requested", func() {
+			tmpDir, err := ioutil.TempDir("", "d2ostest")
+			Ω(err).Should(BeNil())
+			defer os.RemoveAll(tmpDir)
+
+			jt.RemoteCommand = "/bin/sh"
+			jt.Args = []string{"-c", "echo out; echo err >&2"}
+			jt.OutputPath = filepath.Join(tmpDir, "out")
+			jt.ErrorPath = filepath.Join(tmpDir, "err")
+			jt.ExtensionList = map[string]string{
+				extension.JobTemplateOutputAppend: "true",
+			}
+			for i := 0; i < 2; i++ {
+				_, err = StartProcess("1", 0, jt, outCh)
+				Ω(err).Should(BeNil())
+				<-outCh
+				<-outCh
+			}
+
+			out, err := ioutil.ReadFile(jt.OutputPath)
+			Ω(err).Should(BeNil())
+			Ω(string(out)).Should(Equal("out\nout\n"))
+			out, err = ioutil.ReadFile(jt.ErrorPath)
+			Ω(err).Should(BeNil())
+			Ω(string(out)).Should(Equal("err\nerr\n"))
+
+			// without append mode the files are truncated
+			jt.ExtensionList = nil
+			_, err = StartProcess("1", 0, jt, outCh)
+			Ω(err).Should(BeNil())
+			<-outCh
+			<-outCh
+			out, err = ioutil.ReadFile(jt.OutputPath)
+			Ω(err).Should(BeNil())
+			Ω(string(out)).Should(Equal("out\n"))
+		})
+
 	})
 
 	Context("Redirection of file descriptors errors", func() {
@@ -231,6 +312,230 @@ var _ = Describe("OS specific functionality", func() {
 
 	})
 
+	Context("Resource limits", func() {
+
+		It("should set resource limits of the job with setrlimit", func() {
+			file, err := ioutil.TempFile("", "d2ostest")
+			Ω(err).Should(BeNil())
+			file.Close()
+			defer os.Remove(file.Name())
+
+			jt.RemoteCommand = "/bin/sh"
+			jt.Args = []string{"-c", "ulimit -n"}
+			jt.OutputPath = file.Name()
+			jt.ResourceLimits = map[string]string{
+				extension.ResourceLimitOpenFiles: "64",
+			}
+			_, err = StartProcess("1", 0, jt, outCh)
+			Ω(err).Should(BeNil())
+
+			<-outCh
+			<-outCh
+
+			out, err := ioutil.ReadFile(file.Name())
+			Ω(err).Should(BeNil())
+			Ω(strings.TrimSpace(string(out))).Should(Equal("64"))
+		})
+
+		It("should reject invalid resource limits", func() {
+			jt.ResourceLimits = map[string]string{
+				extension.ResourceLimitOpenFiles: "unlimited",
+			}
+			_, err := StartProcess("1", 0, jt, outCh)
+			Ω(err).ShouldNot(BeNil())
+		})
+
+	})
+
+	Context("Placeholders", func() {
+
+		It("should replace the DRMAA placeholders of a task", func() {
+			dir, err := os.MkdirTemp("", "d2osplaceholder")
+			Ω(err).Should(BeNil())
+			defer os.RemoveAll(dir)
+
+			jt.RemoteCommand = "/bin/echo"
+			jt.Args = []string{"task", "$drmaa_incr_ph$", "in", "$drmaa_wd_ph$"}
+			jt.WorkingDirectory = dir
+			jt.OutputPath = "$drmaa_wd_ph$/out.$drmaa_incr_ph$"
+			_, err = StartProcess("1.7", 7, jt, outCh)
+			Ω(err).Should(BeNil())
+			Eventually(outCh, "10s").Should(Receive(HaveField("JobState",
+				drmaa2interface.Done)))
+
+			out, err := os.ReadFile(filepath.Join(dir, "out.7"))
+			Ω(err).Should(BeNil())
+			Ω(string(out)).Should(Equal("task 7 in " + dir + "\n"))
+		})
+
+		It("should replace the DRMAA placeholders of task 0", func() {
+			dir, err := os.MkdirTemp("", "d2osplaceholder")
+			Ω(err).Should(BeNil())
+			defer os.RemoveAll(dir)
+
+			jt.RemoteCommand = "/bin/echo"
+			jt.Args = []string{"task", "$drmaa_incr_ph$"}
+			jt.OutputPath = filepath.Join(dir, "out.$drmaa_incr_ph$")
+			_, err = StartProcess("1.0", 0, jt, outCh)
+			Ω(err).Should(BeNil())
+			Eventually(outCh, "10s").Should(Receive(HaveField("JobState",
+				drmaa2interface.Done)))
+
+			out, err := os.ReadFile(filepath.Join(dir, "out.0"))
+			Ω(err).Should(BeNil())
+			Ω(string(out)).Should(Equal("task 0\n"))
+		})
+
+	})
+
+	Context("File staging", func() {
+
+		var dir string
+
+		BeforeEach(func() {
+			var err error
+			dir, err = os.MkdirTemp("", "d2osstaging")
+			Ω(err).Should(BeNil())
+			Ω(os.WriteFile(filepath.Join(dir, "input.txt"), []byte("in\n"), 0644)).Should(BeNil())
+			Ω(os.MkdirAll(filepath.Join(dir, "data", "sub"), 0755)).Should(BeNil())
+			Ω(os.WriteFile(filepath.Join(dir, "data", "sub", "a.txt"), []byte("a\n"), 0644)).Should(BeNil())
+			Ω(os.Mkdir(filepath.Join(dir, "wd"), 0755)).Should(BeNil())
+			jt.WorkingDirectory = filepath.Join(dir, "wd")
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(dir)
+		})
+
+		finished := func() JobEvent {
+			var event JobEvent
+			Eventually(outCh, "10s").Should(Receive(&event, HaveField("JobState",
+				SatisfyAny(Equal(drmaa2interface.Done), Equal(drmaa2interface.Failed)))))
+			return event
+		}
+
+		It("should stage in files and directories and stage out the results", func() {
+			jt.RemoteCommand = "/bin/sh"
+			jt.Args = []string{"-c", "cat input.txt copy/sub/a.txt > out.txt"}
+			jt.StageInFiles = map[string]string{
+				"file://" + filepath.Join(dir, "input.txt"): "",
+				filepath.Join(dir, "data"):                  "copy",
+			}
+			jt.StageOutFiles = map[string]string{
+				filepath.Join(dir, "results", "out.txt"): "out.txt",
+			}
+			_, err := StartProcess("1", 0, jt, outCh)
+			Ω(err).Should(BeNil())
+			Ω(finished().JobState).Should(Equal(drmaa2interface.Done))
+
+			out, err := os.ReadFile(filepath.Join(dir, "results", "out.txt"))
+			Ω(err).Should(BeNil())
+			Ω(string(out)).Should(Equal("in\na\n"))
+		})
+
+		It("should replace $TASK_ID in the paths of tasks", func() {
+			Ω(os.WriteFile(filepath.Join(dir, "input3.txt"), []byte("3"), 0644)).Should(BeNil())
+			jt.RemoteCommand = "/bin/sh"
+			jt.Args = []string{"-c", "cp input.txt out.txt"}
+			jt.StageInFiles = map[string]string{
+				filepath.Join(dir, "input$TASK_ID.txt"): "input.txt",
+			}
+			jt.StageOutFiles = map[string]string{
+				filepath.Join(dir, "out.${TASK_ID}"): "out.txt",
+			}
+			_, err := StartProcess("1.3", 3, jt, outCh)
+			Ω(err).Should(BeNil())
+			Ω(finished().JobState).Should(Equal(drmaa2interface.Done))
+
+			out, err := os.ReadFile(filepath.Join(dir, "out.3"))
+			Ω(err).Should(BeNil())
+			Ω(string(out)).Should(Equal("3"))
+		})
+
+		It("should replace $TASK_ID in the paths of task 0", func() {
+			Ω(os.WriteFile(filepath.Join(dir, "input0.txt"), []byte("0"), 0644)).Should(BeNil())
+			jt.RemoteCommand = "/bin/sh"
+			jt.Args = []string{"-c", "cp input.txt out.txt"}
+			jt.StageInFiles = map[string]string{
+				filepath.Join(dir, "input$TASK_ID.txt"): "input.txt",
+			}
+			jt.StageOutFiles = map[string]string{
+				filepath.Join(dir, "out.${TASK_ID}"): "out.txt",
+			}
+			_, err := StartProcess("1.0", 0, jt, outCh)
+			Ω(err).Should(BeNil())
+			Ω(finished().JobState).Should(Equal(drmaa2interface.Done))
+
+			out, err := os.ReadFile(filepath.Join(dir, "out.0"))
+			Ω(err).Should(BeNil())
+			Ω(string(out)).Should(Equal("0"))
+		})
+
+		It("should hard-link files when requested", func() {
+			jt.Args = []string{"0"}
+			jt.StageInFiles = map[string]string{
+				filepath.Join(dir, "input.txt"): "input.txt",
+			}
+			jt.ExtensionList = map[string]string{
+				extension.JobTemplateStageInHardLink: "TRUE",
+			}
+			_, err := StartProcess("1", 0, jt, outCh)
+			Ω(err).Should(BeNil())
+			finished()
+
+			original, err := os.Stat(filepath.Join(dir, "input.txt"))
+			Ω(err).Should(BeNil())
+			staged, err := os.Stat(filepath.Join(dir, "wd", "input.txt"))
+			Ω(err).Should(BeNil())
+			Ω(os.SameFile(original, staged)).Should(BeTrue())
+		})
+
+		It("should set the job into failed state when stage out fails", func() {
+			jt.Args = []string{"0"}
+			jt.StageOutFiles = map[string]string{
+				filepath.Join(dir, "out.txt"): "missing.txt",
+			}
+			_, err := StartProcess("1", 0, jt, outCh)
+			Ω(err).Should(BeNil())
+			event := finished()
+			Ω(event.JobState).Should(Equal(drmaa2interface.Failed))
+			Ω(event.JobInfo.SubState).Should(ContainSubstring("failed to stage out"))
+		})
+
+		It("should not start the job when stage in fails", func() {
+			jt.StageInFiles = map[string]string{
+				filepath.Join(dir, "missing.txt"): "input.txt",
+			}
+			_, err := StartProcess("1", 0, jt, outCh)
+			Ω(err).ShouldNot(BeNil())
+
+			jt.StageInFiles = map[string]string{
+				"http://example.com/input.txt": "input.txt",
+			}
+			_, err = StartProcess("1", 0, jt, outCh)
+			Ω(err).ShouldNot(BeNil())
+		})
+
+		It("should remove the staged in files when the job can't be started", func() {
+			Ω(os.WriteFile(filepath.Join(dir, "wd", "keep.txt"), []byte("keep"), 0644)).Should(BeNil())
+			jt.StageInFiles = map[string]string{
+				filepath.Join(dir, "input.txt"): "input.txt",
+				filepath.Join(dir, "data"):      "",
+			}
+			jt.InputPath = filepath.Join(dir, "missing.txt")
+			_, err := StartProcess("1", 0, jt, outCh)
+			Ω(err).ShouldNot(BeNil())
+
+			entries, err := os.ReadDir(filepath.Join(dir, "wd"))
+			Ω(err).Should(BeNil())
+			Ω(entries).Should(HaveLen(1))
+			Ω(entries[0].Name()).Should(Equal("keep.txt"))
+			Ω(filepath.Join(dir, "input.txt")).Should(BeAnExistingFile())
+			Ω(filepath.Join(dir, "data", "sub", "a.txt")).Should(BeAnExistingFile())
+		})
+
+	})
+
 	Context("Potential race conditions", func() {
 
 		It("should not block", func() {
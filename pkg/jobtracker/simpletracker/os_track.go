@@ -17,17 +17,34 @@ import (
 // process proc is given.
 func TrackProcess(cmd *exec.Cmd, proc *os.Process, jobID string, startTime time.Time,
 	finishedJobChannel chan JobEvent, waitForFiles int, waitCh chan bool) {
+	trackProcess(cmd, proc, jobID, startTime, finishedJobChannel, waitForFiles, waitCh, "", runtimeLimit{}, stagingPlan{})
+}
+
+// trackProcess supervises a process like TrackProcess. If the process
+// runs in a cgroup the resource usage is taken from the cgroup which is
+// removed afterwards. The process group is terminated when the process
+// runs longer than maxRuntime. The StageOutFiles of the staging plan
+// are staged out when the process is finished.
+func trackProcess(cmd *exec.Cmd, proc *os.Process, jobID string, startTime time.Time,
+	finishedJobChannel chan JobEvent, waitForFiles int, waitCh chan bool, cgroup string,
+	maxRuntime runtimeLimit, staging stagingPlan) {
 
 	var state *os.ProcessState
 	var err error
+	var supervisor *runtimeSupervisor
+	var signal syscall.Signal
 
 	if cmd != nil {
+		supervisor = superviseRuntime(cmd.Process.Pid, maxRuntime)
 		// wait for process and get state
 		state, err = cmd.Process.Wait()
+		signal = supervisor.stop()
 		// additionally wait for file descriptors to prevent blocking
 		cmd.Wait()
 	} else {
+		supervisor = superviseRuntime(proc.Pid, maxRuntime)
 		state, err = proc.Wait()
+		signal = supervisor.stop()
 	}
 
 	// wait until all filedescriptors (stdout, stderr) of the
@@ -39,7 +56,22 @@ func TrackProcess(cmd *exec.Cmd, proc *os.Process, jobID string, startTime time.
 		}
 	}
 
+	pid := 0
+	if cmd != nil {
+		pid = cmd.Process.Pid
+	} else if proc != nil {
+		pid = proc.Pid
+	}
+	if endedByCheckpoint(pid) {
+		// process was killed after it was checkpointed, the job
+		// continues when the process is restored
+		return
+	}
+
 	if err != nil {
+		if cgroup != "" {
+			removeCgroup(cgroup)
+		}
 		ji := makeLocalJobInfo()
 		ji.State = drmaa2interface.Failed
 		finishedJobChannel <- JobEvent{
@@ -51,6 +83,16 @@ func TrackProcess(cmd *exec.Cmd, proc *os.Process, jobID string, startTime time.
 	}
 
 	ji := collectUsage(state, jobID, startTime)
+	if cgroup != "" {
+		if cgroupJI, err := addCgroupUsage(ji, cgroup); err == nil {
+			ji = cgroupJI
+		}
+		removeCgroup(cgroup)
+	}
+	if supervisor != nil {
+		ji = supervisor.applyRuntimeLimit(ji, signal)
+	}
+	ji = staging.stageOut(ji)
 	finishedJobChannel <- JobEvent{JobState: ji.State, JobID: jobID, JobInfo: ji}
 }
 
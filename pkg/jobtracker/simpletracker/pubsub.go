@@ -6,6 +6,7 @@ import (
 	"sync"
 
 	"github.com/dgruber/drmaa2interface"
+	"github.com/dgruber/drmaa2os/pkg/helper"
 )
 
 // JobEvent is send whenever a job status change is happening
@@ -34,6 +35,18 @@ type PubSub struct {
 	jobInfo  map[string]drmaa2interface.JobInfo
 
 	jobstore JobStorer
+
+	// eventSubscribers get notified about all job events
+	eventSubscribers map[int]*eventSubscriber
+	lastSubscriberID int
+}
+
+// eventSubscriber receives DRMAA2 notifications for job events until
+// it unsubscribes.
+type eventSubscriber struct {
+	sessionName string
+	ch          chan drmaa2interface.Notification
+	done        chan struct{}
 }
 
 // NewPubSub returns an initialized PubSub structure and
@@ -44,10 +57,11 @@ func NewPubSub(jobstore JobStorer) (*PubSub, chan JobEvent) {
 	jeCh := make(chan JobEvent, 1)
 
 	pubSub := &PubSub{
-		jobch:         jeCh,
-		waitFunctions: make(map[string][]waitRequest),
-		jobState:      make(map[string]drmaa2interface.JobState),
-		jobInfo:       make(map[string]drmaa2interface.JobInfo),
+		jobch:            jeCh,
+		waitFunctions:    make(map[string][]waitRequest),
+		jobState:         make(map[string]drmaa2interface.JobState),
+		jobInfo:          make(map[string]drmaa2interface.JobInfo),
+		eventSubscribers: make(map[int]*eventSubscriber),
 	}
 
 	if jobstore != nil {
@@ -87,7 +101,7 @@ func NewPubSub(jobstore JobStorer) (*PubSub, chan JobEvent) {
 					// for queued jobs we don't even have a pid
 					pubSub.jobState[existingJobID] = drmaa2interface.Undetermined
 					jobinfo.State = drmaa2interface.Undetermined
-					jobinfo.SubState = "queued before application started"
+					jobinfo.SubState = SubStateQueuedBeforeRestart
 					pubSub.jobInfo[existingJobID] = jobinfo
 				}
 
@@ -178,6 +192,8 @@ func (ps *PubSub) StartBookKeeper() {
 	go func() {
 		for event := range ps.jobch {
 			ps.Lock()
+			previous := ps.jobInfo[event.JobID]
+			previous.State = ps.jobState[event.JobID]
 			ps.jobState[event.JobID] = event.JobState
 			if info, exists := ps.jobInfo[event.JobID]; exists {
 				ps.jobInfo[event.JobID] = mergeJobInfo(info, event.JobInfo)
@@ -198,7 +214,16 @@ func (ps *PubSub) StartBookKeeper() {
 					}
 				}
 			}
+			var events []drmaa2interface.Event
+			var subscribers []*eventSubscriber
+			if len(ps.eventSubscribers) > 0 {
+				current := ps.jobInfo[event.JobID]
+				current.State = event.JobState
+				events = helper.JobInfoChangeEvents(previous, current)
+				subscribers = ps.subscribers()
+			}
 			ps.Unlock()
+			publish(subscribers, event.JobID, event.JobState, events...)
 			if event.callback != nil {
 				event.callback <- true
 			}
@@ -206,6 +231,100 @@ func (ps *PubSub) StartBookKeeper() {
 	}()
 }
 
+// Subscribe returns a channel which emits a DRMAA2 notification for each
+// job event (state change, attribute change) of all jobs. The returned
+// function removes the subscription.
+func (ps *PubSub) Subscribe(sessionName string) (drmaa2interface.EventChannel, func()) {
+	ps.Lock()
+	defer ps.Unlock()
+	ps.lastSubscriberID++
+	id := ps.lastSubscriberID
+	subscriber := &eventSubscriber{
+		sessionName: sessionName,
+		ch:          make(chan drmaa2interface.Notification, 128),
+		done:        make(chan struct{}),
+	}
+	ps.eventSubscribers[id] = subscriber
+	var once sync.Once
+	return subscriber.ch, func() {
+		once.Do(func() {
+			ps.Lock()
+			delete(ps.eventSubscribers, id)
+			ps.Unlock()
+			close(subscriber.done)
+		})
+	}
+}
+
+// NotifyStateChange informs all subscribers about a job state change
+// which was not processed by the book keeper, like a state change
+// caused by a job control action. Must be called without holding
+// the lock.
+func (ps *PubSub) NotifyStateChange(jobID string, state drmaa2interface.JobState) {
+	ps.Lock()
+	subscribers := ps.subscribers()
+	ps.Unlock()
+	publish(subscribers, jobID, state, drmaa2interface.NewState)
+}
+
+// setJobState changes the state of a job which has no process, like a
+// job which is cancelled before it was started, and wakes up the
+// functions waiting for that state. Subscribers are not informed (see
+// NotifyStateChange). Must be called while holding the lock.
+func (ps *PubSub) setJobState(jobID string, state drmaa2interface.JobState, subState string) {
+	ps.jobState[jobID] = state
+	ji, exists := ps.jobInfo[jobID]
+	if !exists {
+		ji = drmaa2interface.CreateJobInfo()
+		ji.ID = jobID
+	}
+	ji.State = state
+	ji.SubState = subState
+	ps.jobInfo[jobID] = ji
+	if ps.jobstore != nil {
+		ps.jobstore.SaveJobInfo(jobID, ji)
+	}
+	for _, waiter := range ps.waitFunctions[jobID] {
+		for i := range waiter.ExpectedState {
+			if state == waiter.ExpectedState[i] {
+				select {
+				case waiter.WaitChannel <- state:
+				default:
+					// waiter was already informed
+				}
+			}
+		}
+	}
+}
+
+// subscribers returns a copy of the list of subscribers. Must be called
+// while holding the lock.
+func (ps *PubSub) subscribers() []*eventSubscriber {
+	subscribers := make([]*eventSubscriber, 0, len(ps.eventSubscribers))
+	for _, subscriber := range ps.eventSubscribers {
+		subscribers = append(subscribers, subscriber)
+	}
+	return subscribers
+}
+
+// publish sends the notifications to all subscribers. It blocks until
+// the subscribers consumed the notifications or unsubscribed.
+func publish(subscribers []*eventSubscriber, jobID string, state drmaa2interface.JobState, events ...drmaa2interface.Event) {
+	for _, event := range events {
+		for _, subscriber := range subscribers {
+			select {
+			case subscriber.ch <- drmaa2interface.Notification{
+				Evt:         event,
+				JobID:       jobID,
+				SessionName: subscriber.sessionName,
+				State:       state,
+			}:
+			case <-subscriber.done:
+			}
+		}
+	}
+}
+
 func (ps *PubSub) GetJobInfo(jobID string) (drmaa2interface.JobInfo, error) {
 	ps.Lock()
 	defer ps.Unlock()
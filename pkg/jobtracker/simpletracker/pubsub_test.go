@@ -4,6 +4,7 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	"fmt"
 	"time"
 
 	"github.com/dgruber/drmaa2interface"
@@ -27,6 +28,49 @@ var _ = Describe("Pubsub", func() {
 
 	})
 
+	Context("Event subscriptions", func() {
+
+		It("should emit notifications for state and attribute changes", func() {
+			ps, jeCh := NewPubSub(NewJobStore())
+			ps.StartBookKeeper()
+
+			ch, stop := ps.Subscribe("session")
+			defer stop()
+
+			jeCh <- JobEvent{JobState: drmaa2interface.Queued, JobID: "13"}
+			jeCh <- JobEvent{JobState: drmaa2interface.Running, JobID: "13"}
+			jeCh <- JobEvent{JobState: drmaa2interface.Running, JobID: "13",
+				JobInfo: drmaa2interface.JobInfo{SubState: "busy"}}
+
+			var n drmaa2interface.Notification
+			Eventually(ch).Should(Receive(&n))
+			Ω(n).Should(Equal(drmaa2interface.Notification{
+				Evt: drmaa2interface.NewState, JobID: "13",
+				SessionName: "session", State: drmaa2interface.Queued}))
+			Eventually(ch).Should(Receive(&n))
+			Ω(n.Evt).Should(Equal(drmaa2interface.NewState))
+			Ω(n.State).Should(Equal(drmaa2interface.Running))
+			Eventually(ch).Should(Receive(&n))
+			Ω(n.Evt).Should(Equal(drmaa2interface.AttributeChange))
+			Ω(n.State).Should(Equal(drmaa2interface.Running))
+		})
+
+		It("should not block the book keeper after unsubscribing", func() {
+			ps, _ := NewPubSub(NewJobStore())
+			ps.StartBookKeeper()
+
+			ch, stop := ps.Subscribe("session")
+			stop()
+
+			for i := 0; i < 256; i++ {
+				ps.NotifyAndWait(JobEvent{JobState: drmaa2interface.Queued,
+					JobID: fmt.Sprintf("%d", i)})
+			}
+			Ω(len(ch)).Should(BeNumerically("==", 0))
+		})
+
+	})
+
 	Context("Single producer and single consumer", func() {
 
 		It("should return the running state of the job", func() {
@@ -0,0 +1,254 @@
+package simpletracker
+
+import (
+	"fmt"
+	"runtime"
+	"time"
+
+	"github.com/dgruber/drmaa2interface"
+)
+
+// scheduler limits the number of slots used by the running jobs of
+// a job tracker. Jobs stay in Queued state until enough slots are
+// free. Queued jobs are started in the order of their priority and
+// their submission time.
+type scheduler struct {
+	// slots is the total amount of slots
+	slots int
+	// used is the amount of slots used by running jobs
+	used int
+	// running maps the running jobs to their slots
+	running map[string]int
+	// queue contains the jobs waiting for free slots ordered by
+	// priority and submission time
+	queue []*pendingJob
+	// seq orders jobs with the same submission time
+	seq int64
+}
+
+// pendingJob is a job or task of an array job waiting for free slots.
+type pendingJob struct {
+	jobid          string
+	taskid         int
+	template       drmaa2interface.JobTemplate
+	slots          int
+	submissionTime time.Time
+	seq            int64
+}
+
+func newScheduler(slots int) *scheduler {
+	return &scheduler{
+		slots:   slots,
+		running: make(map[string]int),
+	}
+}
+
+// localSlots returns the number of CPUs of the local machine.
+func localSlots() int {
+	machine, err := GetLocalMachineInfo()
+	if err != nil {
+		return runtime.NumCPU()
+	}
+	cpus := machine.Sockets * machine.CoresPerSocket * machine.ThreadsPerCore
+	if cpus < 1 {
+		return runtime.NumCPU()
+	}
+	return int(cpus)
+}
+
+// jobSlots returns the slots a job requires.
+func jobSlots(t drmaa2interface.JobTemplate) int {
+	if t.MinSlots > 1 {
+		return int(t.MinSlots)
+	}
+	return 1
+}
+
+// runsBefore returns true if job a must be started before job b.
+func (a *pendingJob) runsBefore(b *pendingJob) bool {
+	if a.template.Priority != b.template.Priority {
+		return a.template.Priority > b.template.Priority
+	}
+	if !a.submissionTime.Equal(b.submissionTime) {
+		return a.submissionTime.Before(b.submissionTime)
+	}
+	return a.seq < b.seq
+}
+
+// push adds a job to the queue.
+func (s *scheduler) push(job *pendingJob) {
+	s.seq++
+	job.seq = s.seq
+	i := len(s.queue)
+	for i > 0 && job.runsBefore(s.queue[i-1]) {
+		i--
+	}
+	s.queue = append(s.queue, nil)
+	copy(s.queue[i+1:], s.queue[i:])
+	s.queue[i] = job
+}
+
+// remove removes the job at the given position of the queue.
+func (s *scheduler) remove(i int) {
+	s.queue = append(s.queue[:i], s.queue[i+1:]...)
+}
+
+// isQueued returns true if the job waits for free slots.
+func (s *scheduler) isQueued(jobid string) bool {
+	for _, job := range s.queue {
+		if job.jobid == jobid {
+			return true
+		}
+	}
+	return false
+}
+
+// release frees the slots of a finished job.
+func (s *scheduler) release(jobid string) {
+	if slots, exists := s.running[jobid]; exists {
+		s.used -= slots
+		delete(s.running, jobid)
+	}
+}
+
+// scheduleJob queues a saved job or a task of an array job and starts
+// the queued jobs for which enough slots are free. It returns an error
+// when the job requires more slots than available or when the job is
+// started right away and the process can't be created. Must be called
+// while holding the lock.
+func (jt *JobTracker) scheduleJob(jobid string, taskid int, t drmaa2interface.JobTemplate) error {
+	slots := jobSlots(t)
+	if slots > jt.scheduler.slots {
+		return fmt.Errorf("job requires %d slots but only %d slots are available",
+			slots, jt.scheduler.slots)
+	}
+	jt.ps.Lock()
+	submissionTime := jt.ps.jobInfo[jobid].SubmissionTime
+	jt.ps.Unlock()
+	jt.scheduler.push(&pendingJob{
+		jobid:          jobid,
+		taskid:         taskid,
+		template:       t,
+		slots:          slots,
+		submissionTime: submissionTime,
+	})
+	return jt.dispatch(jobid)
+}
+
+// dispatch starts the queued jobs in their order as long as there are
+// enough free slots. Held jobs are skipped, terminated jobs are removed
+// from the queue. Jobs which can't be started are set into failed
+// state, besides the given job for which the error is returned. Must
+// be called while holding the lock.
+func (jt *JobTracker) dispatch(jobid string) error {
+	var startErr error
+	s := jt.scheduler
+	for i := 0; i < len(s.queue); {
+		job := s.queue[i]
+		jt.ps.Lock()
+		state := jt.ps.jobState[job.jobid]
+		jt.ps.Unlock()
+		if state == drmaa2interface.QueuedHeld {
+			i++
+			continue
+		}
+		if state != drmaa2interface.Queued {
+			// job was terminated while waiting
+			s.remove(i)
+			continue
+		}
+		if job.slots > s.slots-s.used {
+			// lower prioritized jobs must not overtake the job
+			break
+		}
+		s.remove(i)
+		pid, err := startProcess(job.jobid, job.taskid, job.template,
+			jt.ps.jobch, jt.jobCgroup(job.jobid))
+		if err != nil {
+			if job.jobid == jobid {
+				startErr = err
+				continue
+			}
+			jt.ps.NotifyAndWait(JobEvent{
+				JobState: drmaa2interface.Failed,
+				JobID:    job.jobid,
+				JobInfo: drmaa2interface.JobInfo{
+					State:    drmaa2interface.Failed,
+					SubState: fmt.Sprintf("failed to start job: %v", err),
+				}})
+			continue
+		}
+		jt.savePID(job.jobid, pid)
+		s.used += job.slots
+		s.running[job.jobid] = job.slots
+		go jt.releaseSlotsWhenFinished(job.jobid)
+	}
+	return startErr
+}
+
+// releaseSlotsWhenFinished waits until the job is finished and starts
+// the queued jobs which fit into the freed slots.
+func (jt *JobTracker) releaseSlotsWhenFinished(jobid string) {
+	jt.Wait(jobid, 0, drmaa2interface.Done, drmaa2interface.Failed)
+	jt.Lock()
+	defer jt.Unlock()
+	jt.scheduler.release(jobid)
+	jt.dispatch("")
+}
+
+// restoreScheduledJobs books the slots of the jobs of a persistent job
+// store which are still running and queues the jobs which were waiting
+// for free slots when the application stopped. Held jobs are queued
+// when they are released, delayed jobs when they reached their start
+// time. Tasks of array jobs are not restored. Must be called while
+// holding the lock.
+func (jt *JobTracker) restoreScheduledJobs() {
+	s := jt.scheduler
+	var queued []string
+	for _, jobid := range jt.js.GetJobIDs() {
+		if jt.js.IsArrayJob(jobid) {
+			continue
+		}
+		pid, err := jt.js.GetPID(jobid)
+		if err != nil {
+			continue
+		}
+		jt.ps.Lock()
+		ji := jt.ps.jobInfo[jobid]
+		jt.ps.Unlock()
+		if pid == 0 {
+			if ji.State == drmaa2interface.Undetermined &&
+				ji.SubState == SubStateQueuedBeforeRestart {
+				queued = append(queued, jobid)
+			}
+			continue
+		}
+		if ji.State != drmaa2interface.Running &&
+			ji.State != drmaa2interface.Suspended {
+			continue
+		}
+		t, err := jt.js.GetJobTemplate(jobid)
+		if err != nil {
+			continue
+		}
+		slots := jobSlots(t)
+		s.used += slots
+		s.running[jobid] = slots
+		go jt.releaseSlotsWhenFinished(jobid)
+	}
+	for _, jobid := range queued {
+		t, err := jt.js.GetJobTemplate(jobid)
+		if err != nil {
+			continue
+		}
+		jt.ps.Lock()
+		jt.ps.setJobState(jobid, drmaa2interface.Queued, "")
+		jt.ps.Unlock()
+		if err := jt.scheduleJob(jobid, 0, t); err != nil {
+			jt.ps.Lock()
+			jt.ps.setJobState(jobid, drmaa2interface.Failed,
+				fmt.Sprintf("failed to start job: %v", err))
+			jt.ps.Unlock()
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 	"time"
@@ -28,6 +29,27 @@ type SimpleTrackerInitParams struct {
 	UsePersistentJobStorage           bool
 	DBFilePath                        string
 	CheckPointRestartForSuspendResume bool
+	// CheckpointDir is the directory in which the process trees of jobs
+	// are stored when they are suspended with checkpoint / restart. If
+	// not set a temporary directory is used. When the persistent job
+	// storage is used it should be set to a directory which survives a
+	// reboot.
+	CheckpointDir string
+	// CgroupParent is the path of a delegated cgroup v2 (like
+	// /sys/fs/cgroup/user.slice/user-1000.slice/user@1000.service/drmaa2os)
+	// below which each job gets its own cgroup enforcing the resource
+	// limits of the job template. If not set or if the cgroups can't
+	// be created the limits are set with setrlimit.
+	CgroupParent string
+	// UseLocalScheduler limits the slots used by the running jobs of
+	// the job session. Jobs stay in Queued state until enough slots are
+	// free. Queued jobs are started by their priority (higher values
+	// first) and their submission time. A job requires MinSlots slots
+	// (at least 1).
+	UseLocalScheduler bool
+	// Slots is the amount of slots of the local scheduler. If not set
+	// the number of CPUs of the local machine is used.
+	Slots int
 }
 
 // New is called by the SessionManager when a new JobSession is allocated.
@@ -53,18 +75,72 @@ func (a *allocator) New(jobSessionName string, jobTrackerInitParams interface{})
 		}
 
 		if simpleTrackerInitParams.CheckPointRestartForSuspendResume {
-			jt = EnableCheckpointRestart(jt)
+			jt = enableCheckpointRestart(jt, simpleTrackerInitParams.CheckpointDir)
+		}
+		if simpleTrackerInitParams.CgroupParent != "" {
+			jt = EnableCgroups(jt, simpleTrackerInitParams.CgroupParent)
+		}
+		if simpleTrackerInitParams.UseLocalScheduler {
+			jt = EnableLocalScheduler(jt, simpleTrackerInitParams.Slots)
 		}
 		return jt, nil
 
 	}
 	jt := New(jobSessionName)
 	if simpleTrackerInitParams.CheckPointRestartForSuspendResume {
-		jt = EnableCheckpointRestart(jt)
+		jt = enableCheckpointRestart(jt, simpleTrackerInitParams.CheckpointDir)
+	}
+	if simpleTrackerInitParams.CgroupParent != "" {
+		jt = EnableCgroups(jt, simpleTrackerInitParams.CgroupParent)
+	}
+	if simpleTrackerInitParams.UseLocalScheduler {
+		jt = EnableLocalScheduler(jt, simpleTrackerInitParams.Slots)
 	}
 	return jt, nil
 }
 
+// DrmsName returns "process" as jobs are managed as OS processes.
+func (a *allocator) DrmsName() string {
+	return "process"
+}
+
+// DrmsVersion returns the version of the process tracker.
+func (a *allocator) DrmsVersion() (drmaa2interface.Version, error) {
+	return drmaa2interface.Version{Major: "1", Minor: "0"}, nil
+}
+
+// Supports returns true for advance reservations of slots on the
+// local machine, job arrays with a maxParallel limit, pushed job
+// events, file staging, and job deadlines.
+func (a *allocator) Supports(capability drmaa2interface.Capability) bool {
+	switch capability {
+	case drmaa2interface.AdvanceReservation,
+		drmaa2interface.ReserveSlots,
+		drmaa2interface.Callback,
+		drmaa2interface.BulkJobsMaxParallel,
+		drmaa2interface.JtStaging,
+		drmaa2interface.JtDeadline,
+		drmaa2interface.RtStartNow,
+		drmaa2interface.RtDuration,
+		drmaa2interface.RtMachineOS,
+		drmaa2interface.RtMachineArch:
+		return true
+	}
+	return false
+}
+
+// SupportsJobControl returns true for suspend, resume, hold, release,
+// and terminate.
+func (a *allocator) SupportsJobControl(action string) bool {
+	switch action {
+	case jobtracker.JobControlSuspend, jobtracker.JobControlResume,
+		jobtracker.JobControlHold, jobtracker.JobControlRelease,
+		jobtracker.JobControlTerminate:
+		return true
+	}
+	return false
+}
+
 // JobTracker implements the JobTracker interface and treats
 // jobs as OS processes.
 type JobTracker struct {
@@ -80,6 +156,18 @@ type JobTracker struct {
 	isPersistent bool
 	// checkpointRestart flags whether SIGTSTP (false) or CRIU (true) is used for suspend
 	checkpointRestart bool
+	// checkpointDir contains the CRIU image directories of suspended jobs
+	checkpointDir string
+	// cgroupParent is the cgroup v2 in which the cgroups of the jobs are created
+	cgroupParent string
+	// delayedStarts are the timers of the jobs waiting for their start time
+	delayedStarts map[string]*time.Timer
+	// arrayJobControllers wake up the submission controllers of array
+	// jobs when held tasks are released
+	arrayJobControllers map[string]chan struct{}
+	// scheduler queues jobs until enough slots are free, it is nil
+	// when jobs are started right away
+	scheduler *scheduler
 }
 
 // New creates and initializes a JobTracker.
@@ -89,12 +177,72 @@ func New(jobsession string) *JobTracker {
 }
 
 // EnableCheckpointRestart turns a job tracker which handles suspend / resume
-// with signals into a job tracker which does suspend and resume with CRIU
+// with signals into a job tracker which does suspend and resume with CRIU.
+// The process trees of suspended jobs are stored in a temporary directory.
 func EnableCheckpointRestart(jobtracker *JobTracker) *JobTracker {
+	return EnableCheckpointRestartInDir(jobtracker,
+		filepath.Join(os.TempDir(), "drmaa2os-checkpoints"))
+}
+
+// EnableCheckpointRestartInDir turns a job tracker which handles suspend /
+// resume with signals into a job tracker which does suspend and resume with
+// CRIU. The process trees of suspended jobs are stored in subdirectories
+// of the given directory. The criu binary must be in the PATH and the
+// application needs the privileges to run it.
+func EnableCheckpointRestartInDir(jobtracker *JobTracker, dir string) *JobTracker {
 	jobtracker.checkpointRestart = true
+	jobtracker.checkpointDir = dir
+	return jobtracker
+}
+
+// EnableCgroups lets each job run in its own cgroup v2 created below
+// the given parent cgroup. The cgroup limits memory (MinPhysMemory,
+// VIRTUAL_MEMORY), CPUs (MaxSlots), and processes (PROCESSES) of the
+// job and provides the memory and CPU usage of all processes of the job.
+func EnableCgroups(jobtracker *JobTracker, parent string) *JobTracker {
+	jobtracker.cgroupParent = parent
 	return jobtracker
 }
 
+// EnableLocalScheduler limits the slots used by the running jobs of the
+// job tracker. Jobs and tasks of array jobs stay in Queued state until
+// enough slots are free. Each job requires MinSlots slots (at least 1).
+// Queued jobs are started in the order of their priority (higher values
+// first) and their submission time. If slots is not greater than 0 the
+// number of CPUs of the local machine is used. With a persistent job
+// store the jobs which were queued when the application stopped are
+// queued again and the running jobs keep their slots.
+func EnableLocalScheduler(jobtracker *JobTracker, slots int) *JobTracker {
+	if slots <= 0 {
+		slots = localSlots()
+	}
+	jobtracker.Lock()
+	jobtracker.scheduler = newScheduler(slots)
+	if jobtracker.isPersistent {
+		jobtracker.restoreScheduledJobs()
+	}
+	jobtracker.Unlock()
+	return jobtracker
+}
+
+// enableCheckpointRestart enables checkpoint / restart with the
+// default directory when no directory is configured.
+func enableCheckpointRestart(jobtracker *JobTracker, dir string) *JobTracker {
+	if dir == "" {
+		return EnableCheckpointRestart(jobtracker)
+	}
+	return EnableCheckpointRestartInDir(jobtracker, dir)
+}
+
+// jobCgroup returns the path of the cgroup of a job or "" if cgroups
+// are not enabled.
+func (jt *JobTracker) jobCgroup(jobid string) string {
+	if jt.cgroupParent == "" {
+		return ""
+	}
+	return filepath.Join(jt.cgroupParent, uniqueJobName(jt.jobsession, jobid))
+}
+
 func NewWithJobStore(jobsession string, jobstore JobStorer, persistent bool) (*JobTracker, error) {
 	if jobstore == nil {
 		return nil, fmt.Errorf("require job storage")
@@ -115,6 +263,10 @@ func NewWithJobStore(jobsession string, jobstore JobStorer, persistent bool) (*J
 				fmt.Printf("failed to get pid for job %s: %v\n", jobid, err)
 				continue
 			}
+			if pid == 0 {
+				// job was not started yet
+				continue
+			}
 
 			// need to catch the process for tracker
 			process, err := os.FindProcess(int(pid))
@@ -159,11 +311,17 @@ func NewWithJobStore(jobsession string, jobstore JobStorer, persistent bool) (*J
 	}
 
 	tracker := JobTracker{
-		jobsession:   jobsession,
-		js:           jobstore,
-		shutdown:     false,
-		ps:           ps,
-		isPersistent: persistent,
+		jobsession:          jobsession,
+		js:                  jobstore,
+		shutdown:            false,
+		ps:                  ps,
+		isPersistent:        persistent,
+		delayedStarts:       make(map[string]*time.Timer),
+		arrayJobControllers: make(map[string]chan struct{}),
+	}
+
+	if persistent {
+		tracker.restoreDelayedJobs()
 	}
 
 	return &tracker, nil
@@ -188,24 +346,60 @@ func (jt *JobTracker) ListJobs() ([]string, error) {
 }
 
 // AddJob creates a process, fills in the internal job state and saves the
-// job internally.
+// job internally. When the StartTime of the job template is in the future
+// the job stays in Queued state until it is started at StartTime. Jobs
+// submitted as hold stay in QueuedHeld state until they are released.
+// With the local scheduler the job stays in Queued state until enough
+// slots are free.
 func (jt *JobTracker) AddJob(t drmaa2interface.JobTemplate) (string, error) {
 	jt.Lock()
 	defer jt.Unlock()
 
+	if t.SubmitAsHold || isDelayed(t) || jt.scheduler != nil {
+		if _, ok := jt.js.(PIDJobStorer); !ok {
+			return "", errors.New("job store does not support jobs which are not started right away")
+		}
+	}
+
 	jobid := jt.js.NewJobID()
 	jt.ps.NotifyAndWait(JobEvent{
-		JobState: drmaa2interface.Queued,
+		JobState: initialState(t),
 		JobID:    jobid,
 		JobInfo: drmaa2interface.JobInfo{
-			State:          drmaa2interface.Queued,
+			State:          initialState(t),
 			Slots:          1,
 			SubmissionTime: time.Now(),
 			ID:             jobid,
 		}})
 
+	if t.SubmitAsHold {
+		jt.js.SaveJob(jobid, t, 0)
+		return jobid, nil
+	}
+
+	if isDelayed(t) {
+		if err := jt.addDelayedJob(jobid, t); err != nil {
+			jt.ps.NotifyAndWait(JobEvent{
+				JobState: drmaa2interface.Failed,
+				JobID:    jobid})
+			return "", err
+		}
+		return jobid, nil
+	}
+
+	if jt.scheduler != nil {
+		jt.js.SaveJob(jobid, t, 0)
+		if err := jt.scheduleJob(jobid, 0, t); err != nil {
+			jt.ps.NotifyAndWait(JobEvent{
+				JobState: drmaa2interface.Failed,
+				JobID:    jobid})
+			return "", err
+		}
+		return jobid, nil
+	}
+
 	// here also an event
-	pid, err := StartProcess(jobid, 0, t, jt.ps.jobch)
+	pid, err := startProcess(jobid, 0, t, jt.ps.jobch, jt.jobCgroup(jobid))
 	if err != nil {
 		jt.ps.NotifyAndWait(JobEvent{
 			JobState: drmaa2interface.Failed,
@@ -247,7 +441,10 @@ func cleanup(pids []int) {
 }
 
 // AddArrayJob starts end-begin/step processes based on the given JobTemplate.
-// Note that maxParallel is not yet implemented.
+// Not more than maxParallel tasks are running at the same time. Tasks
+// of array jobs submitted as hold are started when they are released.
+// When the StartTime of the job template is in the future the tasks
+// stay in Queued state until StartTime.
 func (jt *JobTracker) AddArrayJob(t drmaa2interface.JobTemplate, begin, end, step, maxParallel int) (string, error) {
 	jt.Lock()
 	var pids []int
@@ -259,10 +456,10 @@ func (jt *JobTracker) AddArrayJob(t drmaa2interface.JobTemplate, begin, end, ste
 	for i := begin; i <= end; i += step {
 		jobid := fmt.Sprintf("%s.%d", arrayjobid, i)
 		jt.ps.NotifyAndWait(JobEvent{
-			JobState: drmaa2interface.Queued,
+			JobState: initialState(t),
 			JobID:    jobid,
 			JobInfo: drmaa2interface.JobInfo{
-				State:          drmaa2interface.Queued,
+				State:          initialState(t),
 				Slots:          1,
 				SubmissionTime: time.Now(),
 				ID:             jobid,
@@ -280,7 +477,7 @@ func (jt *JobTracker) AddArrayJob(t drmaa2interface.JobTemplate, begin, end, ste
 			fmt.Printf("Internal error: %v", err)
 		} else {
 			// wait until job is in QUEUED state
-			for state != drmaa2interface.Queued {
+			for state != initialState(t) {
 				fmt.Printf("waiting for job %s to be in QUEUED state\n",
 					jobid)
 				time.Sleep(time.Millisecond * 10)
@@ -305,6 +502,14 @@ func (jt *JobTracker) AddArrayJob(t drmaa2interface.JobTemplate, begin, end, ste
 	return arrayjobid, nil
 }
 
+// initialState returns the state of a submitted job.
+func initialState(t drmaa2interface.JobTemplate) drmaa2interface.JobState {
+	if t.SubmitAsHold {
+		return drmaa2interface.QueuedHeld
+	}
+	return drmaa2interface.Queued
+}
+
 func areAllJobsInJobStateMap(jt *JobTracker, arrayjobid string, begin, end, step int) bool {
 	jt.ps.Lock()
 	for i := begin; i <= end; i += step {
@@ -370,6 +575,23 @@ func (jt *JobTracker) JobInfo(jobid string) (drmaa2interface.JobInfo, error) {
 
 // JobControl suspends, resumes, or terminates a job.
 func (jt *JobTracker) JobControl(jobid, state string) error {
+	jt.ps.Lock()
+	previousState := jt.ps.jobState[jobid]
+	jt.ps.Unlock()
+
+	err := jt.jobControl(jobid, state)
+
+	// job control actions are changing the job state directly
+	jt.ps.Lock()
+	currentState, exists := jt.ps.jobState[jobid]
+	jt.ps.Unlock()
+	if exists && currentState != previousState {
+		jt.ps.NotifyStateChange(jobid, currentState)
+	}
+	return err
+}
+
+func (jt *JobTracker) jobControl(jobid, state string) error {
 	jt.Lock()
 	defer jt.Unlock()
 
@@ -384,8 +606,13 @@ func (jt *JobTracker) JobControl(jobid, state string) error {
 			return errors.New("job is not running")
 		}
 		if jt.checkpointRestart {
-			// TODO use CRIU
-			return fmt.Errorf("checkpoint / restart not implemented (TODO)")
+			jt.ps.Lock()
+			state := jt.ps.jobState[jobid]
+			jt.ps.Unlock()
+			if state != drmaa2interface.Running {
+				return errors.New("job is not running")
+			}
+			return jt.checkpointJob(jobid, pid)
 		} else {
 			err := SuspendPid(pid)
 			if err == nil {
@@ -398,12 +625,16 @@ func (jt *JobTracker) JobControl(jobid, state string) error {
 
 		return err
 	case "resume":
+		// checkpointed jobs are restored even when the job tracker
+		// was created without checkpoint / restart
+		if imageDir, err := jt.checkpoint(jobid); err == nil {
+			return jt.restoreJob(jobid, imageDir)
+		}
 		if pid == 0 {
 			return errors.New("job is not running")
 		}
 		if jt.checkpointRestart {
-			// TODO use CRIU
-			return fmt.Errorf("checkpoint / restart not implemented (TODO)")
+			return errors.New("job is not checkpointed")
 		} else {
 
 			err := ResumePid(pid)
@@ -417,15 +648,21 @@ func (jt *JobTracker) JobControl(jobid, state string) error {
 
 		return err
 	case "hold":
-		return errors.New("Unsupported Operation")
+		return jt.holdJob(jobid)
 	case "release":
-		return errors.New("Unsupported Operation")
+		return jt.releaseJob(jobid)
 	case "terminate":
+		if jt.cancelDelayedJob(jobid) || jt.cancelCheckpointedJob(jobid) {
+			return nil
+		}
+		if arrayjobid, isTask := arrayJobOfTask(jobid); isTask {
+			defer jt.wakeArrayJobController(arrayjobid)
+		}
 		// if job is queued - terminate it by setting it to
 		// failed state (see arrayJobSubmissionController())
 		jt.ps.Lock()
 		state := jt.ps.jobState[jobid]
-		if state == drmaa2interface.Queued {
+		if state == drmaa2interface.Queued || state == drmaa2interface.QueuedHeld {
 			// pid is 0 - this should not be the case
 			if pid != 0 {
 				fmt.Printf("PID is not 0\n")
@@ -437,7 +674,8 @@ func (jt *JobTracker) JobControl(jobid, state string) error {
 					return fmt.Errorf("error killing job %s: %s", jobid, err)
 				}
 			}
-			jt.ps.jobState[jobid] = drmaa2interface.Failed
+			// there is no process which reports the end of the job
+			jt.ps.setJobState(jobid, drmaa2interface.Failed, "")
 			jt.ps.Unlock()
 			return nil
 		}
@@ -517,6 +755,14 @@ func (jt *JobTracker) Wait(jobid string, d time.Duration, state ...drmaa2interfa
 	}
 }
 
+// RegisterEventNotification implements the jobtracker.EventNotifier
+// interface. The returned channel emits notifications for all job
+// events of the job session until the returned function is called.
+func (jt *JobTracker) RegisterEventNotification() (drmaa2interface.EventChannel, func(), error) {
+	ch, stop := jt.ps.Subscribe(jt.jobsession)
+	return ch, stop, nil
+}
+
 // ListJobCategories returns an empty list as JobCategories are
 // currently not defined for OS processes.
 func (jt *JobTracker) ListJobCategories() ([]string, error) {
@@ -532,6 +778,14 @@ func (jt *JobTracker) JobTemplate(jobID string) (drmaa2interface.JobTemplate, er
 // Close implmements the jobtracker.Closer interface to disengage
 // from a DB or the DRM when the job session gets closed.
 func (jt *JobTracker) Close() error {
+	jt.Lock()
+	// delayed jobs of a persistent job store are scheduled again
+	// when a new job tracker is created
+	for jobid, timer := range jt.delayedStarts {
+		timer.Stop()
+		delete(jt.delayedStarts, jobid)
+	}
+	jt.Unlock()
 	if closer, ok := jt.js.(StoreCloser); ok {
 		return closer.Close()
 	}
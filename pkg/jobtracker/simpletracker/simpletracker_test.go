@@ -2,6 +2,7 @@ package simpletracker_test
 
 import (
 	"os"
+	"path/filepath"
 
 	"github.com/dgruber/drmaa2interface"
 
@@ -158,7 +159,7 @@ var _ = Describe("Simpletracker", func() {
 				Ω(err).ShouldNot(BeNil())
 			})
 
-			It("hold and release are not supported", func() {
+			It("should not hold or release a started job", func() {
 				jobid, err := tracker.AddJob(t)
 				Ω(err).Should(BeNil())
 				err = tracker.JobControl(jobid, "hold")
@@ -799,4 +800,459 @@ var _ = Describe("Simpletracker", func() {
 
 	})
 
+	Context("Delayed start", func() {
+
+		var dbPath string
+		var store *PersistentJobStorage
+		var tracker *JobTracker
+
+		BeforeEach(func() {
+			file, err := os.CreateTemp("", "delayedstarttest")
+			Expect(err).To(BeNil())
+			dbPath = file.Name()
+			file.Close()
+			store, err = NewPersistentJobStore(dbPath)
+			Expect(err).To(BeNil())
+			tracker, err = NewWithJobStore("testsession", store, true)
+			Expect(err).To(BeNil())
+		})
+
+		AfterEach(func() {
+			tracker.Close()
+			os.Remove(dbPath)
+		})
+
+		It("should start the job at its start time", func() {
+			startTime := time.Now().Add(time.Millisecond * 500)
+			jobid, err := tracker.AddJob(drmaa2interface.JobTemplate{
+				RemoteCommand: "sleep",
+				Args:          []string{"0"},
+				StartTime:     startTime,
+			})
+			Expect(err).To(BeNil())
+
+			state, _, err := tracker.JobState(jobid)
+			Expect(err).To(BeNil())
+			Expect(state).To(Equal(drmaa2interface.Queued))
+
+			err = tracker.Wait(jobid, time.Second*10, drmaa2interface.Done)
+			Expect(err).To(BeNil())
+			ji, err := tracker.JobInfo(jobid)
+			Expect(err).To(BeNil())
+			Expect(ji.DispatchTime).To(BeTemporally(">=", startTime))
+		})
+
+		It("should cancel a job waiting for its start time", func() {
+			jobid, err := tracker.AddJob(drmaa2interface.JobTemplate{
+				RemoteCommand: "sleep",
+				Args:          []string{"0"},
+				StartTime:     time.Now().Add(time.Hour),
+			})
+			Expect(err).To(BeNil())
+
+			waitErr := make(chan error, 1)
+			go func() {
+				waitErr <- tracker.Wait(jobid, time.Second*10,
+					drmaa2interface.Done, drmaa2interface.Failed)
+			}()
+
+			err = tracker.JobControl(jobid, jobtracker.JobControlTerminate)
+			Expect(err).To(BeNil())
+			Eventually(waitErr).Should(Receive(BeNil()))
+
+			ji, err := tracker.JobInfo(jobid)
+			Expect(err).To(BeNil())
+			Expect(ji.State).To(Equal(drmaa2interface.Failed))
+			Expect(ji.SubState).To(Equal(SubStateCancelledBeforeStart))
+			Expect(store.GetDelayedJobIDs()).To(BeEmpty())
+		})
+
+		It("should start delayed jobs after a restart", func() {
+			jobid, err := tracker.AddJob(drmaa2interface.JobTemplate{
+				RemoteCommand: "sleep",
+				Args:          []string{"0"},
+				StartTime:     time.Now().Add(time.Second),
+			})
+			Expect(err).To(BeNil())
+			Expect(tracker.Close()).To(BeNil())
+
+			store, err = NewPersistentJobStore(dbPath)
+			Expect(err).To(BeNil())
+			tracker, err = NewWithJobStore("testsession", store, true)
+			Expect(err).To(BeNil())
+
+			state, _, err := tracker.JobState(jobid)
+			Expect(err).To(BeNil())
+			Expect(state).To(Equal(drmaa2interface.Queued))
+
+			err = tracker.Wait(jobid, time.Second*10, drmaa2interface.Done)
+			Expect(err).To(BeNil())
+			Expect(store.GetDelayedJobIDs()).To(BeEmpty())
+		})
+
+		It("should start the tasks of a job array at its start time", func() {
+			startTime := time.Now().Add(time.Millisecond * 500)
+			arrayjobid, err := tracker.AddArrayJob(drmaa2interface.JobTemplate{
+				RemoteCommand: "sleep",
+				Args:          []string{"0"},
+				StartTime:     startTime,
+			}, 1, 2, 1, 1)
+			Expect(err).To(BeNil())
+			taskIDs, err := tracker.ListArrayJobs(arrayjobid)
+			Expect(err).To(BeNil())
+			for _, taskID := range taskIDs {
+				state, _, err := tracker.JobState(taskID)
+				Expect(err).To(BeNil())
+				Expect(state).To(Equal(drmaa2interface.Queued))
+			}
+			for _, taskID := range taskIDs {
+				err = tracker.Wait(taskID, time.Second*10, drmaa2interface.Done)
+				Expect(err).To(BeNil())
+				ji, err := tracker.JobInfo(taskID)
+				Expect(err).To(BeNil())
+				Expect(ji.DispatchTime).To(BeTemporally(">=", startTime))
+			}
+		})
+
+	})
+
+	Context("Hold and release", func() {
+
+		var tracker *JobTracker
+
+		BeforeEach(func() {
+			tracker = New("testsession")
+		})
+
+		It("should reject held jobs when the job store can't update the PID", func() {
+			// hides the optional interfaces of the job store
+			type basicJobStore struct{ JobStorer }
+			basicTracker, err := NewWithJobStore("testsession",
+				basicJobStore{NewJobStore()}, false)
+			Expect(err).To(BeNil())
+			_, err = basicTracker.AddJob(drmaa2interface.JobTemplate{
+				RemoteCommand: "sleep",
+				Args:          []string{"0"},
+				SubmitAsHold:  true,
+			})
+			Expect(err).NotTo(BeNil())
+			jobid, err := basicTracker.AddJob(drmaa2interface.JobTemplate{
+				RemoteCommand: "sleep",
+				Args:          []string{"0"},
+			})
+			Expect(err).To(BeNil())
+			err = basicTracker.Wait(jobid, time.Second*10, drmaa2interface.Done)
+			Expect(err).To(BeNil())
+		})
+
+		It("should start a job submitted as hold when it is released", func() {
+			jobid, err := tracker.AddJob(drmaa2interface.JobTemplate{
+				RemoteCommand: "sleep",
+				Args:          []string{"0"},
+				SubmitAsHold:  true,
+			})
+			Expect(err).To(BeNil())
+			Consistently(func() drmaa2interface.JobState {
+				state, _, _ := tracker.JobState(jobid)
+				return state
+			}, time.Millisecond*200).Should(Equal(drmaa2interface.QueuedHeld))
+			template, err := tracker.JobTemplate(jobid)
+			Expect(err).To(BeNil())
+			Expect(template.SubmitAsHold).To(BeTrue())
+
+			Expect(tracker.JobControl(jobid, jobtracker.JobControlHold)).To(BeNil())
+			Expect(tracker.JobControl(jobid, jobtracker.JobControlRelease)).To(BeNil())
+			Expect(tracker.JobControl(jobid, jobtracker.JobControlRelease)).NotTo(BeNil())
+			err = tracker.Wait(jobid, time.Second*10, drmaa2interface.Done)
+			Expect(err).To(BeNil())
+		})
+
+		It("should hold a job waiting for its start time", func() {
+			jobid, err := tracker.AddJob(drmaa2interface.JobTemplate{
+				RemoteCommand: "sleep",
+				Args:          []string{"0"},
+				StartTime:     time.Now().Add(time.Millisecond * 300),
+			})
+			Expect(err).To(BeNil())
+			Expect(tracker.JobControl(jobid, jobtracker.JobControlHold)).To(BeNil())
+			Consistently(func() drmaa2interface.JobState {
+				state, _, _ := tracker.JobState(jobid)
+				return state
+			}, time.Millisecond*500).Should(Equal(drmaa2interface.QueuedHeld))
+
+			Expect(tracker.JobControl(jobid, jobtracker.JobControlRelease)).To(BeNil())
+			err = tracker.Wait(jobid, time.Second*10, drmaa2interface.Done)
+			Expect(err).To(BeNil())
+		})
+
+		It("should terminate a held job", func() {
+			jobid, err := tracker.AddJob(drmaa2interface.JobTemplate{
+				RemoteCommand: "sleep",
+				Args:          []string{"0"},
+				SubmitAsHold:  true,
+			})
+			Expect(err).To(BeNil())
+
+			waitErr := make(chan error, 1)
+			go func() {
+				waitErr <- tracker.Wait(jobid, time.Second*10,
+					drmaa2interface.Done, drmaa2interface.Failed)
+			}()
+			Expect(tracker.JobControl(jobid, jobtracker.JobControlTerminate)).To(BeNil())
+			Eventually(waitErr).Should(Receive(BeNil()))
+			Expect(tracker.JobControl(jobid, jobtracker.JobControlRelease)).NotTo(BeNil())
+		})
+
+		It("should hold and release queued tasks of an array job", func() {
+			arrayjobid, err := tracker.AddArrayJob(drmaa2interface.JobTemplate{
+				RemoteCommand: "sleep",
+				Args:          []string{"0.5"},
+			}, 1, 3, 1, 1)
+			Expect(err).To(BeNil())
+			task3 := arrayjobid + ".3"
+			Expect(tracker.JobControl(task3, jobtracker.JobControlHold)).To(BeNil())
+
+			for _, task := range []string{arrayjobid + ".1", arrayjobid + ".2"} {
+				err = tracker.Wait(task, time.Second*10, drmaa2interface.Done)
+				Expect(err).To(BeNil())
+			}
+			Consistently(func() drmaa2interface.JobState {
+				state, _, _ := tracker.JobState(task3)
+				return state
+			}, time.Millisecond*500).Should(Equal(drmaa2interface.QueuedHeld))
+
+			Expect(tracker.JobControl(task3, jobtracker.JobControlRelease)).To(BeNil())
+			err = tracker.Wait(task3, time.Second*10, drmaa2interface.Done)
+			Expect(err).To(BeNil())
+		})
+
+		It("should start the released tasks of an array job submitted as hold", func() {
+			arrayjobid, err := tracker.AddArrayJob(drmaa2interface.JobTemplate{
+				RemoteCommand: "sleep",
+				Args:          []string{"0"},
+				SubmitAsHold:  true,
+			}, 1, 2, 1, 0)
+			Expect(err).To(BeNil())
+			task1, task2 := arrayjobid+".1", arrayjobid+".2"
+
+			Expect(tracker.JobControl(task1, jobtracker.JobControlTerminate)).To(BeNil())
+			err = tracker.Wait(task1, time.Second*10, drmaa2interface.Failed)
+			Expect(err).To(BeNil())
+
+			Expect(tracker.JobControl(task2, jobtracker.JobControlRelease)).To(BeNil())
+			err = tracker.Wait(task2, time.Second*10, drmaa2interface.Done)
+			Expect(err).To(BeNil())
+		})
+
+		It("should start tasks released one after another while the first task is held", func() {
+			arrayjobid, err := tracker.AddArrayJob(drmaa2interface.JobTemplate{
+				RemoteCommand: "sleep",
+				Args:          []string{"0"},
+				SubmitAsHold:  true,
+			}, 1, 3, 1, 0)
+			Expect(err).To(BeNil())
+
+			for _, task := range []string{".2", ".3", ".1"} {
+				Expect(tracker.JobControl(arrayjobid+task, jobtracker.JobControlRelease)).To(BeNil())
+				err = tracker.Wait(arrayjobid+task, time.Second*10, drmaa2interface.Done)
+				Expect(err).To(BeNil())
+			}
+		})
+
+	})
+
+	Context("Local scheduler", func() {
+
+		var tracker *JobTracker
+
+		jobState := func(jobid string) func() drmaa2interface.JobState {
+			return func() drmaa2interface.JobState {
+				state, _, _ := tracker.JobState(jobid)
+				return state
+			}
+		}
+
+		sleepJob := func(seconds string, priority, slots int64) drmaa2interface.JobTemplate {
+			return drmaa2interface.JobTemplate{
+				RemoteCommand: "sleep",
+				Args:          []string{seconds},
+				Priority:      priority,
+				MinSlots:      slots,
+			}
+		}
+
+		BeforeEach(func() {
+			tracker = EnableLocalScheduler(New("testsession"), 2)
+		})
+
+		It("should queue jobs until enough slots are free", func() {
+			first, err := tracker.AddJob(sleepJob("1", 0, 2))
+			Expect(err).To(BeNil())
+			Expect(tracker.Wait(first, time.Second*10,
+				drmaa2interface.Running)).To(BeNil())
+
+			second, err := tracker.AddJob(sleepJob("0", 0, 1))
+			Expect(err).To(BeNil())
+			Consistently(jobState(second), time.Millisecond*300).Should(
+				Equal(drmaa2interface.Queued))
+
+			Expect(tracker.Wait(second, time.Second*10,
+				drmaa2interface.Done)).To(BeNil())
+			firstInfo, err := tracker.JobInfo(first)
+			Expect(err).To(BeNil())
+			secondInfo, err := tracker.JobInfo(second)
+			Expect(err).To(BeNil())
+			Expect(secondInfo.DispatchTime).To(BeTemporally(">=",
+				firstInfo.FinishTime))
+		})
+
+		It("should start queued jobs by priority and submission time", func() {
+			first, err := tracker.AddJob(sleepJob("0.5", 0, 2))
+			Expect(err).To(BeNil())
+			low, err := tracker.AddJob(sleepJob("0.5", 0, 2))
+			Expect(err).To(BeNil())
+			high, err := tracker.AddJob(sleepJob("0.5", 10, 2))
+			Expect(err).To(BeNil())
+			later, err := tracker.AddJob(sleepJob("0.5", 10, 2))
+			Expect(err).To(BeNil())
+
+			Expect(tracker.Wait(first, time.Second*10,
+				drmaa2interface.Done)).To(BeNil())
+			Expect(tracker.Wait(high, time.Second*10,
+				drmaa2interface.Running)).To(BeNil())
+			Expect(jobState(low)()).To(Equal(drmaa2interface.Queued))
+			Expect(jobState(later)()).To(Equal(drmaa2interface.Queued))
+
+			Expect(tracker.Wait(later, time.Second*10,
+				drmaa2interface.Running)).To(BeNil())
+			Expect(jobState(low)()).To(Equal(drmaa2interface.Queued))
+			Expect(tracker.Wait(low, time.Second*10,
+				drmaa2interface.Done)).To(BeNil())
+		})
+
+		It("should not let smaller jobs overtake a queued job", func() {
+			first, err := tracker.AddJob(sleepJob("0.5", 0, 1))
+			Expect(err).To(BeNil())
+			large, err := tracker.AddJob(sleepJob("0", 0, 2))
+			Expect(err).To(BeNil())
+			small, err := tracker.AddJob(sleepJob("0", 0, 1))
+			Expect(err).To(BeNil())
+			Expect(tracker.Wait(first, time.Second*10,
+				drmaa2interface.Running)).To(BeNil())
+			Expect(jobState(large)()).To(Equal(drmaa2interface.Queued))
+			Expect(jobState(small)()).To(Equal(drmaa2interface.Queued))
+
+			Expect(tracker.Wait(small, time.Second*10,
+				drmaa2interface.Done)).To(BeNil())
+			largeInfo, err := tracker.JobInfo(large)
+			Expect(err).To(BeNil())
+			smallInfo, err := tracker.JobInfo(small)
+			Expect(err).To(BeNil())
+			Expect(smallInfo.DispatchTime).To(BeTemporally(">=",
+				largeInfo.DispatchTime))
+		})
+
+		It("should reject jobs requiring more slots than available", func() {
+			_, err := tracker.AddJob(sleepJob("0", 0, 3))
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should limit the running tasks of array jobs", func() {
+			arrayjobid, err := tracker.AddArrayJob(sleepJob("0.3", 0, 1), 1, 6, 1, 0)
+			Expect(err).To(BeNil())
+			tasks, err := tracker.ListArrayJobs(arrayjobid)
+			Expect(err).To(BeNil())
+			running := func() int {
+				count := 0
+				for _, task := range tasks {
+					if jobState(task)() == drmaa2interface.Running {
+						count++
+					}
+				}
+				return count
+			}
+			Consistently(running, time.Millisecond*500).Should(
+				BeNumerically("<=", 2))
+			for _, task := range tasks {
+				Expect(tracker.Wait(task, time.Second*10,
+					drmaa2interface.Done)).To(BeNil())
+			}
+		})
+
+		It("should hold, release, and terminate queued jobs", func() {
+			first, err := tracker.AddJob(sleepJob("0.5", 0, 2))
+			Expect(err).To(BeNil())
+			held, err := tracker.AddJob(sleepJob("0", 0, 1))
+			Expect(err).To(BeNil())
+			terminated, err := tracker.AddJob(sleepJob("0", 0, 1))
+			Expect(err).To(BeNil())
+
+			Expect(tracker.JobControl(held, jobtracker.JobControlHold)).To(BeNil())
+			Expect(tracker.JobControl(terminated,
+				jobtracker.JobControlTerminate)).To(BeNil())
+			Expect(tracker.Wait(first, time.Second*10,
+				drmaa2interface.Done)).To(BeNil())
+			Consistently(jobState(held), time.Millisecond*300).Should(
+				Equal(drmaa2interface.QueuedHeld))
+			Expect(jobState(terminated)()).To(Equal(drmaa2interface.Failed))
+
+			Expect(tracker.JobControl(held, jobtracker.JobControlRelease)).To(BeNil())
+			Expect(tracker.Wait(held, time.Second*10,
+				drmaa2interface.Done)).To(BeNil())
+		})
+
+		It("should restore the queued and running jobs after a restart", func() {
+			dbPath := filepath.Join(GinkgoT().TempDir(), "scheduler.db")
+			store, err := NewPersistentJobStore(dbPath)
+			Expect(err).To(BeNil())
+			persistentTracker, err := NewWithJobStore("testsession", store, true)
+			Expect(err).To(BeNil())
+			tracker = EnableLocalScheduler(persistentTracker, 1)
+
+			running, err := tracker.AddJob(sleepJob("1", 0, 1))
+			Expect(err).To(BeNil())
+			Expect(tracker.Wait(running, time.Second*10,
+				drmaa2interface.Running)).To(BeNil())
+			queued, err := tracker.AddJob(sleepJob("0", 0, 1))
+			Expect(err).To(BeNil())
+			heldJob := sleepJob("0", 0, 1)
+			heldJob.SubmitAsHold = true
+			held, err := tracker.AddJob(heldJob)
+			Expect(err).To(BeNil())
+			Expect(tracker.Close()).To(BeNil())
+
+			store, err = NewPersistentJobStore(dbPath)
+			Expect(err).To(BeNil())
+			persistentTracker, err = NewWithJobStore("testsession", store, true)
+			Expect(err).To(BeNil())
+			tracker = EnableLocalScheduler(persistentTracker, 1)
+			defer tracker.Close()
+
+			// the running job keeps its slot
+			Consistently(jobState(queued), time.Millisecond*300).Should(
+				Equal(drmaa2interface.Queued))
+			Expect(tracker.Wait(running, time.Second*10,
+				drmaa2interface.Done, drmaa2interface.Failed)).To(BeNil())
+			Expect(tracker.Wait(queued, time.Second*10,
+				drmaa2interface.Done)).To(BeNil())
+
+			// released jobs wait for free slots
+			blocking, err := tracker.AddJob(sleepJob("0.5", 0, 1))
+			Expect(err).To(BeNil())
+			Expect(tracker.Wait(blocking, time.Second*10,
+				drmaa2interface.Running)).To(BeNil())
+			Expect(tracker.JobControl(held, jobtracker.JobControlRelease)).To(BeNil())
+			Expect(jobState(held)()).To(Equal(drmaa2interface.Queued))
+			Expect(tracker.Wait(held, time.Second*10,
+				drmaa2interface.Done)).To(BeNil())
+			blockingInfo, err := tracker.JobInfo(blocking)
+			Expect(err).To(BeNil())
+			heldInfo, err := tracker.JobInfo(held)
+			Expect(err).To(BeNil())
+			Expect(heldInfo.DispatchTime).To(BeTemporally(">=",
+				blockingInfo.FinishTime))
+		})
+
+	})
+
 })
@@ -0,0 +1,254 @@
+package simpletracker
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dgruber/drmaa2interface"
+	"github.com/dgruber/drmaa2os/pkg/extension"
+)
+
+// stagedFile is a file or directory which is copied from src to dst
+// before or after the job runs.
+type stagedFile struct {
+	src string
+	dst string
+}
+
+// stagingPlan contains the files which are staged in before the job
+// is started and staged out after the job is finished.
+type stagingPlan struct {
+	in       []stagedFile
+	out      []stagedFile
+	hardLink bool
+}
+
+// newStagingPlan resolves the StageInFiles and StageOutFiles of the job
+// template. The keys of both maps are paths outside of the job, relative
+// paths are relative to the working directory of the application. The
+// values are paths relative to the working directory of the job. If a
+// value is empty the base name of the key is used. $TASK_ID is replaced
+// by the task ID when isTask is set for tasks of array jobs.
+func newStagingPlan(t drmaa2interface.JobTemplate, task int, isTask bool) (stagingPlan, error) {
+	plan := stagingPlan{
+		hardLink: strings.ToLower(t.ExtensionList[extension.JobTemplateStageInHardLink]) == "true",
+	}
+	var err error
+	plan.in, err = resolveStagedFiles(t.StageInFiles, t.WorkingDirectory, task, isTask, false)
+	if err != nil {
+		return plan, fmt.Errorf("invalid StageInFiles: %v", err)
+	}
+	plan.out, err = resolveStagedFiles(t.StageOutFiles, t.WorkingDirectory, task, isTask, true)
+	if err != nil {
+		return plan, fmt.Errorf("invalid StageOutFiles: %v", err)
+	}
+	return plan, nil
+}
+
+func resolveStagedFiles(files map[string]string, workingDir string, task int, isTask, out bool) ([]stagedFile, error) {
+	staged := make([]stagedFile, 0, len(files))
+	for outside, inside := range files {
+		outsidePath, err := stagingPath(outside, task, isTask)
+		if err != nil {
+			return nil, err
+		}
+		if inside == "" {
+			inside = filepath.Base(outsidePath)
+		}
+		insidePath, err := stagingPath(inside, task, isTask)
+		if err != nil {
+			return nil, err
+		}
+		if !filepath.IsAbs(insidePath) {
+			insidePath = filepath.Join(workingDir, insidePath)
+		}
+		if out {
+			staged = append(staged, stagedFile{src: insidePath, dst: outsidePath})
+		} else {
+			staged = append(staged, stagedFile{src: outsidePath, dst: insidePath})
+		}
+	}
+	// stage files in a defined order
+	sort.Slice(staged, func(i, j int) bool {
+		return staged[i].dst < staged[j].dst
+	})
+	return staged, nil
+}
+
+// jobStagingPlan returns the staging plan of a job or task of an array
+// job which was started before. Must be called while holding the lock.
+func (jt *JobTracker) jobStagingPlan(jobid string) stagingPlan {
+	templateID, task := jobid, 0
+	arrayjobid, isTask := arrayJobOfTask(jobid)
+	if isTask {
+		templateID = arrayjobid
+		task, _ = strconv.Atoi(strings.TrimPrefix(jobid, arrayjobid+"."))
+	}
+	t, err := jt.js.GetJobTemplate(templateID)
+	if err != nil {
+		return stagingPlan{}
+	}
+	// errors were reported when the job was started
+	plan, _ := newStagingPlan(t, task, isTask)
+	return plan
+}
+
+// stagingPath converts a local path or a file:// URL into a path
+// and replaces $TASK_ID by the task ID for tasks of array jobs.
+func stagingPath(path string, task int, isTask bool) (string, error) {
+	if strings.HasPrefix(path, "file://") {
+		path = strings.TrimPrefix(path, "file://")
+	} else if strings.Contains(path, "://") {
+		return "", fmt.Errorf("unsupported URL %s (only file:// is supported)", path)
+	}
+	if path == "" {
+		return "", fmt.Errorf("empty path")
+	}
+	if isTask {
+		taskID := strconv.Itoa(task)
+		path = strings.ReplaceAll(path, "${TASK_ID}", taskID)
+		path = strings.ReplaceAll(path, "$TASK_ID", taskID)
+	}
+	return filepath.Clean(path), nil
+}
+
+// stageIn copies (or hard-links) the files and directories into the
+// working directory of the job.
+func (p stagingPlan) stageIn() error {
+	for _, file := range p.in {
+		if err := stageFile(file.src, file.dst, p.hardLink); err != nil {
+			return fmt.Errorf("failed to stage in %s: %v", file.src, err)
+		}
+	}
+	return nil
+}
+
+// removeStagedIn removes the files which were staged in when the job
+// could not be started. Directories are removed only when they are
+// empty afterwards so that files of the working directory which were
+// not staged in are kept.
+func (p stagingPlan) removeStagedIn() {
+	for _, file := range p.in {
+		info, err := os.Lstat(file.src)
+		if err != nil {
+			continue
+		}
+		if !info.IsDir() {
+			os.Remove(file.dst)
+			continue
+		}
+		var dirs []string
+		filepath.WalkDir(file.src, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return nil
+			}
+			rel, err := filepath.Rel(file.src, path)
+			if err != nil {
+				return nil
+			}
+			target := filepath.Join(file.dst, rel)
+			if d.IsDir() {
+				dirs = append(dirs, target)
+			} else {
+				os.Remove(target)
+			}
+			return nil
+		})
+		// remove subdirectories before their parents
+		for i := len(dirs) - 1; i >= 0; i-- {
+			os.Remove(dirs[i])
+		}
+	}
+}
+
+// stageOut copies the results of the job back. The job is set into
+// failed state when a file can't be staged out.
+func (p stagingPlan) stageOut(ji drmaa2interface.JobInfo) drmaa2interface.JobInfo {
+	for _, file := range p.out {
+		if err := stageFile(file.src, file.dst, false); err != nil {
+			ji.State = drmaa2interface.Failed
+			if ji.SubState == "" {
+				ji.SubState = fmt.Sprintf("failed to stage out %s: %v", file.src, err)
+			}
+		}
+	}
+	return ji
+}
+
+// stageFile copies a file or a directory recursively. Files are
+// hard-linked when requested and when it is possible.
+func stageFile(src, dst string, hardLink bool) error {
+	info, err := os.Lstat(src)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+			return err
+		}
+		return copyFile(src, dst, info, hardLink)
+	}
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return os.MkdirAll(target, info.Mode().Perm()|0700)
+		}
+		return copyFile(path, target, info, hardLink)
+	})
+}
+
+// copyFile copies a regular file or recreates a symlink.
+func copyFile(src, dst string, info fs.FileInfo, hardLink bool) error {
+	// replace existing files instead of writing into them as they
+	// might be hard-links
+	if err := os.Remove(dst); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if info.Mode()&os.ModeSymlink != 0 {
+		link, err := os.Readlink(src)
+		if err != nil {
+			return err
+		}
+		return os.Symlink(link, dst)
+	}
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("%s is not a regular file", src)
+	}
+	if hardLink {
+		if err := os.Link(src, dst); err == nil {
+			return nil
+		}
+		// fall back to copying (like for different file systems)
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
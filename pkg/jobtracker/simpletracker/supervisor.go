@@ -0,0 +1,146 @@
+package simpletracker
+
+import (
+	"fmt"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/dgruber/drmaa2interface"
+	"github.com/dgruber/drmaa2os/pkg/extension"
+	"github.com/dgruber/drmaa2os/pkg/helper"
+)
+
+// terminationGracePeriod is the time a job has to finish after it got
+// SIGTERM because it exceeded its wallclock limit or deadline. Afterwards
+// the process group of the job is killed with SIGKILL.
+var terminationGracePeriod = 10 * time.Second
+
+const (
+	// SubStateWallclockExceeded is the SubState of a job which was
+	// terminated as it exceeded its wallclock time limit.
+	SubStateWallclockExceeded = "wallclock limit exceeded"
+	// SubStateDeadlineExceeded is the SubState of a job which was
+	// terminated as it was still running at its deadline.
+	SubStateDeadlineExceeded = "deadline exceeded"
+)
+
+// runtimeLimit is the point in time a job gets terminated. The zero
+// value means that the job can run forever.
+type runtimeLimit struct {
+	end    time.Time
+	reason string
+}
+
+// newRuntimeLimit derives the end of the runtime of a job started at
+// the given time from the wallclock time limit and the deadline of the
+// job template. The earlier one wins.
+func newRuntimeLimit(t drmaa2interface.JobTemplate, startTime time.Time) (runtimeLimit, error) {
+	var limit runtimeLimit
+	if value, exists := t.ResourceLimits[extension.ResourceLimitWallclockTime]; exists {
+		wallclock, err := helper.ParseDurationLimit(value)
+		if err != nil {
+			return limit, fmt.Errorf("invalid resource limit %s: %v",
+				extension.ResourceLimitWallclockTime, err)
+		}
+		if wallclock > 0 {
+			limit.end = startTime.Add(wallclock)
+			limit.reason = SubStateWallclockExceeded
+		}
+	}
+	if !t.DeadlineTime.IsZero() && (limit.end.IsZero() || t.DeadlineTime.Before(limit.end)) {
+		limit.end = t.DeadlineTime
+		limit.reason = SubStateDeadlineExceeded
+	}
+	return limit, nil
+}
+
+// runtimeSupervisor terminates the process group of a job when it
+// exceeds its runtime limit.
+type runtimeSupervisor struct {
+	sync.Mutex
+	limit   runtimeLimit
+	timer   *time.Timer
+	signal  syscall.Signal
+	stopped bool
+}
+
+// superviseRuntime starts supervising the process group of the given
+// process. stop() needs to be called when the process is finished.
+func superviseRuntime(pid int, limit runtimeLimit) *runtimeSupervisor {
+	s := &runtimeSupervisor{limit: limit}
+	if limit.end.IsZero() {
+		return s
+	}
+	s.Lock()
+	defer s.Unlock()
+	s.timer = time.AfterFunc(time.Until(limit.end), func() {
+		s.terminate(pid)
+	})
+	return s
+}
+
+// terminate sends SIGTERM to the process group and SIGKILL after the
+// grace period. SIGKILL is sent even when the process itself finished
+// in time as other processes of the group might ignore SIGTERM.
+func (s *runtimeSupervisor) terminate(pid int) {
+	s.Lock()
+	defer s.Unlock()
+	if s.stopped {
+		return
+	}
+	s.signal = syscall.SIGTERM
+	syscall.Kill(-pid, syscall.SIGTERM)
+	// a suspended job can't handle SIGTERM
+	syscall.Kill(-pid, syscall.SIGCONT)
+	s.timer = time.AfterFunc(terminationGracePeriod, func() {
+		s.Lock()
+		if !s.stopped {
+			s.signal = syscall.SIGKILL
+		}
+		s.Unlock()
+		KillPid(pid)
+	})
+}
+
+// stop ends the supervision and returns the signal sent to the job
+// when it exceeded its runtime limit, otherwise 0. A pending SIGKILL
+// of a terminated job is not cancelled.
+func (s *runtimeSupervisor) stop() syscall.Signal {
+	s.Lock()
+	defer s.Unlock()
+	s.stopped = true
+	if s.timer != nil && s.signal == 0 {
+		s.timer.Stop()
+	}
+	return s.signal
+}
+
+// jobRuntimeLimit returns the runtime limit of a job or a task of an
+// array job which was started at the given time.
+func (jt *JobTracker) jobRuntimeLimit(jobid string, startTime time.Time) runtimeLimit {
+	templateID := jobid
+	if arrayjobid, isTask := arrayJobOfTask(jobid); isTask {
+		templateID = arrayjobid
+	}
+	t, err := jt.js.GetJobTemplate(templateID)
+	if err != nil {
+		return runtimeLimit{}
+	}
+	// errors were reported when the job was started
+	limit, _ := newRuntimeLimit(t, startTime)
+	return limit
+}
+
+// applyRuntimeLimit marks the job as failed when it was terminated as
+// it exceeded its runtime limit.
+func (s *runtimeSupervisor) applyRuntimeLimit(ji drmaa2interface.JobInfo, signal syscall.Signal) drmaa2interface.JobInfo {
+	if signal == 0 {
+		return ji
+	}
+	ji.State = drmaa2interface.Failed
+	ji.SubState = s.limit.reason
+	// the job might have handled SIGTERM and exited on its own
+	ji.TerminatingSignal = signal.String()
+	return ji
+}
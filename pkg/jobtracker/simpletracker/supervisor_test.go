@@ -0,0 +1,185 @@
+package simpletracker
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/dgruber/drmaa2interface"
+	"github.com/dgruber/drmaa2os/pkg/extension"
+)
+
+var _ = Describe("Runtime supervisor", func() {
+
+	Context("runtime limit of the job template", func() {
+
+		start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+		It("should not limit jobs without wallclock time and deadline", func() {
+			limit, err := newRuntimeLimit(drmaa2interface.JobTemplate{}, start)
+			Ω(err).Should(BeNil())
+			Ω(limit.end.IsZero()).Should(BeTrue())
+		})
+
+		It("should take the wallclock time limit", func() {
+			limit, err := newRuntimeLimit(drmaa2interface.JobTemplate{
+				ResourceLimits: map[string]string{
+					extension.ResourceLimitWallclockTime: "1h",
+				},
+			}, start)
+			Ω(err).Should(BeNil())
+			Ω(limit.end).Should(Equal(start.Add(time.Hour)))
+			Ω(limit.reason).Should(Equal(SubStateWallclockExceeded))
+		})
+
+		It("should take the earlier of wallclock time limit and deadline", func() {
+			limit, err := newRuntimeLimit(drmaa2interface.JobTemplate{
+				ResourceLimits: map[string]string{
+					extension.ResourceLimitWallclockTime: "3600",
+				},
+				DeadlineTime: start.Add(time.Minute),
+			}, start)
+			Ω(err).Should(BeNil())
+			Ω(limit.end).Should(Equal(start.Add(time.Minute)))
+			Ω(limit.reason).Should(Equal(SubStateDeadlineExceeded))
+		})
+
+		It("should reject an invalid wallclock time limit", func() {
+			_, err := newRuntimeLimit(drmaa2interface.JobTemplate{
+				ResourceLimits: map[string]string{
+					extension.ResourceLimitWallclockTime: "forever",
+				},
+			}, start)
+			Ω(err).ShouldNot(BeNil())
+		})
+
+	})
+
+	Context("termination of jobs", func() {
+
+		var events chan JobEvent
+
+		BeforeEach(func() {
+			events = make(chan JobEvent, 10)
+		})
+
+		waitForFinishedJob := func() drmaa2interface.JobInfo {
+			for {
+				select {
+				case event := <-events:
+					if event.JobState == drmaa2interface.Done ||
+						event.JobState == drmaa2interface.Failed {
+						return event.JobInfo
+					}
+				case <-time.After(time.Second * 10):
+					Fail("job did not finish")
+				}
+			}
+		}
+
+		It("should terminate a job which exceeds the wallclock time limit", func() {
+			_, err := startProcess("1", 0, drmaa2interface.JobTemplate{
+				RemoteCommand: "/bin/sleep",
+				Args:          []string{"60"},
+				ResourceLimits: map[string]string{
+					extension.ResourceLimitWallclockTime: "100ms",
+				},
+			}, events, "")
+			Ω(err).Should(BeNil())
+			ji := waitForFinishedJob()
+			Ω(ji.State).Should(Equal(drmaa2interface.Failed))
+			Ω(ji.SubState).Should(Equal(SubStateWallclockExceeded))
+			Ω(ji.TerminatingSignal).Should(Equal("terminated"))
+		})
+
+		It("should kill a job which ignores SIGTERM after the grace period", func() {
+			gracePeriod := terminationGracePeriod
+			terminationGracePeriod = time.Millisecond * 200
+			defer func() { terminationGracePeriod = gracePeriod }()
+
+			_, err := startProcess("1", 0, drmaa2interface.JobTemplate{
+				RemoteCommand: "/bin/sh",
+				Args:          []string{"-c", `trap "" TERM; sleep 60 & wait; wait`},
+				DeadlineTime:  time.Now().Add(time.Millisecond * 100),
+			}, events, "")
+			Ω(err).Should(BeNil())
+			ji := waitForFinishedJob()
+			Ω(ji.State).Should(Equal(drmaa2interface.Failed))
+			Ω(ji.SubState).Should(Equal(SubStateDeadlineExceeded))
+			Ω(ji.TerminatingSignal).Should(Equal("killed"))
+		})
+
+		It("should kill processes of the job which ignore SIGTERM when the job finished", func() {
+			gracePeriod := terminationGracePeriod
+			terminationGracePeriod = time.Millisecond * 200
+			defer func() { terminationGracePeriod = gracePeriod }()
+
+			pidFile := filepath.Join(GinkgoT().TempDir(), "pid")
+			_, err := startProcess("1", 0, drmaa2interface.JobTemplate{
+				RemoteCommand: "/bin/sh",
+				Args: []string{"-c",
+					`(trap "" TERM; exec sleep 60) </dev/null >/dev/null 2>&1 & echo $! > ` + pidFile + `; wait`},
+				DeadlineTime: time.Now().Add(time.Millisecond * 300),
+			}, events, "")
+			Ω(err).Should(BeNil())
+			ji := waitForFinishedJob()
+			Ω(ji.State).Should(Equal(drmaa2interface.Failed))
+			Ω(ji.SubState).Should(Equal(SubStateDeadlineExceeded))
+
+			content, err := os.ReadFile(pidFile)
+			Ω(err).Should(BeNil())
+			pid := strings.TrimSpace(string(content))
+			// the orphaned process is gone or a zombie
+			Eventually(func() bool {
+				stat, err := os.ReadFile(filepath.Join("/proc", pid, "stat"))
+				if err != nil {
+					return true
+				}
+				fields := strings.Fields(string(stat[bytes.LastIndexByte(stat, ')')+1:]))
+				return len(fields) > 0 && fields[0] == "Z"
+			}, time.Second*5).Should(BeTrue())
+		})
+
+		It("should reapply the runtime limit of restored jobs", func() {
+			tracker := New("supervisortest")
+			start := time.Now().Add(-time.Minute)
+			tracker.js.SaveJob("1", drmaa2interface.JobTemplate{
+				ResourceLimits: map[string]string{
+					extension.ResourceLimitWallclockTime: "1h",
+				},
+			}, 0)
+			limit := tracker.jobRuntimeLimit("1", start)
+			Ω(limit.end).Should(Equal(start.Add(time.Hour)))
+			Ω(limit.reason).Should(Equal(SubStateWallclockExceeded))
+
+			tracker.js.SaveArrayJob("2", []int{0, 0}, drmaa2interface.JobTemplate{
+				DeadlineTime: start.Add(time.Minute),
+			}, 1, 2, 1)
+			limit = tracker.jobRuntimeLimit("2.2", start)
+			Ω(limit.end).Should(Equal(start.Add(time.Minute)))
+			Ω(limit.reason).Should(Equal(SubStateDeadlineExceeded))
+
+			Ω(tracker.jobRuntimeLimit("3", start).end.IsZero()).Should(BeTrue())
+		})
+
+		It("should not touch jobs which finish in time", func() {
+			_, err := startProcess("1", 0, drmaa2interface.JobTemplate{
+				RemoteCommand: "/bin/true",
+				ResourceLimits: map[string]string{
+					extension.ResourceLimitWallclockTime: "1m",
+				},
+			}, events, "")
+			Ω(err).Should(BeNil())
+			ji := waitForFinishedJob()
+			Ω(ji.State).Should(Equal(drmaa2interface.Done))
+			Ω(ji.SubState).Should(BeEmpty())
+		})
+
+	})
+
+})
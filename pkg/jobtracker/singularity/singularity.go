@@ -3,6 +3,7 @@ package singularity
 import (
 	"fmt"
 	"os/exec"
+	"strings"
 	"time"
 
 	"github.com/dgruber/drmaa2interface"
@@ -27,6 +28,43 @@ func (a *allocator) New(jobSessionName string, jobTrackerInitParams interface{})
 	return New(jobSessionName)
 }
 
+// DrmsName returns "singularity".
+func (a *allocator) DrmsName() string {
+	return "singularity"
+}
+
+// DrmsVersion returns the version of the installed singularity
+// command line tool.
+func (a *allocator) DrmsVersion() (drmaa2interface.Version, error) {
+	// singularity-ce version 3.11.4
+	out, err := exec.Command("singularity", "--version").Output()
+	if err != nil {
+		return drmaa2interface.Version{}, fmt.Errorf("singularity --version failed: %v", err)
+	}
+	fields := strings.Fields(string(out))
+	if len(fields) == 0 {
+		return drmaa2interface.Version{}, fmt.Errorf("unexpected version output: %s", string(out))
+	}
+	major, minor, _ := strings.Cut(fields[len(fields)-1], ".")
+	return drmaa2interface.Version{Major: major, Minor: minor}, nil
+}
+
+// Supports returns true for job arrays with a maxParallel limit as
+// containers are started as processes.
+func (a *allocator) Supports(capability drmaa2interface.Capability) bool {
+	return capability == drmaa2interface.BulkJobsMaxParallel
+}
+
+// SupportsJobControl returns true for suspend, resume, and terminate.
+func (a *allocator) SupportsJobControl(action string) bool {
+	switch action {
+	case jobtracker.JobControlSuspend, jobtracker.JobControlResume,
+		jobtracker.JobControlTerminate:
+		return true
+	}
+	return false
+}
+
 // Tracker tracks singularity container.
 type Tracker struct {
 	processTracker  *simpletracker.JobTracker
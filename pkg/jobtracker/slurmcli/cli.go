@@ -2,8 +2,10 @@ package slurmcli
 
 import (
 	"fmt"
-	"github.com/dgruber/drmaa2interface"
 	"os/exec"
+	"strings"
+
+	"github.com/dgruber/drmaa2interface"
 )
 
 // Slurm is a wrapper for the slurm CLI tools.
@@ -13,6 +15,7 @@ type Slurm struct {
 	control         string
 	cancel          string
 	acct            string
+	info            string
 	suspendBySignal bool
 }
 
@@ -25,19 +28,106 @@ func NewSlurm(sbatch, squeue, scontrol, scancel, sacct string, suspendBySignal b
 		control:         scontrol,
 		cancel:          scancel,
 		acct:            sacct,
+		info:            "sinfo",
 		suspendBySignal: suspendBySignal,
 	}
 }
 
+// WithSinfo sets the sinfo command line tool which is used for
+// monitoring sessions (default is "sinfo").
+func (s *Slurm) WithSinfo(sinfo string) *Slurm {
+	s.info = sinfo
+	return s
+}
+
 // ListJobs returns all jobs for a given account in a given state.
+// Job array tasks are listed as separate jobs.
 func (s *Slurm) ListJobs(account, states string) ([]string, error) {
-	out, err := run(s.queue, "-h", "-A", account, "--states="+states)
+	out, err := run(s.queue, "-h", "-r", "-A", account, "-o", "%i",
+		"--states="+states)
+	if err != nil {
+		return nil, err
+	}
+	return parsesqueue(out)
+}
+
+// ListArrayJobs returns the job IDs of all tasks of a job array
+// (like 18_1, 18_2). Tasks which are not yet started are included.
+func (s *Slurm) ListArrayJobs(account, arrayjobid string) ([]string, error) {
+	infos, err := s.jobInfos(account, arrayjobid)
+	if err != nil {
+		return nil, err
+	}
+	jobIDs := make([]string, 0, len(infos))
+	for _, info := range infos {
+		jobIDs = append(jobIDs, info.ID)
+	}
+	return expandArrayJobIDs(jobIDs), nil
+}
+
+// JobInfo returns the accounting information of a job.
+func (s *Slurm) JobInfo(account, jobid string) (drmaa2interface.JobInfo, error) {
+	infos, err := s.jobInfos(account, jobid)
+	if err != nil {
+		return drmaa2interface.JobInfo{}, err
+	}
+	for _, info := range infos {
+		if info.ID == jobid {
+			return info, nil
+		}
+	}
+	return drmaa2interface.JobInfo{}, fmt.Errorf("job %s not found", jobid)
+}
+
+// ListAllJobs returns the jobs of all users and accounts which are
+// known to squeue.
+func (s *Slurm) ListAllJobs() ([]string, error) {
+	out, err := run(s.queue, "-h", "-r", "-a", "-o", "%i", "--states=all")
 	if err != nil {
 		return nil, err
 	}
 	return parsesqueue(out)
 }
 
+// JobInfoOfAnyUser returns the accounting information of a job
+// independent of the account and user.
+func (s *Slurm) JobInfoOfAnyUser(jobid string) (drmaa2interface.JobInfo, error) {
+	return s.JobInfo("", jobid)
+}
+
+// Partitions returns the names of all partitions.
+func (s *Slurm) Partitions() ([]string, error) {
+	out, err := run(s.info, "-h", "-o", "%R")
+	if err != nil {
+		return nil, err
+	}
+	return parsesinfo(out)
+}
+
+// Nodes returns all compute nodes of the cluster.
+func (s *Slurm) Nodes() ([]drmaa2interface.Machine, error) {
+	out, err := run(s.control, "show", "nodes", "-o")
+	if err != nil {
+		return nil, err
+	}
+	return parseNodes(out)
+}
+
+// jobInfos queries sacct for the job. When the account is empty the
+// jobs of all users are taken into account.
+func (s *Slurm) jobInfos(account, jobid string) ([]drmaa2interface.JobInfo, error) {
+	// sacct -P -n -X -A default -j 25 -o JobID,...
+	args := []string{"-P", "-n", "-X", "-A", account}
+	if account == "" {
+		args = []string{"-P", "-n", "-X", "-a"}
+	}
+	out, err := run(s.acct, append(args, "-j", jobid, "-o", sacctFormat)...)
+	if err != nil {
+		return nil, err
+	}
+	return parsesacct(out)
+}
+
 // SubmitJob converts the job template into job submission options
 // and submits a job with sbatch.
 func (s *Slurm) SubmitJob(account string, jt drmaa2interface.JobTemplate) (string, error) {
@@ -95,6 +185,40 @@ func (s *Slurm) Resume(account, jobid string) error {
 	return err
 }
 
+// Version returns the version of slurm as reported by sbatch --version.
+func (s *Slurm) Version() (drmaa2interface.Version, error) {
+	out, err := run(s.batch, "--version")
+	if err != nil {
+		return drmaa2interface.Version{}, err
+	}
+	return parseVersion(out)
+}
+
+// Hold prevents a pending job (or all pending tasks of a job array)
+// from being started.
+func (s *Slurm) Hold(account, jobid string) error {
+	_, err := run(s.control, "hold", jobid)
+	return err
+}
+
+// Release allows a held job to be started.
+func (s *Slurm) Release(account, jobid string) error {
+	_, err := run(s.control, "release", jobid)
+	return err
+}
+
+// IsHeld returns true if the pending job is held (by the user
+// or an admin).
+func (s *Slurm) IsHeld(account, jobid string) bool {
+	// squeue -h -j 25 -o %r
+	// JobHeldUser
+	reason, err := run(s.queue, "-h", "-j", jobid, "-o", "%r")
+	if err != nil {
+		return false
+	}
+	return strings.HasPrefix(strings.TrimSpace(string(reason)), "JobHeld")
+}
+
 // Terminate stops a job from execution.
 func (s *Slurm) Terminate(account, jobid string) error {
 	_, err := run(s.cancel, "-A", account, jobid)
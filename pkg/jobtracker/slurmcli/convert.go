@@ -3,16 +3,109 @@ package slurmcli
 import (
 	"errors"
 	"fmt"
-	"github.com/dgruber/drmaa2interface"
+	"sort"
 	"strings"
+	"time"
+
+	"github.com/dgruber/drmaa2interface"
+	"github.com/dgruber/drmaa2os/pkg/extension"
+	"github.com/dgruber/drmaa2os/pkg/helper"
 )
 
-// conver into sbatch command line args
+// sbatchTimeLayout is the format of --begin and --deadline
+const sbatchTimeLayout = "2006-01-02T15:04:05"
+
+// convertJobTemplate converts the job template into sbatch command line
+// args. Job template fields which can't be expressed by sbatch options
+// are rejected.
 func convertJobTemplate(account string, jt drmaa2interface.JobTemplate) ([]string, error) {
 	args := []string{"--parsable", "-A", account}
 	if jt.RemoteCommand == "" {
 		return nil, errors.New("RemoteCommand is not set")
 	}
+	if err := checkUnsupportedFields(jt); err != nil {
+		return nil, err
+	}
+
+	if jt.JobName != "" {
+		args = append(args, "--job-name="+jt.JobName)
+	}
+	if jt.OutputPath != "" {
+		args = append(args, "--output="+jt.OutputPath)
+	}
+	// slurm writes stderr into the output file when --error is not set
+	if jt.ErrorPath != "" && !jt.JoinFiles {
+		args = append(args, "--error="+jt.ErrorPath)
+	}
+	if jt.InputPath != "" {
+		args = append(args, "--input="+jt.InputPath)
+	}
+	if jt.WorkingDirectory != "" {
+		args = append(args, "--chdir="+jt.WorkingDirectory)
+	}
+	if jt.QueueName != "" {
+		args = append(args, "--partition="+jt.QueueName)
+	}
+	if jt.ReservationID != "" {
+		args = append(args, "--reservation="+jt.ReservationID)
+	}
+	if jt.MinSlots > 0 {
+		args = append(args, fmt.Sprintf("--ntasks=%d", jt.MinSlots))
+	}
+	if jt.MinPhysMemory > 0 {
+		// MinPhysMemory is in KiB
+		args = append(args, fmt.Sprintf("--mem=%dK", jt.MinPhysMemory))
+	}
+	if len(jt.CandidateMachines) > 0 {
+		args = append(args, "--nodelist="+strings.Join(jt.CandidateMachines, ","))
+	}
+	if !jt.StartTime.IsZero() {
+		args = append(args, "--begin="+jt.StartTime.Local().Format(sbatchTimeLayout))
+	}
+	if !jt.DeadlineTime.IsZero() {
+		args = append(args, "--deadline="+jt.DeadlineTime.Local().Format(sbatchTimeLayout))
+	}
+	if jt.Priority > 0 {
+		// negative nice values can only be set by privileged users
+		return nil, fmt.Errorf("priority %d is not supported in slurm: only negative priorities (positive nice values) can be set", jt.Priority)
+	}
+	if jt.Priority < 0 {
+		// lower priority means higher nice value
+		args = append(args, fmt.Sprintf("--nice=%d", -jt.Priority))
+	}
+	if len(jt.Email) > 0 {
+		args = append(args, "--mail-user="+strings.Join(jt.Email, ","))
+		mailTypes := []string{}
+		if jt.EmailOnStarted {
+			mailTypes = append(mailTypes, "BEGIN")
+		}
+		if jt.EmailOnTerminated {
+			mailTypes = append(mailTypes, "END", "FAIL")
+		}
+		if len(mailTypes) > 0 {
+			args = append(args, "--mail-type="+strings.Join(mailTypes, ","))
+		}
+	}
+	if jt.ReRunnable {
+		args = append(args, "--requeue")
+	}
+	if jt.SubmitAsHold {
+		args = append(args, "--hold")
+	}
+	if len(jt.JobEnvironment) > 0 {
+		export, err := convertJobEnvironment(jt.JobEnvironment)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, export)
+	}
+	limits, err := convertResourceLimits(jt.ResourceLimits)
+	if err != nil {
+		return nil, err
+	}
+	args = append(args, limits...)
+	args = append(args, convertExtensions(jt.ExtensionList)...)
+
 	args = append(args, jt.RemoteCommand)
 	if jt.Args != nil && len(jt.Args) > 0 {
 		args = append(args, jt.Args...)
@@ -20,19 +113,129 @@ func convertJobTemplate(account string, jt drmaa2interface.JobTemplate) ([]strin
 	return args, nil
 }
 
+// checkUnsupportedFields returns an error for all job template fields
+// which have no sbatch representation.
+func checkUnsupportedFields(jt drmaa2interface.JobTemplate) error {
+	if jt.MaxSlots > 0 && jt.MaxSlots != jt.MinSlots {
+		return errors.New("MaxSlots different from MinSlots is not supported in slurm")
+	}
+	if jt.JobCategory != "" {
+		return errors.New("JobCategory is not supported in slurm")
+	}
+	if jt.AccountingID != "" {
+		return errors.New("AccountingID is not supported in slurm as the slurm account is the job session name")
+	}
+	if jt.MachineOs != "" {
+		return errors.New("MachineOs is not supported in slurm")
+	}
+	if jt.MachineArch != "" {
+		return errors.New("MachineArch is not supported in slurm")
+	}
+	if len(jt.StageInFiles) > 0 {
+		return errors.New("StageInFiles is not supported in slurm")
+	}
+	if len(jt.StageOutFiles) > 0 {
+		return errors.New("StageOutFiles is not supported in slurm")
+	}
+	if (jt.EmailOnStarted || jt.EmailOnTerminated) && len(jt.Email) == 0 {
+		return errors.New("EmailOnStarted and EmailOnTerminated require Email")
+	}
+	return nil
+}
+
+// convertJobEnvironment creates the --export option which forwards the
+// environment of the submitter together with the job environment.
+func convertJobEnvironment(env map[string]string) (string, error) {
+	keys := sortedKeys(env)
+	variables := make([]string, 0, len(keys)+1)
+	variables = append(variables, "ALL")
+	for _, key := range keys {
+		// --export is comma separated
+		if strings.Contains(env[key], ",") {
+			return "", fmt.Errorf("value of environment variable %s contains a comma which is not supported in slurm", key)
+		}
+		variables = append(variables, key+"="+env[key])
+	}
+	return "--export=" + strings.Join(variables, ","), nil
+}
+
+// convertResourceLimits converts WALLCLOCK_TIME into the --time option.
+// All other limits are rejected.
+func convertResourceLimits(limits map[string]string) ([]string, error) {
+	args := []string{}
+	for _, limit := range sortedKeys(limits) {
+		switch limit {
+		case extension.ResourceLimitWallclockTime:
+			duration, err := helper.ParseDurationLimit(limits[limit])
+			if err != nil {
+				return nil, fmt.Errorf("resource limit %s: %v", limit, err)
+			}
+			args = append(args, "--time="+formatSlurmTime(duration))
+		default:
+			return nil, fmt.Errorf("resource limit %s is not supported in slurm", limit)
+		}
+	}
+	return args, nil
+}
+
+// formatSlurmTime returns the duration in the slurm time format
+// days-hours:minutes:seconds. Slurm has a granularity of minutes
+// hence the duration is rounded up to full minutes.
+func formatSlurmTime(d time.Duration) string {
+	minutes := int64((d + time.Minute - 1) / time.Minute)
+	return fmt.Sprintf("%d-%02d:%02d:00", minutes/(24*60), (minutes/60)%24, minutes%60)
+}
+
+// convertExtensions passes the job template extensions with the slurm
+// option prefix as sbatch options. The key without the prefix is the
+// long option name (like "constraint" of "slurm.constraint"), an empty
+// value creates an option without argument (like --exclusive). The
+// extensions of other backends are ignored.
+func convertExtensions(extensions map[string]string) []string {
+	args := []string{}
+	for _, key := range sortedKeys(extensions) {
+		if !strings.HasPrefix(key, extension.JobTemplateSlurmOptionPrefix) {
+			continue
+		}
+		option := "--" + strings.TrimLeft(strings.TrimPrefix(key, extension.JobTemplateSlurmOptionPrefix), "-")
+		if extensions[key] != "" {
+			option += "=" + extensions[key]
+		}
+		args = append(args, option)
+	}
+	return args
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 func convertArrayJobArgs(start, end, step, maxParallel int) ([]string, error) {
-	if maxParallel != 0 {
-		return nil, fmt.Errorf("job array throttling is not supported in slurm")
+	if step <= 0 {
+		return nil, fmt.Errorf("step must be greater than 0")
+	}
+	if maxParallel < 0 {
+		return nil, fmt.Errorf("maxParallel must not be negative")
 	}
 	arg := fmt.Sprintf("--array=%d-%d", start, end)
 	if step != 1 {
 		arg = fmt.Sprintf("%s:%d", arg, step)
 	}
+	// job array throttling (like --array=1-10%4)
+	if maxParallel > 0 {
+		arg = fmt.Sprintf("%s%%%d", arg, maxParallel)
+	}
 	return []string{arg}, nil
 }
 
 func convertState(state string) drmaa2interface.JobState {
-	state = strings.TrimSpace(state)
+	// cancelled jobs are reported like "CANCELLED by 1000"
+	state = strings.SplitN(strings.TrimSpace(state), " ", 2)[0]
 	switch state {
 	case "RUNNING":
 		return drmaa2interface.Running
@@ -0,0 +1,131 @@
+package slurmcli
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/dgruber/drmaa2interface"
+)
+
+var _ = Describe("Convert", func() {
+
+	Context("Job template conversion", func() {
+
+		It("should convert a job template with all supported fields", func() {
+			start := time.Date(2030, 1, 2, 3, 4, 5, 0, time.Local)
+			jt := drmaa2interface.JobTemplate{
+				RemoteCommand:     "job.sh",
+				Args:              []string{"a", "b"},
+				JobName:           "name",
+				OutputPath:        "/tmp/out",
+				ErrorPath:         "/tmp/err",
+				InputPath:         "/tmp/in",
+				WorkingDirectory:  "/tmp",
+				QueueName:         "debug",
+				ReservationID:     "resv",
+				MinSlots:          4,
+				MaxSlots:          4,
+				MinPhysMemory:     1024,
+				CandidateMachines: []string{"node01", "node02"},
+				StartTime:         start,
+				DeadlineTime:      start.Add(time.Hour),
+				Priority:          -10,
+				Email:             []string{"a@example.com"},
+				EmailOnTerminated: true,
+				ReRunnable:        true,
+				SubmitAsHold:      true,
+				JobEnvironment:    map[string]string{"B": "2", "A": "1"},
+				ResourceLimits:    map[string]string{"WALLCLOCK_TIME": "1h30m"},
+			}
+			jt.ExtensionList = map[string]string{
+				"slurm.constraint": "intel",
+				"slurm.exclusive":  "",
+				// extensions of other backends are ignored
+				"privileged": "true",
+			}
+			args, err := convertJobTemplate("account", jt)
+			Ω(err).Should(BeNil())
+			Ω(args).Should(Equal([]string{
+				"--parsable", "-A", "account",
+				"--job-name=name",
+				"--output=/tmp/out",
+				"--error=/tmp/err",
+				"--input=/tmp/in",
+				"--chdir=/tmp",
+				"--partition=debug",
+				"--reservation=resv",
+				"--ntasks=4",
+				"--mem=1024K",
+				"--nodelist=node01,node02",
+				"--begin=2030-01-02T03:04:05",
+				"--deadline=2030-01-02T04:04:05",
+				"--nice=10",
+				"--mail-user=a@example.com",
+				"--mail-type=END,FAIL",
+				"--requeue",
+				"--hold",
+				"--export=ALL,A=1,B=2",
+				"--time=0-01:30:00",
+				"--constraint=intel",
+				"--exclusive",
+				"job.sh", "a", "b",
+			}))
+		})
+
+		It("should not set the error path when files are joined", func() {
+			args, err := convertJobTemplate("account", drmaa2interface.JobTemplate{
+				RemoteCommand: "job.sh",
+				OutputPath:    "/tmp/out",
+				ErrorPath:     "/tmp/err",
+				JoinFiles:     true,
+			})
+			Ω(err).Should(BeNil())
+			Ω(args).Should(ContainElement("--output=/tmp/out"))
+			Ω(args).ShouldNot(ContainElement("--error=/tmp/err"))
+		})
+
+		It("should convert the wallclock time limit", func() {
+			Ω(formatSlurmTime(90 * time.Second)).Should(Equal("0-00:02:00"))
+			Ω(formatSlurmTime(49 * time.Hour)).Should(Equal("2-01:00:00"))
+		})
+
+		It("should convert job array arguments", func() {
+			args, err := convertArrayJobArgs(1, 10, 1, 0)
+			Ω(err).Should(BeNil())
+			Ω(args).Should(Equal([]string{"--array=1-10"}))
+			args, err = convertArrayJobArgs(1, 10, 2, 4)
+			Ω(err).Should(BeNil())
+			Ω(args).Should(Equal([]string{"--array=1-10:2%4"}))
+			_, err = convertArrayJobArgs(1, 10, 0, 0)
+			Ω(err).ShouldNot(BeNil())
+			_, err = convertArrayJobArgs(1, 10, 1, -1)
+			Ω(err).ShouldNot(BeNil())
+		})
+
+		It("should reject fields which can't be expressed", func() {
+			unsupported := []drmaa2interface.JobTemplate{
+				{MinSlots: 1, MaxSlots: 2},
+				{JobCategory: "image"},
+				{AccountingID: "id"},
+				{MachineOs: "linux"},
+				{MachineArch: "x86_64"},
+				{StageInFiles: map[string]string{"a": "b"}},
+				{StageOutFiles: map[string]string{"a": "b"}},
+				{EmailOnStarted: true},
+				{JobEnvironment: map[string]string{"A": "1,2"}},
+				{ResourceLimits: map[string]string{"CPU_TIME": "60"}},
+				{ResourceLimits: map[string]string{"WALLCLOCK_TIME": "soon"}},
+				{Priority: 10},
+			}
+			for _, jt := range unsupported {
+				jt.RemoteCommand = "job.sh"
+				_, err := convertJobTemplate("account", jt)
+				Ω(err).ShouldNot(BeNil())
+			}
+		})
+
+	})
+
+})
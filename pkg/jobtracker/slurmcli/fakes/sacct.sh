@@ -1 +1,24 @@
 #!/bin/sh
+# prints accounting information for job 42 (done), 43 (cancelled),
+# 44 (running), 45 (held), 46 (pending), and the job array 50
+while [ $# -gt 0 ]; do
+	case $1 in
+		-j) shift; job=$1 ;;
+	esac
+	shift
+done
+
+case $job in
+  42.batch ) echo COMPLETED ;;
+  43.batch ) echo "CANCELLED by 1000" ;;
+  44.batch ) echo RUNNING ;;
+  45.batch ) echo PENDING ;;
+  46.batch ) echo PENDING ;;
+  42 ) echo "42|sleep.sh|debug|default|user1|COMPLETED|0:0|node[01-02]|120|2|60|2023-08-01T12:00:00|2023-08-01T12:00:05|2023-08-01T12:01:05" ;;
+  43 ) echo "43|sleep.sh|debug|default|user1|CANCELLED by 1000|0:15|node03|10|1|10|2023-08-01T12:00:00|2023-08-01T12:00:05|2023-08-01T12:00:15" ;;
+  44 ) echo "44|sleep.sh|batch|default|user1|RUNNING|0:0|gpu1|4|1|4|2023-08-01T12:00:00|2023-08-01T12:00:05|Unknown" ;;
+  50 )
+    echo "50_1|array.sh|debug|default|user1|COMPLETED|0:0|node01|1|1|1|2023-08-01T12:00:00|2023-08-01T12:00:05|2023-08-01T12:00:06"
+    echo "50_2|array.sh|debug|default|user1|RUNNING|0:0|node02|1|1|1|2023-08-01T12:00:00|2023-08-01T12:00:05|Unknown"
+    echo "50_[3-7:2%2]|array.sh|debug|default|user1|PENDING|0:0|None assigned|0|1|0|2023-08-01T12:00:00|Unknown|Unknown" ;;
+esac
@@ -1,4 +1,8 @@
 #!/bin/bash
+if [ "$1" = "--version" ]; then
+	echo "slurm 23.02.7"
+	exit 0
+fi
 if [  "${!#}X" = "failX" ]; then
 	exit 1
 fi
@@ -1 +1,10 @@
 #!/bin/sh
+
+if [ "X$2" = "Xfail" ]; then
+	exit 1
+fi
+
+if [ "X$1 $2" = "Xshow nodes" ]; then
+	echo "NodeName=node01 Arch=x86_64 CoresPerSocket=4 CPUAlloc=2 CPUTot=8 CPULoad=0.52 AvailableFeatures=intel ActiveFeatures=intel Gres=(null) NodeAddr=node01 NodeHostName=node01 Version=23.02.7 OS=Linux 5.15.0-76-generic #83-Ubuntu SMP Thu Jun 15 19:16:32 UTC 2023 RealMemory=7900 AllocMem=0 FreeMem=6000 Sockets=1 Boards=1 State=MIXED ThreadsPerCore=2 TmpDisk=0 Weight=1 Owner=N/A MCS_label=N/A Partitions=debug"
+	echo "NodeName=gpu1 Arch=aarch64 CoresPerSocket=16 CPUAlloc=0 CPUTot=64 CPULoad=0.00 AvailableFeatures=(null) ActiveFeatures=(null) Gres=gpu:4 NodeAddr=gpu1 NodeHostName=gpu1 Version=23.02.7 OS=Linux 6.1.0 RealMemory=256000 AllocMem=0 FreeMem=250000 Sockets=2 Boards=1 State=IDLE+DRAIN ThreadsPerCore=2 TmpDisk=0 Weight=1 Owner=N/A MCS_label=N/A Partitions=gpu"
+fi
@@ -0,0 +1,5 @@
+#!/bin/sh
+# sinfo -h -o %R lists partitions once per node state
+echo "debug"
+echo "debug"
+echo "gpu"
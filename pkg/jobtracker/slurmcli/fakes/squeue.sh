@@ -4,4 +4,20 @@ if [ "X$4" = "Xfail" ]; then
 	exit 1
 fi
 
-echo "bla"
\ No newline at end of file
+# pending reason of a job (squeue -h -j <jobid> -o %r)
+if [ "X$2" = "X-j" ]; then
+	case $3 in
+		45 ) echo "JobHeldUser" ;;
+		* ) echo "Priority" ;;
+	esac
+	exit 0
+fi
+
+# jobs of all users (squeue -h -r -a -o %i --states=all)
+if [ "X$3" = "X-a" ]; then
+	echo "42"
+	echo "43"
+fi
+echo "44"
+echo "50_2"
+echo "50_3"
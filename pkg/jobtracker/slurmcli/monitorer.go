@@ -0,0 +1,91 @@
+package slurmcli
+
+import (
+	"fmt"
+	"os/exec"
+
+	"github.com/dgruber/drmaa2interface"
+	"github.com/dgruber/drmaa2os/pkg/d2hlp"
+)
+
+// Tracker implements the Monitorer interface so that MonitoringSessions
+// can be created by the SessionManager. Other than in job sessions all
+// jobs of all users and accounts are visible. Queues are slurm partitions.
+
+// OpenMonitoringSession checks if sinfo is available which is
+// required for listing the partitions.
+func (t *Tracker) OpenMonitoringSession(name string) error {
+	if _, err := exec.LookPath(t.slurm.info); err != nil {
+		return fmt.Errorf("sinfo command (%s) does not exist", t.slurm.info)
+	}
+	return nil
+}
+
+// CloseMonitoringSession does nothing.
+func (t *Tracker) CloseMonitoringSession(name string) error {
+	return nil
+}
+
+// GetAllJobIDs returns the IDs of all jobs of all users known to squeue.
+// If filter is != nil only the jobs which job info matches the filter
+// are returned.
+func (t *Tracker) GetAllJobIDs(filter *drmaa2interface.JobInfo) ([]string, error) {
+	jobIDs, err := t.slurm.ListAllJobs()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job IDs from squeue: %v", err)
+	}
+	if filter == nil {
+		return jobIDs, nil
+	}
+	matching := make([]string, 0, len(jobIDs))
+	for _, jobID := range jobIDs {
+		ji, err := t.slurm.JobInfoOfAnyUser(jobID)
+		if err != nil {
+			// job might be finished in between
+			continue
+		}
+		if d2hlp.JobInfoMatches(ji, *filter) {
+			matching = append(matching, jobID)
+		}
+	}
+	return matching, nil
+}
+
+// GetAllQueueNames returns all slurm partitions. If names is != nil
+// only the partitions which are defined in names are returned.
+func (t *Tracker) GetAllQueueNames(names []string) ([]string, error) {
+	partitions, err := t.slurm.Partitions()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get partitions from sinfo: %v", err)
+	}
+	if names == nil {
+		return partitions, nil
+	}
+	return d2hlp.NewStringFilter(partitions).GetIncludedSubset(names), nil
+}
+
+// GetAllMachines returns all compute nodes of the cluster. If names
+// is != nil only the nodes which are defined in names are returned.
+func (t *Tracker) GetAllMachines(names []string) ([]drmaa2interface.Machine, error) {
+	machines, err := t.slurm.Nodes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nodes from scontrol: %v", err)
+	}
+	if names == nil {
+		return machines, nil
+	}
+	filter := d2hlp.NewStringFilter(names)
+	filtered := make([]drmaa2interface.Machine, 0, len(machines))
+	for _, machine := range machines {
+		if filter.IsIncluded(machine.Name) {
+			filtered = append(filtered, machine)
+		}
+	}
+	return filtered, nil
+}
+
+// JobInfoFromMonitor returns the job info of a job of any user taken
+// from the slurm accounting.
+func (t *Tracker) JobInfoFromMonitor(id string) (drmaa2interface.JobInfo, error) {
+	return t.slurm.JobInfoOfAnyUser(id)
+}
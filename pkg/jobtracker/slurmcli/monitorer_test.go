@@ -0,0 +1,104 @@
+package slurmcli_test
+
+import (
+	. "github.com/dgruber/drmaa2os/pkg/jobtracker/slurmcli"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/dgruber/drmaa2interface"
+)
+
+var _ = Describe("Monitorer", func() {
+
+	var tracker *Tracker
+
+	BeforeEach(func() {
+		s := NewSlurm("./fakes/sbatch.sh",
+			"./fakes/squeue.sh",
+			"./fakes/scontrol.sh",
+			"./fakes/scancel.sh",
+			"./fakes/sacct.sh",
+			false).WithSinfo("./fakes/sinfo.sh")
+		var err error
+		tracker, err = New("monitor", s)
+		Ω(err).Should(BeNil())
+		Ω(tracker.OpenMonitoringSession("monitor")).Should(BeNil())
+	})
+
+	AfterEach(func() {
+		Ω(tracker.CloseMonitoringSession("monitor")).Should(BeNil())
+	})
+
+	It("should fail to open a monitoring session without sinfo", func() {
+		s := NewSlurm("./fakes/sbatch.sh",
+			"./fakes/squeue.sh",
+			"./fakes/scontrol.sh",
+			"./fakes/scancel.sh",
+			"./fakes/sacct.sh",
+			false).WithSinfo("notfound.sh")
+		t, err := New("monitor", s)
+		Ω(err).Should(BeNil())
+		Ω(t.OpenMonitoringSession("monitor")).ShouldNot(BeNil())
+	})
+
+	It("should return all partitions as queues", func() {
+		queues, err := tracker.GetAllQueueNames(nil)
+		Ω(err).Should(BeNil())
+		Ω(queues).Should(Equal([]string{"debug", "gpu"}))
+
+		queues, err = tracker.GetAllQueueNames([]string{"gpu", "unknown"})
+		Ω(err).Should(BeNil())
+		Ω(queues).Should(Equal([]string{"gpu"}))
+	})
+
+	It("should return all nodes as machines", func() {
+		machines, err := tracker.GetAllMachines(nil)
+		Ω(err).Should(BeNil())
+		Ω(len(machines)).Should(BeNumerically("==", 2))
+
+		Ω(machines[0].Name).Should(Equal("node01"))
+		Ω(machines[0].Available).Should(BeTrue())
+		Ω(machines[0].Architecture).Should(Equal(drmaa2interface.X64))
+		Ω(machines[0].Sockets).Should(BeNumerically("==", 1))
+		Ω(machines[0].CoresPerSocket).Should(BeNumerically("==", 4))
+		Ω(machines[0].ThreadsPerCore).Should(BeNumerically("==", 2))
+		Ω(machines[0].Load).Should(BeNumerically("~", 0.52, 0.001))
+		Ω(machines[0].PhysicalMemory).Should(BeNumerically("==", 7900*1024))
+		Ω(machines[0].OS).Should(Equal(drmaa2interface.Linux))
+		Ω(machines[0].OSVersion.Major).Should(Equal("5"))
+		Ω(machines[0].ExtensionList["partitions"]).Should(Equal("debug"))
+
+		Ω(machines[1].Name).Should(Equal("gpu1"))
+		Ω(machines[1].Available).Should(BeFalse())
+		Ω(machines[1].Architecture).Should(Equal(drmaa2interface.ARM64))
+		Ω(machines[1].Sockets).Should(BeNumerically("==", 2))
+
+		machines, err = tracker.GetAllMachines([]string{"gpu1"})
+		Ω(err).Should(BeNil())
+		Ω(len(machines)).Should(BeNumerically("==", 1))
+		Ω(machines[0].Name).Should(Equal("gpu1"))
+	})
+
+	It("should return the jobs of all users", func() {
+		jobs, err := tracker.GetAllJobIDs(nil)
+		Ω(err).Should(BeNil())
+		Ω(jobs).Should(ConsistOf("42", "43", "44", "50_2", "50_3"))
+	})
+
+	It("should filter jobs by job info", func() {
+		filter := drmaa2interface.CreateJobInfo()
+		filter.State = drmaa2interface.Failed
+		jobs, err := tracker.GetAllJobIDs(&filter)
+		Ω(err).Should(BeNil())
+		Ω(jobs).Should(ConsistOf("43"))
+	})
+
+	It("should return the job info of jobs of all users", func() {
+		ji, err := tracker.JobInfoFromMonitor("42")
+		Ω(err).Should(BeNil())
+		Ω(ji.State).Should(Equal(drmaa2interface.Done))
+		Ω(ji.JobOwner).Should(Equal("user1"))
+	})
+
+})
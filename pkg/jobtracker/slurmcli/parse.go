@@ -2,7 +2,13 @@ package slurmcli
 
 import (
 	"errors"
+	"fmt"
+	"strconv"
 	"strings"
+	"syscall"
+	"time"
+
+	"github.com/dgruber/drmaa2interface"
 )
 
 func parsesbatch(out []byte) (string, error) {
@@ -14,20 +20,324 @@ func parsesbatch(out []byte) (string, error) {
 	// --parsable: "outputs only the jobid and cluster name (if present),
 	// separated by semicolon, only on successful submission."
 	elements := strings.Split(string(out), ";")
-	return elements[0], nil
+	return strings.TrimSpace(elements[0]), nil
 }
 
 func parsesqueue(out []byte) ([]string, error) {
-	return []string{}, nil
+	// squeue -h -r -A default -o %i --states=all
+	// 17
+	// 18_1
+	// 18_2
+	jobIDs := []string{}
+	for _, line := range strings.Split(string(out), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		jobIDs = append(jobIDs, line)
+	}
+	return jobIDs, nil
+}
+
+func parsesinfo(out []byte) ([]string, error) {
+	// sinfo -h -o %R
+	// debug
+	// gpu
+	// partitions are listed once per node state
+	partitions := []string{}
+	known := map[string]bool{}
+	for _, partition := range strings.Fields(string(out)) {
+		if !known[partition] {
+			known[partition] = true
+			partitions = append(partitions, partition)
+		}
+	}
+	return partitions, nil
 }
 
-func parsesacct(out []byte) ([]string, error) {
-	/* $ sacct -P
-	JobID|JobName|Partition|Account|AllocCPUS|State|ExitCode
-	15|sleep.sh|debug|default|2|COMPLETED|0:0
-	15.batch|batch||default|2|COMPLETED|0:0
-	16|sleep.sh|debug|default|2|COMPLETED|0:0
-	16.batch|batch||default|2|COMPLETED|0:0
+// parseNodes parses the output of scontrol show nodes -o which
+// prints one line per node with key=value pairs.
+func parseNodes(out []byte) ([]drmaa2interface.Machine, error) {
+	/* NodeName=node01 Arch=x86_64 CoresPerSocket=4 CPUAlloc=0 CPUTot=8
+	   CPULoad=0.52 AvailableFeatures=(null) ... OS=Linux 5.15.0-76-generic #83-Ubuntu SMP
+	   RealMemory=7900 ... Sockets=1 ... State=IDLE ThreadsPerCore=2 ...
 	*/
-	return []string{}, nil
+	machines := []drmaa2interface.Machine{}
+	for _, line := range strings.Split(string(out), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		node := parseKeyValues(line)
+		if node["NodeName"] == "" {
+			return nil, fmt.Errorf("unexpected scontrol output: %s", line)
+		}
+		machine := drmaa2interface.Machine{
+			Name:         node["NodeName"],
+			Available:    isNodeAvailable(node["State"]),
+			Architecture: convertArch(node["Arch"]),
+		}
+		machine.Sockets, _ = strconv.ParseInt(node["Sockets"], 10, 64)
+		machine.CoresPerSocket, _ = strconv.ParseInt(node["CoresPerSocket"], 10, 64)
+		machine.ThreadsPerCore, _ = strconv.ParseInt(node["ThreadsPerCore"], 10, 64)
+		machine.Load, _ = strconv.ParseFloat(node["CPULoad"], 64)
+		// RealMemory is in MB, DRMAA2 expects kilobytes
+		if memory, err := strconv.ParseInt(node["RealMemory"], 10, 64); err == nil {
+			machine.PhysicalMemory = memory * 1024
+			machine.VirtualMemory = memory * 1024
+		}
+		machine.OS, machine.OSVersion = convertOS(node["OS"])
+		machine.ExtensionList = map[string]string{
+			"state":      node["State"],
+			"partitions": node["Partitions"],
+			"cpus":       node["CPUTot"],
+			"cpus_alloc": node["CPUAlloc"],
+			"features":   node["AvailableFeatures"],
+		}
+		machines = append(machines, machine)
+	}
+	return machines, nil
+}
+
+// parseKeyValues splits a line of key=value pairs. Values can contain
+// spaces (like OS=Linux 5.15.0) hence words without = belong to the
+// value of the previous key.
+func parseKeyValues(line string) map[string]string {
+	values := map[string]string{}
+	key := ""
+	for _, word := range strings.Fields(line) {
+		k, v, found := strings.Cut(word, "=")
+		if !found && key != "" {
+			values[key] += " " + word
+			continue
+		}
+		key = k
+		values[key] = v
+	}
+	return values
+}
+
+func isNodeAvailable(state string) bool {
+	// like IDLE, MIXED, ALLOCATED, DOWN*, IDLE+DRAIN
+	for _, unavailable := range []string{"DOWN", "DRAIN", "FAIL", "NOT_RESPONDING", "POWERED_DOWN"} {
+		if strings.Contains(state, unavailable) {
+			return false
+		}
+	}
+	return state != ""
+}
+
+func convertArch(arch string) drmaa2interface.CPU {
+	switch arch {
+	case "x86_64":
+		return drmaa2interface.X64
+	case "i386", "i686":
+		return drmaa2interface.X86
+	case "aarch64":
+		return drmaa2interface.ARM64
+	case "ppc64le", "ppc64":
+		return drmaa2interface.PowerPC64
+	}
+	return drmaa2interface.OtherCPU
+}
+
+func convertOS(os string) (drmaa2interface.OS, drmaa2interface.Version) {
+	// Linux 5.15.0-76-generic #83-Ubuntu SMP ...
+	fields := strings.Fields(os)
+	if len(fields) == 0 {
+		return drmaa2interface.OtherOS, drmaa2interface.Version{}
+	}
+	var version drmaa2interface.Version
+	if len(fields) > 1 {
+		major, minor, _ := strings.Cut(fields[1], ".")
+		version = drmaa2interface.Version{Major: major, Minor: minor}
+	}
+	switch fields[0] {
+	case "Linux":
+		return drmaa2interface.Linux, version
+	case "Darwin":
+		return drmaa2interface.MacOS, version
+	case "FreeBSD":
+		return drmaa2interface.BSD, version
+	}
+	return drmaa2interface.OtherOS, version
+}
+
+// sacctFormat defines the columns requested from sacct. parsesacct
+// depends on the order.
+const sacctFormat = "JobID,JobName,Partition,Account,User,State,ExitCode,NodeList,CPUTimeRAW,AllocCPUS,ElapsedRaw,Submit,Start,End"
+
+const sacctFields = 14
+
+// sacctTimeLayout is the format of Submit, Start, and End
+const sacctTimeLayout = "2006-01-02T15:04:05"
+
+func parsesacct(out []byte) ([]drmaa2interface.JobInfo, error) {
+	/* $ sacct -P -n -X -o JobID,JobName,Partition,Account,User,State,ExitCode,
+	     NodeList,CPUTimeRAW,AllocCPUS,ElapsedRaw,Submit,Start,End
+	15|sleep.sh|debug|default|user|COMPLETED|0:0|node[01-02]|120|2|60|2023-08-01T12:00:00|2023-08-01T12:00:05|2023-08-01T12:01:05
+	16|sleep.sh|debug|default|user|CANCELLED by 1000|0:15|node03|10|1|10|2023-08-01T12:00:00|2023-08-01T12:00:05|2023-08-01T12:00:15
+	*/
+	infos := []drmaa2interface.JobInfo{}
+	for _, line := range strings.Split(string(out), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		fields := strings.Split(line, "|")
+		if len(fields) != sacctFields {
+			return nil, fmt.Errorf("unexpected sacct output: %s", line)
+		}
+		info := drmaa2interface.CreateJobInfo()
+		info.ID = fields[0]
+		info.QueueName = fields[2]
+		info.JobOwner = fields[4]
+		info.SubState = fields[5]
+		info.State = convertState(fields[5])
+		info.ExitStatus, info.TerminatingSignal = parseExitCode(fields[6])
+		info.AllocatedMachines = expandHostList(fields[7])
+		if cpuTime, err := strconv.ParseInt(fields[8], 10, 64); err == nil {
+			info.CPUTime = cpuTime
+		}
+		if slots, err := strconv.ParseInt(fields[9], 10, 64); err == nil {
+			info.Slots = slots
+		}
+		if elapsed, err := strconv.ParseInt(fields[10], 10, 64); err == nil {
+			info.WallclockTime = time.Duration(elapsed) * time.Second
+		}
+		info.SubmissionTime = parseTime(fields[11])
+		info.DispatchTime = parseTime(fields[12])
+		info.FinishTime = parseTime(fields[13])
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+// parseExitCode converts the sacct ExitCode column (exit code and
+// signal separated by colon, like 0:15) into the exit status and the
+// name of the terminating signal.
+func parseExitCode(exitCode string) (int, string) {
+	code, signal, _ := strings.Cut(exitCode, ":")
+	exitStatus, err := strconv.Atoi(code)
+	if err != nil {
+		exitStatus = drmaa2interface.UnsetNum
+	}
+	if sig, err := strconv.Atoi(signal); err == nil && sig != 0 {
+		return exitStatus, syscall.Signal(sig).String()
+	}
+	return exitStatus, ""
+}
+
+// parseTime returns the zero time when the time is not (yet) known
+// (like "Unknown" or "None").
+func parseTime(t string) time.Time {
+	parsed, err := time.ParseInLocation(sacctTimeLayout, t, time.Local)
+	if err != nil {
+		return time.Time{}
+	}
+	return parsed
+}
+
+// expandHostList expands the compressed slurm host list notation
+// (like node[01-03,05],gpu1) into a list of host names.
+func expandHostList(hostList string) []string {
+	hosts := []string{}
+	if hostList == "" || hostList == "None assigned" {
+		return hosts
+	}
+	for _, entry := range splitOutsideBrackets(hostList) {
+		prefix, ranges, found := strings.Cut(entry, "[")
+		if !found {
+			hosts = append(hosts, entry)
+			continue
+		}
+		ranges, suffix, _ := strings.Cut(ranges, "]")
+		for _, r := range strings.Split(ranges, ",") {
+			from, to, isRange := strings.Cut(r, "-")
+			if !isRange {
+				hosts = append(hosts, prefix+r+suffix)
+				continue
+			}
+			first, err1 := strconv.Atoi(from)
+			last, err2 := strconv.Atoi(to)
+			if err1 != nil || err2 != nil {
+				hosts = append(hosts, prefix+r+suffix)
+				continue
+			}
+			for i := first; i <= last; i++ {
+				hosts = append(hosts, fmt.Sprintf("%s%0*d%s", prefix, len(from), i, suffix))
+			}
+		}
+	}
+	return hosts
+}
+
+func splitOutsideBrackets(list string) []string {
+	entries := []string{}
+	depth, start := 0, 0
+	for i, c := range list {
+		switch c {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case ',':
+			if depth == 0 {
+				entries = append(entries, list[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(entries, list[start:])
+}
+
+// expandArrayJobIDs expands the array task IDs of pending job array
+// tasks (like 18_[3-7%2] or 18_[1,3,5-7]) into single task IDs
+// (like 18_3). Other job IDs are returned unchanged.
+func expandArrayJobIDs(jobIDs []string) []string {
+	expanded := make([]string, 0, len(jobIDs))
+	for _, jobID := range jobIDs {
+		arrayJobID, tasks, found := strings.Cut(jobID, "_[")
+		if !found {
+			expanded = append(expanded, jobID)
+			continue
+		}
+		tasks = strings.TrimSuffix(tasks, "]")
+		// throttling limit
+		tasks, _, _ = strings.Cut(tasks, "%")
+		for _, r := range strings.Split(tasks, ",") {
+			from, rest, isRange := strings.Cut(r, "-")
+			if !isRange {
+				expanded = append(expanded, arrayJobID+"_"+r)
+				continue
+			}
+			// step is given after colon (like 1-7:2)
+			to, stepStr, hasStep := strings.Cut(rest, ":")
+			step := 1
+			if hasStep {
+				step, _ = strconv.Atoi(stepStr)
+			}
+			first, err1 := strconv.Atoi(from)
+			last, err2 := strconv.Atoi(to)
+			if err1 != nil || err2 != nil || step <= 0 {
+				expanded = append(expanded, arrayJobID+"_"+r)
+				continue
+			}
+			for i := first; i <= last; i += step {
+				expanded = append(expanded, fmt.Sprintf("%s_%d", arrayJobID, i))
+			}
+		}
+	}
+	return expanded
+}
+
+func parseVersion(out []byte) (drmaa2interface.Version, error) {
+	// sbatch --version
+	// slurm 23.02.7
+	fields := strings.Fields(string(out))
+	if len(fields) != 2 || fields[0] != "slurm" {
+		return drmaa2interface.Version{}, fmt.Errorf("unexpected version output: %s", string(out))
+	}
+	major, minor, _ := strings.Cut(fields[1], ".")
+	return drmaa2interface.Version{Major: major, Minor: minor}, nil
 }
@@ -2,6 +2,7 @@ package slurmcli
 
 import (
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/dgruber/drmaa2interface"
@@ -25,6 +26,39 @@ func (a *allocator) New(jobSessionName string, jobTrackerInitParams interface{})
 		"squeue", "scontrol", "scancel", "sacct", true))
 }
 
+// DrmsName returns "slurm".
+func (a *allocator) DrmsName() string {
+	return "slurm"
+}
+
+// DrmsVersion returns the version of the installed slurm.
+func (a *allocator) DrmsVersion() (drmaa2interface.Version, error) {
+	return NewSlurm("sbatch", "squeue", "scontrol", "scancel", "sacct",
+		true).Version()
+}
+
+// Supports returns true for job array throttling and the job template
+// fields Email and DeadlineTime which are mapped to sbatch options.
+func (a *allocator) Supports(capability drmaa2interface.Capability) bool {
+	switch capability {
+	case drmaa2interface.BulkJobsMaxParallel,
+		drmaa2interface.JtEmail, drmaa2interface.JtDeadline:
+		return true
+	}
+	return false
+}
+
+// SupportsJobControl returns true for all job control actions.
+func (a *allocator) SupportsJobControl(action string) bool {
+	switch action {
+	case jobtracker.JobControlSuspend, jobtracker.JobControlResume,
+		jobtracker.JobControlHold, jobtracker.JobControlRelease,
+		jobtracker.JobControlTerminate:
+		return true
+	}
+	return false
+}
+
 // Tracker implements the JobTracker interface by calling
 // the slurm command line.
 type Tracker struct {
@@ -48,7 +82,8 @@ func New(jobsession string, slurmCLI *Slurm) (*Tracker, error) {
 	}, nil
 }
 
-// ListJobs shows all running slurm jobs.
+// ListJobs shows all slurm jobs of the job session (slurm account)
+// which are known to squeue.
 func (t *Tracker) ListJobs() ([]string, error) {
 	return t.slurm.ListJobs(t.sessionName, "all")
 }
@@ -60,25 +95,31 @@ func (t *Tracker) AddJob(jt drmaa2interface.JobTemplate) (string, error) {
 
 // AddArrayJob creates a slurm job array.
 func (t *Tracker) AddArrayJob(jt drmaa2interface.JobTemplate, begin, end, step, maxParallel int) (string, error) {
-	return "", nil
+	return t.slurm.SubmitJobArray(t.sessionName, jt, begin, end, step, maxParallel)
 }
 
-// ListArrayJobs shows all slums jobs which belong to a certain job array.
+// ListArrayJobs shows all slurm jobs which belong to a certain job array.
 func (t *Tracker) ListArrayJobs(arrayjobid string) ([]string, error) {
-	return nil, nil
+	return t.slurm.ListArrayJobs(t.sessionName, arrayjobid)
 }
 
-// JobState returns the state of the slum job.
+// JobState returns the state of the slurm job. Pending jobs which
+// are held are reported as QueuedHeld.
 func (t *Tracker) JobState(jobid string) (drmaa2interface.JobState, string, error) {
-	return t.slurm.State(t.sessionName, jobid), "", nil
+	state := t.slurm.State(t.sessionName, jobid)
+	if state == drmaa2interface.Queued && t.slurm.IsHeld(t.sessionName, jobid) {
+		return drmaa2interface.QueuedHeld, "", nil
+	}
+	return state, "", nil
 }
 
-// JobInfo returns detailed information about the job.
+// JobInfo returns detailed information about the job taken from
+// the slurm accounting (sacct).
 func (t *Tracker) JobInfo(jobid string) (drmaa2interface.JobInfo, error) {
-	return drmaa2interface.JobInfo{}, nil
+	return t.slurm.JobInfo(t.sessionName, jobid)
 }
 
-// JobControl suspends, resumes, or stops a slurm job.
+// JobControl suspends, resumes, holds, releases, or stops a slurm job.
 func (t *Tracker) JobControl(jobid, state string) error {
 	switch state {
 	case "suspend":
@@ -86,9 +127,9 @@ func (t *Tracker) JobControl(jobid, state string) error {
 	case "resume":
 		return t.slurm.Resume(t.sessionName, jobid)
 	case "hold":
-		return errors.New("Unsupported Operation")
+		return t.slurm.Hold(t.sessionName, jobid)
 	case "release":
-		return errors.New("Unsupported Operation")
+		return t.slurm.Release(t.sessionName, jobid)
 	case "terminate":
 		return t.slurm.Terminate(t.sessionName, jobid)
 	}
@@ -106,8 +147,13 @@ func (t *Tracker) ListJobCategories() ([]string, error) {
 	return []string{}, nil
 }
 
-// DeleteJob removes the job from the internal storage. It errors
-// when the job is not yet in any end state.
+// DeleteJob errors when the job is not yet in any end state. Otherwise
+// nothing happens as finished jobs can't be removed from the slurm
+// accounting.
 func (t *Tracker) DeleteJob(jobid string) error {
+	state := t.slurm.State(t.sessionName, jobid)
+	if state != drmaa2interface.Done && state != drmaa2interface.Failed {
+		return fmt.Errorf("job %s is not in an end state (%s)", jobid, state)
+	}
 	return nil
 }
@@ -1,6 +1,9 @@
 package slurmcli_test
 
 import (
+	"syscall"
+	"time"
+
 	. "github.com/dgruber/drmaa2os/pkg/jobtracker/slurmcli"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -41,13 +44,95 @@ var _ = Describe("Tracker", func() {
 			Ω(jobid).ShouldNot(Equal(""))
 		})
 
+		It("should report the slurm version", func() {
+			version, err := s.Version()
+			Ω(err).Should(BeNil())
+			Ω(version.Major).Should(Equal("23"))
+			Ω(version.Minor).Should(Equal("02.7"))
+		})
+
 		It("should be possible to list jobs", func() {
 			tracker, err := New("test", s)
 			Ω(err).Should(BeNil())
 			Ω(tracker).ShouldNot(BeNil())
 			jobs, err := tracker.ListJobs()
 			Ω(err).Should(BeNil())
-			Ω(jobs).ShouldNot(BeNil())
+			Ω(jobs).Should(ConsistOf("44", "50_2", "50_3"))
+		})
+
+		It("should fail to list jobs when squeue fails", func() {
+			tracker, err := New("fail", s)
+			Ω(err).Should(BeNil())
+			_, err = tracker.ListJobs()
+			Ω(err).ShouldNot(BeNil())
+		})
+
+		It("should list all tasks of a job array", func() {
+			tracker, err := New("test", s)
+			Ω(err).Should(BeNil())
+			jobs, err := tracker.ListArrayJobs("50")
+			Ω(err).Should(BeNil())
+			Ω(jobs).Should(Equal([]string{"50_1", "50_2", "50_3", "50_5", "50_7"}))
+		})
+
+	})
+
+	Context("Job info", func() {
+
+		It("should return the job info of a finished job", func() {
+			tracker, err := New("test", s)
+			Ω(err).Should(BeNil())
+			ji, err := tracker.JobInfo("42")
+			Ω(err).Should(BeNil())
+			Ω(ji.ID).Should(Equal("42"))
+			Ω(ji.State).Should(Equal(drmaa2interface.Done))
+			Ω(ji.ExitStatus).Should(Equal(0))
+			Ω(ji.TerminatingSignal).Should(Equal(""))
+			Ω(ji.QueueName).Should(Equal("debug"))
+			Ω(ji.JobOwner).Should(Equal("user1"))
+			Ω(ji.AllocatedMachines).Should(Equal([]string{"node01", "node02"}))
+			Ω(ji.CPUTime).Should(BeNumerically("==", 120))
+			Ω(ji.Slots).Should(BeNumerically("==", 2))
+			Ω(ji.WallclockTime).Should(Equal(time.Minute))
+			Ω(ji.SubmissionTime).Should(Equal(time.Date(2023, 8, 1, 12, 0, 0, 0, time.Local)))
+			Ω(ji.DispatchTime).Should(Equal(time.Date(2023, 8, 1, 12, 0, 5, 0, time.Local)))
+			Ω(ji.FinishTime).Should(Equal(time.Date(2023, 8, 1, 12, 1, 5, 0, time.Local)))
+		})
+
+		It("should return the terminating signal of a cancelled job", func() {
+			tracker, err := New("test", s)
+			Ω(err).Should(BeNil())
+			ji, err := tracker.JobInfo("43")
+			Ω(err).Should(BeNil())
+			Ω(ji.State).Should(Equal(drmaa2interface.Failed))
+			Ω(ji.SubState).Should(Equal("CANCELLED by 1000"))
+			Ω(ji.TerminatingSignal).Should(Equal(syscall.SIGTERM.String()))
+			Ω(ji.AllocatedMachines).Should(Equal([]string{"node03"}))
+		})
+
+		It("should not set the finish time of a running job", func() {
+			tracker, err := New("test", s)
+			Ω(err).Should(BeNil())
+			ji, err := tracker.JobInfo("44")
+			Ω(err).Should(BeNil())
+			Ω(ji.State).Should(Equal(drmaa2interface.Running))
+			Ω(ji.QueueName).Should(Equal("batch"))
+			Ω(ji.FinishTime.IsZero()).Should(BeTrue())
+		})
+
+		It("should fail for unknown jobs", func() {
+			tracker, err := New("test", s)
+			Ω(err).Should(BeNil())
+			_, err = tracker.JobInfo("1234")
+			Ω(err).ShouldNot(BeNil())
+		})
+
+		It("should delete only jobs in an end state", func() {
+			tracker, err := New("test", s)
+			Ω(err).Should(BeNil())
+			Ω(tracker.DeleteJob("42")).Should(BeNil())
+			Ω(tracker.DeleteJob("43")).Should(BeNil())
+			Ω(tracker.DeleteJob("44")).ShouldNot(BeNil())
 		})
 
 	})
@@ -96,6 +181,36 @@ var _ = Describe("Tracker", func() {
 
 	})
 
+	Context("Job control", func() {
+
+		It("should hold and release jobs", func() {
+			tracker, err := New("test", s)
+			Ω(err).Should(BeNil())
+			Ω(tracker.JobControl("46", "hold")).Should(BeNil())
+			Ω(tracker.JobControl("46", "release")).Should(BeNil())
+			Ω(tracker.JobControl("fail", "hold")).ShouldNot(BeNil())
+			Ω(tracker.JobControl("fail", "release")).ShouldNot(BeNil())
+		})
+
+		It("should report held jobs as QueuedHeld", func() {
+			tracker, err := New("test", s)
+			Ω(err).Should(BeNil())
+			Ω(tracker.JobState("45")).Should(Equal(drmaa2interface.QueuedHeld))
+			Ω(tracker.JobState("46")).Should(Equal(drmaa2interface.Queued))
+		})
+
+		It("should submit job arrays with throttling", func() {
+			tracker, err := New("test", s)
+			Ω(err).Should(BeNil())
+			jobid, err := tracker.AddArrayJob(drmaa2interface.JobTemplate{
+				RemoteCommand: "mycommand",
+			}, 1, 10, 1, 4)
+			Ω(err).Should(BeNil())
+			Ω(jobid).Should(Equal("77"))
+		})
+
+	})
+
 	Context("Error cases", func() {
 
 		It("should fail to create a job tracker when commands are not available", func() {
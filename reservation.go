@@ -4,25 +4,50 @@ import (
 	"github.com/dgruber/drmaa2interface"
 )
 
+// Reservation represents an advance reservation of slots on the
+// local machine. It is created by a ReservationSession. Jobs which
+// refer to the reservation ID in the ReservationID field of the
+// JobTemplate are only executed within the reserved time window
+// and within the reserved slots.
 type Reservation struct {
+	id       string
+	session  string
+	template drmaa2interface.ReservationTemplate
+	store    *reservationStore
 }
 
+// GetID returns the reservation identifier which can be used
+// in the ReservationID field of a JobTemplate.
 func (r *Reservation) GetID() (string, error) {
-	return "", nil
+	return r.id, nil
 }
 
+// GetSessionName returns the name of the ReservationSession which
+// was used for creating the reservation.
 func (r *Reservation) GetSessionName() (string, error) {
-	return "", nil
+	return r.session, nil
 }
 
+// GetTemplate returns the ReservationTemplate which was used for
+// requesting the reservation.
 func (r *Reservation) GetTemplate() (drmaa2interface.ReservationTemplate, error) {
-	return drmaa2interface.ReservationTemplate{}, nil
+	return r.template, nil
 }
 
+// GetInfo returns the current details of the reservation, like
+// the granted time window and the amount of reserved slots.
 func (r *Reservation) GetInfo() (drmaa2interface.ReservationInfo, error) {
-	return drmaa2interface.ReservationInfo{}, nil
+	record, err := r.store.get(r.session, r.id)
+	if err != nil {
+		return drmaa2interface.ReservationInfo{}, err
+	}
+	return record.Info, nil
 }
 
+// Terminate cancels the reservation and frees the booked slots. Jobs
+// which are still running within the reservation are terminated. Jobs
+// submitted into the reservation by other processes are terminated at
+// the end of the originally reserved time window.
 func (r *Reservation) Terminate() error {
-	return nil
+	return r.store.remove(r.session, r.id)
 }
@@ -4,29 +4,90 @@ import (
 	"github.com/dgruber/drmaa2interface"
 )
 
+// ReservationSession manages advance reservations. Currently
+// reservations are only available for the DefaultSession where
+// slots of the local machine are booked for a given time window.
+// Reservations are stored persistently in the DB of the
+// SessionManager.
 type ReservationSession struct {
+	name    string
+	contact string
+	store   *reservationStore
 }
 
+// Close disengages from the reservation session. Reservations are
+// not affected. Afterwards the reservation session can't be used
+// anymore.
 func (rs *ReservationSession) Close() error {
+	if rs.name == "" && rs.store == nil {
+		return ErrorInvalidSession
+	}
+	rs.name = ""
+	rs.store = nil
 	return nil
 }
 
+// GetContact returns the contact string which was used when the
+// reservation session was created.
 func (rs *ReservationSession) GetContact() (string, error) {
-	return "", nil
+	if rs.store == nil {
+		return "", ErrorInvalidSession
+	}
+	return rs.contact, nil
 }
 
+// GetSessionName returns the name of the reservation session.
 func (rs *ReservationSession) GetSessionName() (string, error) {
-	return "", nil
+	if rs.store == nil {
+		return "", ErrorInvalidSession
+	}
+	return rs.name, nil
 }
 
-func (rs *ReservationSession) GetReservation(string) (drmaa2interface.Reservation, error) {
-	return nil, nil
+// GetReservation returns the reservation with the given ID. If the
+// reservation does not belong to the session ErrorInvalidArgument
+// is returned.
+func (rs *ReservationSession) GetReservation(id string) (drmaa2interface.Reservation, error) {
+	if rs.store == nil {
+		return nil, ErrorInvalidSession
+	}
+	record, err := rs.store.get(rs.name, id)
+	if err != nil {
+		return nil, err
+	}
+	return rs.store.newReservation(rs.name, record), nil
 }
 
+// RequestReservation books slots on the local machine for the time
+// window specified in the ReservationTemplate. If StartTime is not
+// set the reservation starts immediately. The end of the reservation
+// is either given by EndTime or by Duration. The request is rejected
+// when the machine does not offer enough free slots within the time
+// window (taking other reservations into account) or when the machine
+// does not match the template.
 func (rs *ReservationSession) RequestReservation(template drmaa2interface.ReservationTemplate) (drmaa2interface.Reservation, error) {
-	return nil, nil
+	if rs.store == nil {
+		return nil, ErrorInvalidSession
+	}
+	record, err := rs.store.request(rs.name, template)
+	if err != nil {
+		return nil, err
+	}
+	return rs.store.newReservation(rs.name, record), nil
 }
 
-func (rs *ReservationSession) GetReservations() ([]Reservation, error) {
-	return nil, nil
+// GetReservations returns all reservations of the session.
+func (rs *ReservationSession) GetReservations() ([]drmaa2interface.Reservation, error) {
+	if rs.store == nil {
+		return nil, ErrorInvalidSession
+	}
+	records, err := rs.store.list(rs.name)
+	if err != nil {
+		return nil, err
+	}
+	reservations := make([]drmaa2interface.Reservation, 0, len(records))
+	for _, record := range records {
+		reservations = append(reservations, rs.store.newReservation(rs.name, record))
+	}
+	return reservations, nil
 }
@@ -0,0 +1,588 @@
+package drmaa2os
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dgruber/drmaa2interface"
+	"github.com/dgruber/drmaa2os/pkg/jobtracker"
+	"github.com/dgruber/drmaa2os/pkg/storage"
+	"github.com/shirou/gopsutil/v3/mem"
+)
+
+// reservationMutex serializes all read-modify-write cycles on the
+// reservation session records as well as the admission of jobs
+// into reservations.
+var reservationMutex sync.Mutex
+
+var (
+	// pendingSlots contains the slots of jobs which were admitted into
+	// a reservation but are not submitted yet (by reservation ID).
+	pendingSlots = make(map[string]int64)
+	// terminatedReservations contains a channel for each supervised
+	// reservation which is closed when the reservation is terminated.
+	terminatedReservations = make(map[string]chan struct{})
+)
+
+// reservationSessionRecord is stored as JSON for each reservation
+// session under the storage.ReservationSessionType key type.
+type reservationSessionRecord struct {
+	Contact      string              `json:"contact"`
+	LastID       int64               `json:"lastID"`
+	Reservations []reservationRecord `json:"reservations,omitempty"`
+}
+
+type reservationRecord struct {
+	Template drmaa2interface.ReservationTemplate `json:"template"`
+	Info     drmaa2interface.ReservationInfo     `json:"info"`
+	Jobs     []reservedJob                       `json:"jobs,omitempty"`
+}
+
+// reservedJob is a job or a job array which currently consumes
+// slots of a reservation.
+type reservedJob struct {
+	SessionName string `json:"sessionName"`
+	JobID       string `json:"jobID"`
+	IsArrayJob  bool   `json:"isArrayJob,omitempty"`
+	Slots       int64  `json:"slots"`
+}
+
+// reservationStore books slots of the local machine for reservations
+// and keeps track of the jobs running within the reservations.
+type reservationStore struct {
+	store storage.Storer
+	// slots is the amount of slots the local machine offers
+	slots int64
+}
+
+func newReservationStore(store storage.Storer) *reservationStore {
+	return &reservationStore{
+		store: store,
+		slots: int64(runtime.NumCPU()),
+	}
+}
+
+func (r *reservationStore) newReservation(session string, record reservationRecord) *Reservation {
+	return &Reservation{
+		id:       record.Info.ReservationID,
+		session:  session,
+		template: record.Template,
+		store:    r,
+	}
+}
+
+func (r *reservationStore) createSession(name, contact string) error {
+	reservationMutex.Lock()
+	defer reservationMutex.Unlock()
+	if r.store.Exists(storage.ReservationSessionType, name) {
+		return fmt.Errorf("Session already exists")
+	}
+	return r.save(name, reservationSessionRecord{Contact: contact})
+}
+
+func (r *reservationStore) contact(name string) (string, error) {
+	reservationMutex.Lock()
+	defer reservationMutex.Unlock()
+	record, err := r.load(name)
+	if err != nil {
+		return "", err
+	}
+	return record.Contact, nil
+}
+
+func (r *reservationStore) load(session string) (reservationSessionRecord, error) {
+	var record reservationSessionRecord
+	value, err := r.store.Get(storage.ReservationSessionType, session)
+	if err != nil {
+		return record, fmt.Errorf("reservation session %s does not exist", session)
+	}
+	if err := json.Unmarshal([]byte(value), &record); err != nil {
+		return record, fmt.Errorf("failed to decode reservation session %s: %v", session, err)
+	}
+	return record, nil
+}
+
+func (r *reservationStore) save(session string, record reservationSessionRecord) error {
+	value, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode reservation session %s: %v", session, err)
+	}
+	return r.store.Put(storage.ReservationSessionType, session, string(value))
+}
+
+func (r *reservationStore) get(session, id string) (reservationRecord, error) {
+	reservationMutex.Lock()
+	defer reservationMutex.Unlock()
+	record, err := r.load(session)
+	if err != nil {
+		return reservationRecord{}, err
+	}
+	for _, reservation := range record.Reservations {
+		if reservation.Info.ReservationID == id {
+			return reservation, nil
+		}
+	}
+	return reservationRecord{}, ErrorInvalidArgument
+}
+
+func (r *reservationStore) list(session string) ([]reservationRecord, error) {
+	reservationMutex.Lock()
+	defer reservationMutex.Unlock()
+	record, err := r.load(session)
+	if err != nil {
+		return nil, err
+	}
+	return record.Reservations, nil
+}
+
+func (r *reservationStore) remove(session, id string) error {
+	reservationMutex.Lock()
+	defer reservationMutex.Unlock()
+	record, err := r.load(session)
+	if err != nil {
+		return err
+	}
+	for i := range record.Reservations {
+		if record.Reservations[i].Info.ReservationID == id {
+			record.Reservations = append(record.Reservations[:i], record.Reservations[i+1:]...)
+			if err := r.save(session, record); err != nil {
+				return err
+			}
+			terminateReservation(id)
+			return nil
+		}
+	}
+	return ErrorInvalidArgument
+}
+
+// terminateReservation stops the jobs running in the reservation. Must
+// be called while holding the reservationMutex.
+func terminateReservation(reservationID string) {
+	if terminated, exists := terminatedReservations[reservationID]; exists {
+		close(terminated)
+		delete(terminatedReservations, reservationID)
+	}
+}
+
+// terminated returns a channel which is closed when the reservation
+// gets terminated. The channel is closed already when the reservation
+// does not exist anymore.
+func (r *reservationStore) terminated(reservationID string) <-chan struct{} {
+	reservationMutex.Lock()
+	defer reservationMutex.Unlock()
+	if terminated, exists := terminatedReservations[reservationID]; exists {
+		return terminated
+	}
+	terminated := make(chan struct{})
+	if _, _, _, err := r.find(reservationID); err != nil {
+		close(terminated)
+		return terminated
+	}
+	terminatedReservations[reservationID] = terminated
+	return terminated
+}
+
+// request checks if the reservation can be granted and stores it.
+func (r *reservationStore) request(session string, template drmaa2interface.ReservationTemplate) (reservationRecord, error) {
+	start, end, err := reservationWindow(template, time.Now())
+	if err != nil {
+		return reservationRecord{}, err
+	}
+	if err := matchLocalMachine(template); err != nil {
+		return reservationRecord{}, err
+	}
+
+	minSlots := template.MinSlots
+	if minSlots <= 0 {
+		minSlots = 1
+	}
+	maxSlots := template.MaxSlots
+	if maxSlots < minSlots {
+		maxSlots = minSlots
+	}
+
+	reservationMutex.Lock()
+	defer reservationMutex.Unlock()
+
+	record, err := r.load(session)
+	if err != nil {
+		return reservationRecord{}, err
+	}
+	booked, err := r.bookedSlots(start, end)
+	if err != nil {
+		return reservationRecord{}, err
+	}
+	free := r.slots - booked
+	if free < minSlots {
+		return reservationRecord{}, fmt.Errorf(
+			"not enough free slots between %s and %s: requested %d but only %d are available",
+			start.Format(time.RFC3339), end.Format(time.RFC3339), minSlots, free)
+	}
+	reserved := maxSlots
+	if reserved > free {
+		reserved = free
+	}
+
+	host, _ := os.Hostname()
+	record.LastID++
+	reservation := reservationRecord{
+		Template: template,
+		Info: drmaa2interface.ReservationInfo{
+			ReservationID:        fmt.Sprintf("%s.%d", session, record.LastID),
+			ReservationName:      template.Name,
+			ReservationStartTime: start,
+			ReservationEndTime:   end,
+			ACL:                  template.UsersACL,
+			ReservedSlots:        reserved,
+			ReservedMachines:     []string{host},
+		},
+	}
+	record.Reservations = append(record.Reservations, reservation)
+	if err := r.save(session, record); err != nil {
+		return reservationRecord{}, err
+	}
+	return reservation, nil
+}
+
+// bookedSlots returns the maximum amount of slots booked by all
+// reservations at any point in time of the given time window.
+func (r *reservationStore) bookedSlots(start, end time.Time) (int64, error) {
+	sessions, err := r.store.List(storage.ReservationSessionType)
+	if err != nil {
+		return 0, err
+	}
+	overlapping := make([]drmaa2interface.ReservationInfo, 0)
+	for _, session := range sessions {
+		record, err := r.load(session)
+		if err != nil {
+			return 0, err
+		}
+		for _, reservation := range record.Reservations {
+			info := reservation.Info
+			if info.ReservationStartTime.Before(end) && info.ReservationEndTime.After(start) {
+				overlapping = append(overlapping, info)
+			}
+		}
+	}
+	// the amount of booked slots can only increase at the start
+	// of a reservation
+	points := []time.Time{start}
+	for _, info := range overlapping {
+		if info.ReservationStartTime.After(start) {
+			points = append(points, info.ReservationStartTime)
+		}
+	}
+	var max int64
+	for _, point := range points {
+		var booked int64
+		for _, info := range overlapping {
+			if !info.ReservationStartTime.After(point) && info.ReservationEndTime.After(point) {
+				booked += info.ReservedSlots
+			}
+		}
+		if booked > max {
+			max = booked
+		}
+	}
+	return max, nil
+}
+
+// find searches the reservation in all reservation sessions.
+func (r *reservationStore) find(id string) (string, reservationSessionRecord, int, error) {
+	sessions, err := r.store.List(storage.ReservationSessionType)
+	if err != nil {
+		return "", reservationSessionRecord{}, -1, err
+	}
+	for _, session := range sessions {
+		record, err := r.load(session)
+		if err != nil {
+			continue
+		}
+		for i := range record.Reservations {
+			if record.Reservations[i].Info.ReservationID == id {
+				return session, record, i, nil
+			}
+		}
+	}
+	return "", reservationSessionRecord{}, -1, fmt.Errorf("reservation %s does not exist", id)
+}
+
+// submit admits a job or job array into the reservation and calls the
+// submit function with the start of the reservation. The submit function
+// must return the ID of the job. The slots are booked before the
+// submission so that the reserved slots can't be exceeded by concurrent
+// submissions while the submission itself runs without holding the lock.
+// The isActive function is used for finding out which of the jobs of the
+// reservation still consume slots. It returns the end of the reservation.
+func (r *reservationStore) submit(jobSession, reservationID string, slots int64, isArrayJob bool,
+	isActive func(job reservedJob) bool, submit func(start time.Time) (string, error)) (time.Time, error) {
+
+	start, end, inactive, err := r.admit(reservationID, slots, isActive)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	jobID, err := submit(start)
+
+	reservationMutex.Lock()
+	defer reservationMutex.Unlock()
+
+	pendingSlots[reservationID] -= slots
+	if pendingSlots[reservationID] <= 0 {
+		delete(pendingSlots, reservationID)
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	session, record, index, err := r.find(reservationID)
+	if err != nil {
+		// the reservation was terminated meanwhile, the job gets
+		// terminated by its supervisor
+		return end, nil
+	}
+	reservation := record.Reservations[index]
+	jobs := make([]reservedJob, 0, len(reservation.Jobs)+1)
+	for _, job := range reservation.Jobs {
+		if !inactive[job] {
+			jobs = append(jobs, job)
+		}
+	}
+	reservation.Jobs = append(jobs, reservedJob{
+		SessionName: jobSession,
+		JobID:       jobID,
+		IsArrayJob:  isArrayJob,
+		Slots:       slots,
+	})
+	record.Reservations[index] = reservation
+	if err := r.save(session, record); err != nil {
+		return time.Time{}, err
+	}
+	return end, nil
+}
+
+// admit checks if the reservation has not ended and has enough free
+// slots and books the slots as pending. It returns the start and the end
+// of the reservation and the jobs which don't consume slots anymore.
+func (r *reservationStore) admit(reservationID string, slots int64,
+	isActive func(job reservedJob) bool) (time.Time, time.Time, map[reservedJob]bool, error) {
+
+	reservationMutex.Lock()
+	defer reservationMutex.Unlock()
+
+	_, record, index, err := r.find(reservationID)
+	if err != nil {
+		return time.Time{}, time.Time{}, nil, err
+	}
+	reservation := record.Reservations[index]
+	info := reservation.Info
+
+	if !time.Now().Before(info.ReservationEndTime) {
+		return time.Time{}, time.Time{}, nil, fmt.Errorf("reservation %s has ended at %s",
+			reservationID, info.ReservationEndTime.Format(time.RFC3339))
+	}
+	if err := checkReservationACL(info.ACL); err != nil {
+		return time.Time{}, time.Time{}, nil, err
+	}
+
+	inactive := make(map[reservedJob]bool)
+	used := pendingSlots[reservationID]
+	for _, job := range reservation.Jobs {
+		if isActive(job) {
+			used += job.Slots
+		} else {
+			inactive[job] = true
+		}
+	}
+	if used+slots > info.ReservedSlots {
+		return time.Time{}, time.Time{}, nil, fmt.Errorf(
+			"reservation %s has not enough free slots: requested %d, reserved %d, in use %d",
+			reservationID, slots, info.ReservedSlots, used)
+	}
+	pendingSlots[reservationID] += slots
+	return info.ReservationStartTime, info.ReservationEndTime, inactive, nil
+}
+
+// release frees the slots a job consumes in a reservation.
+func (r *reservationStore) release(reservationID, jobSession, jobID string) error {
+	reservationMutex.Lock()
+	defer reservationMutex.Unlock()
+
+	session, record, index, err := r.find(reservationID)
+	if err != nil {
+		// reservation was terminated meanwhile
+		return nil
+	}
+	jobs := record.Reservations[index].Jobs
+	for i := range jobs {
+		if jobs[i].SessionName == jobSession && jobs[i].JobID == jobID {
+			record.Reservations[index].Jobs = append(jobs[:i], jobs[i+1:]...)
+			return r.save(session, record)
+		}
+	}
+	return nil
+}
+
+// superviseReservedJobs terminates the given jobs when they are still
+// running at the end of the reservation or when the reservation gets
+// terminated and frees the slots of the reservation once the jobs are
+// finished.
+func (r *reservationStore) superviseReservedJobs(tracker jobtracker.JobTracker, reservationID,
+	jobSession, jobID string, jobIDs []string, end time.Time) {
+	terminated := r.terminated(reservationID)
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		for _, id := range jobIDs {
+			remaining := time.Until(end)
+			if remaining <= 0 {
+				return
+			}
+			tracker.Wait(id, remaining, drmaa2interface.Done, drmaa2interface.Failed)
+		}
+	}()
+	deadline := time.NewTimer(time.Until(end))
+	defer deadline.Stop()
+	select {
+	case <-finished:
+	case <-deadline.C:
+	case <-terminated:
+	}
+	for _, id := range jobIDs {
+		state, _, err := tracker.JobState(id)
+		if err == nil && state != drmaa2interface.Done && state != drmaa2interface.Failed {
+			tracker.JobControl(id, jobtracker.JobControlTerminate)
+		}
+	}
+	r.release(reservationID, jobSession, jobID)
+}
+
+// reservationWindow determines start and end time of the reservation.
+func reservationWindow(template drmaa2interface.ReservationTemplate, now time.Time) (time.Time, time.Time, error) {
+	start, end := template.StartTime, template.EndTime
+	if start.IsZero() {
+		if !end.IsZero() && template.Duration > 0 {
+			start = end.Add(-template.Duration)
+		} else {
+			start = now
+		}
+	}
+	if start.Before(now) {
+		start = now
+	}
+	if end.IsZero() {
+		if template.Duration <= 0 {
+			return start, end, fmt.Errorf("reservation requires an EndTime or a Duration")
+		}
+		end = start.Add(template.Duration)
+	}
+	if !end.After(start) {
+		return start, end, fmt.Errorf("reservation end time %s is not after start time %s",
+			end.Format(time.RFC3339), start.Format(time.RFC3339))
+	}
+	return start, end, nil
+}
+
+// matchLocalMachine checks if the machine related requests of the
+// reservation template can be fulfilled by the local machine.
+func matchLocalMachine(template drmaa2interface.ReservationTemplate) error {
+	if len(template.CandidateMachines) > 0 {
+		host, _ := os.Hostname()
+		found := false
+		for _, machine := range template.CandidateMachines {
+			if machine == host || machine == "localhost" {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("local machine %s is not in the list of candidate machines", host)
+		}
+	}
+	if template.MachineOs != "" && !strings.EqualFold(template.MachineOs, runtime.GOOS) &&
+		!strings.EqualFold(template.MachineOs, localMachineOS().String()) {
+		return fmt.Errorf("requested machine OS %s does not match local OS %s",
+			template.MachineOs, runtime.GOOS)
+	}
+	if template.MachineArch != "" && !strings.EqualFold(template.MachineArch, runtime.GOARCH) &&
+		!strings.EqualFold(template.MachineArch, localMachineArch().String()) {
+		return fmt.Errorf("requested machine architecture %s does not match local architecture %s",
+			template.MachineArch, runtime.GOARCH)
+	}
+	if template.MinPhysMemory > 0 {
+		vm, err := mem.VirtualMemory()
+		if err != nil {
+			return fmt.Errorf("failed to get physical memory of local machine: %v", err)
+		}
+		// MinPhysMemory is specified in kilobytes
+		if uint64(template.MinPhysMemory) > vm.Total/1024 {
+			return fmt.Errorf("requested physical memory of %d KB exceeds local memory of %d KB",
+				template.MinPhysMemory, vm.Total/1024)
+		}
+	}
+	return nil
+}
+
+func localMachineOS() drmaa2interface.OS {
+	switch runtime.GOOS {
+	case "linux":
+		return drmaa2interface.Linux
+	case "darwin":
+		return drmaa2interface.MacOS
+	case "freebsd", "netbsd", "openbsd":
+		return drmaa2interface.BSD
+	case "aix":
+		return drmaa2interface.AIX
+	case "solaris", "illumos":
+		return drmaa2interface.SunOS
+	case "windows":
+		return drmaa2interface.Win
+	}
+	return drmaa2interface.OtherOS
+}
+
+func localMachineArch() drmaa2interface.CPU {
+	switch runtime.GOARCH {
+	case "amd64":
+		return drmaa2interface.X64
+	case "386":
+		return drmaa2interface.X86
+	case "arm":
+		return drmaa2interface.ARM
+	case "arm64":
+		return drmaa2interface.ARM64
+	case "mips", "mipsle":
+		return drmaa2interface.MIPS
+	case "mips64", "mips64le":
+		return drmaa2interface.MIPS64
+	case "ppc64", "ppc64le":
+		return drmaa2interface.PowerPC64
+	case "sparc64":
+		return drmaa2interface.SPARC64
+	}
+	return drmaa2interface.OtherCPU
+}
+
+// checkReservationACL returns an error if the ACL is set and the
+// current user is not part of it.
+func checkReservationACL(acl []string) error {
+	if len(acl) == 0 {
+		return nil
+	}
+	current, err := user.Current()
+	if err != nil {
+		return fmt.Errorf("failed to get current user for checking reservation ACL: %v", err)
+	}
+	for _, name := range acl {
+		if name == "*" || name == current.Username || name == current.Uid {
+			return nil
+		}
+	}
+	return fmt.Errorf("user %s is not allowed to use the reservation", current.Username)
+}
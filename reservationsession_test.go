@@ -0,0 +1,288 @@
+package drmaa2os_test
+
+import (
+	"os"
+	"runtime"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/dgruber/drmaa2interface"
+	"github.com/dgruber/drmaa2os"
+
+	_ "github.com/dgruber/drmaa2os/pkg/jobtracker/simpletracker"
+)
+
+var _ = Describe("ReservationSession", func() {
+
+	var (
+		sm drmaa2interface.SessionManager
+		rs drmaa2interface.ReservationSession
+		js drmaa2interface.JobSession
+	)
+
+	BeforeEach(func() {
+		os.Remove("drmaa2ostestreservation")
+		var err error
+		sm, err = drmaa2os.NewDefaultSessionManager("drmaa2ostestreservation")
+		Ω(err).Should(BeNil())
+		rs, err = sm.CreateReservationSession("reservationsession", "")
+		Ω(err).Should(BeNil())
+		js, err = sm.CreateJobSession("jobsession", "")
+		Ω(err).Should(BeNil())
+	})
+
+	AfterEach(func() {
+		os.Remove("drmaa2ostestreservation")
+	})
+
+	Context("Reservation requests", func() {
+
+		It("should grant a reservation which starts now", func() {
+			r, err := rs.RequestReservation(drmaa2interface.ReservationTemplate{
+				Name:     "now",
+				Duration: time.Minute,
+				MinSlots: 1,
+			})
+			Ω(err).Should(BeNil())
+			Ω(r).ShouldNot(BeNil())
+
+			info, err := r.GetInfo()
+			Ω(err).Should(BeNil())
+			Ω(info.ReservationName).Should(Equal("now"))
+			Ω(info.ReservedSlots).Should(BeNumerically("==", 1))
+			Ω(info.ReservationEndTime.Sub(info.ReservationStartTime)).Should(Equal(time.Minute))
+
+			id, err := r.GetID()
+			Ω(err).Should(BeNil())
+			r2, err := rs.GetReservation(id)
+			Ω(err).Should(BeNil())
+			template, err := r2.GetTemplate()
+			Ω(err).Should(BeNil())
+			Ω(template.Name).Should(Equal("now"))
+
+			reservations, err := rs.GetReservations()
+			Ω(err).Should(BeNil())
+			Ω(len(reservations)).Should(BeNumerically("==", 1))
+		})
+
+		It("should reject reservations without end time or duration", func() {
+			_, err := rs.RequestReservation(drmaa2interface.ReservationTemplate{
+				MinSlots: 1,
+			})
+			Ω(err).ShouldNot(BeNil())
+		})
+
+		It("should reject reservations exceeding the slots of the machine", func() {
+			_, err := rs.RequestReservation(drmaa2interface.ReservationTemplate{
+				Duration: time.Minute,
+				MaxSlots: int64(runtime.NumCPU()),
+				MinSlots: int64(runtime.NumCPU()),
+			})
+			Ω(err).Should(BeNil())
+			_, err = rs.RequestReservation(drmaa2interface.ReservationTemplate{
+				Duration: time.Minute,
+				MinSlots: 1,
+			})
+			Ω(err).ShouldNot(BeNil())
+			// non-overlapping reservation is fine
+			_, err = rs.RequestReservation(drmaa2interface.ReservationTemplate{
+				StartTime: time.Now().Add(time.Hour),
+				Duration:  time.Minute,
+				MinSlots:  1,
+			})
+			Ω(err).Should(BeNil())
+		})
+
+		It("should free the slots when a reservation is terminated", func() {
+			r, err := rs.RequestReservation(drmaa2interface.ReservationTemplate{
+				Duration: time.Minute,
+				MinSlots: int64(runtime.NumCPU()),
+			})
+			Ω(err).Should(BeNil())
+			Ω(r.Terminate()).Should(BeNil())
+			_, err = r.GetInfo()
+			Ω(err).ShouldNot(BeNil())
+			_, err = rs.RequestReservation(drmaa2interface.ReservationTemplate{
+				Duration: time.Minute,
+				MinSlots: int64(runtime.NumCPU()),
+			})
+			Ω(err).Should(BeNil())
+		})
+
+		It("should reject reservations for other machines", func() {
+			_, err := rs.RequestReservation(drmaa2interface.ReservationTemplate{
+				Duration:          time.Minute,
+				CandidateMachines: []string{"doesnotexist.example.com"},
+			})
+			Ω(err).ShouldNot(BeNil())
+		})
+
+		It("should keep reservations when the session is re-opened", func() {
+			r, err := rs.RequestReservation(drmaa2interface.ReservationTemplate{
+				Duration: time.Minute,
+			})
+			Ω(err).Should(BeNil())
+			id, _ := r.GetID()
+			Ω(rs.Close()).Should(BeNil())
+
+			rs, err = sm.OpenReservationSession("reservationsession")
+			Ω(err).Should(BeNil())
+			_, err = rs.GetReservation(id)
+			Ω(err).Should(BeNil())
+		})
+
+	})
+
+	Context("Jobs running in reservations", func() {
+
+		It("should run jobs only within the reserved slots", func() {
+			r, err := rs.RequestReservation(drmaa2interface.ReservationTemplate{
+				Duration: time.Minute,
+				MinSlots: 1,
+				MaxSlots: 1,
+			})
+			Ω(err).Should(BeNil())
+			id, _ := r.GetID()
+
+			jt := drmaa2interface.JobTemplate{
+				RemoteCommand: "/bin/sleep",
+				Args:          []string{"0.5"},
+				ReservationID: id,
+			}
+			job, err := js.RunJob(jt)
+			Ω(err).Should(BeNil())
+
+			// slot is in use
+			_, err = js.RunJob(jt)
+			Ω(err).ShouldNot(BeNil())
+
+			Ω(job.WaitTerminated(drmaa2interface.InfiniteTime)).Should(BeNil())
+			Ω(job.GetState()).Should(Equal(drmaa2interface.Done))
+
+			// slot is free again
+			job, err = js.RunJob(jt)
+			Ω(err).Should(BeNil())
+			Ω(job.WaitTerminated(drmaa2interface.InfiniteTime)).Should(BeNil())
+		})
+
+		It("should reject jobs for unknown reservations", func() {
+			_, err := js.RunJob(drmaa2interface.JobTemplate{
+				RemoteCommand: "/bin/sleep",
+				Args:          []string{"0"},
+				ReservationID: "unknown",
+			})
+			Ω(err).ShouldNot(BeNil())
+		})
+
+		It("should queue jobs until the reservation starts", func() {
+			r, err := rs.RequestReservation(drmaa2interface.ReservationTemplate{
+				StartTime: time.Now().Add(time.Second),
+				Duration:  time.Minute,
+				MinSlots:  1,
+			})
+			Ω(err).Should(BeNil())
+			id, _ := r.GetID()
+
+			jt := drmaa2interface.JobTemplate{
+				RemoteCommand: "/bin/sleep",
+				Args:          []string{"0"},
+				ReservationID: id,
+			}
+			job, err := js.RunJob(jt)
+			Ω(err).Should(BeNil())
+			Ω(job.GetState()).Should(Equal(drmaa2interface.Queued))
+			// queued jobs consume the slots of the reservation
+			_, err = js.RunJob(jt)
+			Ω(err).ShouldNot(BeNil())
+
+			Ω(job.WaitTerminated(time.Second * 10)).Should(BeNil())
+			Ω(job.GetState()).Should(Equal(drmaa2interface.Done))
+			info, err := r.GetInfo()
+			Ω(err).Should(BeNil())
+			ji, err := job.GetJobInfo()
+			Ω(err).Should(BeNil())
+			Ω(ji.DispatchTime).Should(BeTemporally(">=", info.ReservationStartTime))
+		})
+
+		It("should terminate jobs at the end of the reservation", func() {
+			r, err := rs.RequestReservation(drmaa2interface.ReservationTemplate{
+				Duration: time.Second,
+			})
+			Ω(err).Should(BeNil())
+			id, _ := r.GetID()
+
+			job, err := js.RunJob(drmaa2interface.JobTemplate{
+				RemoteCommand: "/bin/sleep",
+				Args:          []string{"60"},
+				ReservationID: id,
+			})
+			Ω(err).Should(BeNil())
+			Ω(job.WaitTerminated(time.Second * 10)).Should(BeNil())
+			Ω(job.GetState()).Should(Equal(drmaa2interface.Failed))
+		})
+
+		It("should terminate jobs when the reservation is terminated", func() {
+			r, err := rs.RequestReservation(drmaa2interface.ReservationTemplate{
+				Duration: time.Hour,
+			})
+			Ω(err).Should(BeNil())
+			id, _ := r.GetID()
+
+			job, err := js.RunJob(drmaa2interface.JobTemplate{
+				RemoteCommand: "/bin/sleep",
+				Args:          []string{"60"},
+				ReservationID: id,
+			})
+			Ω(err).Should(BeNil())
+			Ω(job.WaitStarted(time.Second * 10)).Should(BeNil())
+			Ω(r.Terminate()).Should(BeNil())
+			Ω(job.WaitTerminated(time.Second * 10)).Should(BeNil())
+			Ω(job.GetState()).Should(Equal(drmaa2interface.Failed))
+		})
+
+		It("should terminate jobs when the reservation session is destroyed", func() {
+			r, err := rs.RequestReservation(drmaa2interface.ReservationTemplate{
+				Duration: time.Hour,
+			})
+			Ω(err).Should(BeNil())
+			id, _ := r.GetID()
+
+			job, err := js.RunJob(drmaa2interface.JobTemplate{
+				RemoteCommand: "/bin/sleep",
+				Args:          []string{"60"},
+				ReservationID: id,
+			})
+			Ω(err).Should(BeNil())
+			Ω(job.WaitStarted(time.Second * 10)).Should(BeNil())
+			Ω(rs.Close()).Should(BeNil())
+			Ω(sm.DestroyReservationSession("reservationsession")).Should(BeNil())
+			Ω(job.WaitTerminated(time.Second * 10)).Should(BeNil())
+			Ω(job.GetState()).Should(Equal(drmaa2interface.Failed))
+		})
+
+		It("should limit job arrays to the reserved slots", func() {
+			r, err := rs.RequestReservation(drmaa2interface.ReservationTemplate{
+				Duration: time.Minute,
+				MinSlots: 1,
+			})
+			Ω(err).Should(BeNil())
+			id, _ := r.GetID()
+
+			jt := drmaa2interface.JobTemplate{
+				RemoteCommand: "/bin/sleep",
+				Args:          []string{"0"},
+				ReservationID: id,
+			}
+			_, err = js.RunBulkJobs(jt, 1, 2, 1, 0)
+			Ω(err).ShouldNot(BeNil())
+
+			aj, err := js.RunBulkJobs(jt, 1, 2, 1, 1)
+			Ω(err).Should(BeNil())
+			Ω(len(aj.GetJobs())).Should(BeNumerically("==", 2))
+		})
+
+	})
+
+})
@@ -81,6 +81,8 @@ type SessionManager struct {
 	log                    lager.Logger
 	sessionType            SessionType
 	jobTrackerCreateParams interface{}
+	// events distributes job events of all open job sessions
+	events *eventBroker
 }
 
 // NewDefaultSessionManager creates a SessionManager which starts jobs
@@ -250,6 +252,8 @@ func (sm *SessionManager) CreateJobSession(name, contact string) (drmaa2interfac
 		return nil, err
 	}
 	js := newJobSession(name, []jobtracker.JobTracker{jt})
+	js.reservations = sm.reservationStore()
+	sm.events.addSession(js)
 
 	// for libdrmaa return contact string and store it for open job session
 	if sm.sessionType == LibDRMAASession && sm.jobTrackerCreateParams != nil {
@@ -270,9 +274,21 @@ func (sm *SessionManager) CreateJobSession(name, contact string) (drmaa2interfac
 	return js, nil
 }
 
-// CreateReservationSession creates a new ReservationSession.
+// CreateReservationSession creates a new ReservationSession. Reservations
+// are currently only supported for the DefaultSession (processes).
 func (sm *SessionManager) CreateReservationSession(name, contact string) (drmaa2interface.ReservationSession, error) {
-	return nil, ErrorUnsupportedOperation
+	rs := sm.reservationStore()
+	if rs == nil {
+		return nil, ErrorUnsupportedOperation
+	}
+	if err := rs.createSession(name, contact); err != nil {
+		return nil, err
+	}
+	return &ReservationSession{
+		name:    name,
+		contact: contact,
+		store:   rs,
+	}, nil
 }
 
 // OpenMonitoringSession opens a session for monitoring jobs.
@@ -319,15 +335,29 @@ func (sm *SessionManager) OpenJobSession(name string) (drmaa2interface.JobSessio
 		return nil, err
 	}
 	js := JobSession{
-		name:    name,
-		tracker: []jobtracker.JobTracker{jt},
+		name:         name,
+		tracker:      []jobtracker.JobTracker{jt},
+		reservations: sm.reservationStore(),
 	}
+	sm.events.addSession(&js)
 	return &js, nil
 }
 
 // OpenReservationSession opens a reservation session.
 func (sm *SessionManager) OpenReservationSession(name string) (drmaa2interface.ReservationSession, error) {
-	return nil, ErrorUnsupportedOperation
+	rs := sm.reservationStore()
+	if rs == nil {
+		return nil, ErrorUnsupportedOperation
+	}
+	contact, err := rs.contact(name)
+	if err != nil {
+		return nil, err
+	}
+	return &ReservationSession{
+		name:    name,
+		contact: contact,
+		store:   rs,
+	}, nil
 }
 
 // DestroyJobSession destroys a job session by name.
@@ -347,9 +377,26 @@ func (sm *SessionManager) DestroyJobSession(name string) error {
 	return sm.delete(storage.JobSessionType, name)
 }
 
-// DestroyReservationSession removes a reservation session.
+// DestroyReservationSession removes a reservation session and
+// all of its reservations.
 func (sm *SessionManager) DestroyReservationSession(name string) error {
-	return ErrorUnsupportedOperation
+	if sm.reservationStore() == nil {
+		return ErrorUnsupportedOperation
+	}
+	reservationMutex.Lock()
+	defer reservationMutex.Unlock()
+	record, err := sm.reservationStore().load(name)
+	if err != nil {
+		return err
+	}
+	if err := sm.delete(storage.ReservationSessionType, name); err != nil {
+		return err
+	}
+	// stop the jobs running in the reservations of the session
+	for _, reservation := range record.Reservations {
+		terminateReservation(reservation.Info.ReservationID)
+	}
+	return nil
 }
 
 // GetJobSessionNames returns a list of all job sessions.
@@ -359,26 +406,73 @@ func (sm *SessionManager) GetJobSessionNames() ([]string, error) {
 
 // GetReservationSessionNames returns a list of all reservation sessions.
 func (sm *SessionManager) GetReservationSessionNames() ([]string, error) {
-	return nil, ErrorUnsupportedOperation
+	if sm.reservationStore() == nil {
+		return nil, ErrorUnsupportedOperation
+	}
+	return sm.store.List(storage.ReservationSessionType)
 }
 
 // GetDrmsName returns the name of the distributed resource manager.
+// When the registered JobTracker declares its capabilities the name of
+// the backend is returned (like "slurm" or "kubernetes"), otherwise
+// "drmaa2os".
 func (sm *SessionManager) GetDrmsName() (string, error) {
+	if capabilities := sm.capabilities(); capabilities != nil {
+		return capabilities.DrmsName(), nil
+	}
 	return "drmaa2os", nil
 }
 
 // GetDrmsVersion returns the version of the distributed resource manager.
+// When the registered JobTracker declares its capabilities the version of
+// the backend is returned, otherwise the version of drmaa2os.
 func (sm *SessionManager) GetDrmsVersion() (drmaa2interface.Version, error) {
+	if capabilities := sm.capabilities(); capabilities != nil {
+		return capabilities.DrmsVersion()
+	}
 	return drmaa2interface.Version{Minor: "0", Major: "1"}, nil
 }
 
-// Supports returns true of false of the given Capability is supported by DRMAA2OS.
+// Supports returns true of false of the given Capability is supported by
+// the registered JobTracker. Callback is always supported as job trackers
+// which can't push job events are polled (see RegisterEventNotification).
+// JobTrackers which don't declare their capabilities support nothing else.
 func (sm *SessionManager) Supports(capability drmaa2interface.Capability) bool {
+	if capability == drmaa2interface.Callback {
+		return true
+	}
+	if capabilities := sm.capabilities(); capabilities != nil {
+		return capabilities.Supports(capability)
+	}
 	return false
 }
 
-// RegisterEventNotification creates an event channel which emits events when
-// the conditions described in the given notification specification are met.
+// SupportsJobControl returns true if the registered JobTracker supports
+// the given job control action (like jobtracker.JobControlHold). This is
+// an extension to the DRMAA2 SessionManager interface which allows to
+// check in advance which of the Job methods Suspend(), Resume(), Hold(),
+// Release(), and Terminate() can be used.
+func (sm *SessionManager) SupportsJobControl(action string) bool {
+	if capabilities := sm.capabilities(); capabilities != nil {
+		return capabilities.SupportsJobControl(action)
+	}
+	return action == jobtracker.JobControlTerminate
+}
+
+// RegisterEventNotification creates an event channel which emits events
+// (NewState, Migrated, AttributeChange) for all jobs of all job sessions
+// which are open in this SessionManager. Job sessions which are opened
+// later are included as well. Job trackers which can't push job events
+// are polled in the EventPollInterval. Events are dropped when the
+// channel is not consumed in the rate the events are created. The
+// channel is closed by UnregisterEventNotification.
 func (sm *SessionManager) RegisterEventNotification() (drmaa2interface.EventChannel, error) {
-	return nil, ErrorUnsupportedOperation
+	return sm.events.subscribe(), nil
+}
+
+// UnregisterEventNotification stops emitting events into an event
+// channel created by RegisterEventNotification and closes the channel.
+// This is an extension to the DRMAA2 SessionManager interface.
+func (sm *SessionManager) UnregisterEventNotification(ch drmaa2interface.EventChannel) error {
+	return sm.events.unsubscribe(ch)
 }
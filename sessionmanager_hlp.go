@@ -63,6 +63,20 @@ func (sm *SessionManager) newRegisteredJobTracker(jobSessionName string, params
 	return jtMap[sm.sessionType].New(jobSessionName, params)
 }
 
+// capabilities returns the capabilities of the registered JobTracker or
+// nil if the JobTracker does not declare them.
+func (sm *SessionManager) capabilities() jobtracker.Capabilities {
+	jtMap := atomicTrackers.Load().(map[SessionType]jobtracker.Allocator)
+	if jtMap == nil {
+		return nil
+	}
+	capabilities, ok := jtMap[sm.sessionType].(jobtracker.Capabilities)
+	if !ok {
+		return nil
+	}
+	return capabilities
+}
+
 func makeSessionManager(dbpath string, st SessionType) (*SessionManager, error) {
 	s := boltstore.NewBoltStore(dbpath)
 	if err := s.Init(); err != nil {
@@ -70,7 +84,16 @@ func makeSessionManager(dbpath string, st SessionType) (*SessionManager, error)
 	}
 	l := lager.NewLogger("sessionmanager")
 	l.RegisterSink(lager.NewWriterSink(os.Stdout, lager.INFO))
-	return &SessionManager{store: s, log: l, sessionType: st}, nil
+	return &SessionManager{store: s, log: l, sessionType: st, events: newEventBroker()}, nil
+}
+
+// reservationStore returns the store for advance reservations or nil
+// if the session type does not support reservations.
+func (sm *SessionManager) reservationStore() *reservationStore {
+	if sm.sessionType != DefaultSession {
+		return nil
+	}
+	return newReservationStore(sm.store)
 }
 
 func (sm *SessionManager) logErr(message string) error {
@@ -5,6 +5,7 @@ import (
 	"github.com/dgruber/drmaa2os"
 
 	"os"
+	"time"
 
 	// test with process tracker
 	_ "github.com/dgruber/drmaa2os/pkg/jobtracker/dockertracker"
@@ -205,12 +206,87 @@ var _ = Describe("Sessionmanager", func() {
 				sm.Supports(drmaa2interface.RtMachineOS)
 				sm.Supports(drmaa2interface.RtMachineArch)
 			})
+
+			It("should report the capabilities of the process backend", func() {
+				name, err := sm.GetDrmsName()
+				Ω(err).Should(BeNil())
+				Ω(name).Should(Equal("process"))
+				Ω(sm.Supports(drmaa2interface.AdvanceReservation)).Should(BeTrue())
+				Ω(sm.Supports(drmaa2interface.BulkJobsMaxParallel)).Should(BeTrue())
+				Ω(sm.Supports(drmaa2interface.Callback)).Should(BeTrue())
+				Ω(sm.Supports(drmaa2interface.JtStaging)).Should(BeTrue())
+				Ω(sm.Supports(drmaa2interface.JtDeadline)).Should(BeTrue())
+				Ω(sm.Supports(drmaa2interface.JtEmail)).Should(BeFalse())
+			})
+
+			It("should report the supported job control actions", func() {
+				manager := sm.(*drmaa2os.SessionManager)
+				Ω(manager.SupportsJobControl("suspend")).Should(BeTrue())
+				Ω(manager.SupportsJobControl("terminate")).Should(BeTrue())
+				Ω(manager.SupportsJobControl("hold")).Should(BeTrue())
+				Ω(manager.SupportsJobControl("release")).Should(BeTrue())
+				Ω(manager.SupportsJobControl("unknown")).Should(BeFalse())
+			})
 		})
 
 		Describe("Register event notification", func() {
 			It("should be callable", func() {
 				sm.RegisterEventNotification()
 			})
+
+			It("should emit job state changes of all open job sessions", func() {
+				ch, err := sm.RegisterEventNotification()
+				Ω(err).Should(BeNil())
+				Ω(ch).ShouldNot(BeNil())
+
+				js, err := sm.CreateJobSession("eventsession", "")
+				Ω(err).Should(BeNil())
+				defer sm.DestroyJobSession("eventsession")
+				defer js.Close()
+
+				job, err := js.RunJob(drmaa2interface.JobTemplate{
+					RemoteCommand: "/bin/sleep",
+					Args:          []string{"0"},
+				})
+				Ω(err).Should(BeNil())
+
+				states := []drmaa2interface.JobState{}
+				Eventually(func() []drmaa2interface.JobState {
+					select {
+					case n := <-ch:
+						if n.JobID == job.GetID() && n.Evt == drmaa2interface.NewState {
+							Ω(n.SessionName).Should(Equal("eventsession"))
+							states = append(states, n.State)
+						}
+					default:
+					}
+					return states
+				}, time.Second*5).Should(ContainElement(drmaa2interface.Done))
+				Ω(states).Should(ContainElement(drmaa2interface.Running))
+			})
+
+			It("should close unregistered event channels", func() {
+				manager := sm.(*drmaa2os.SessionManager)
+				ch, err := manager.RegisterEventNotification()
+				Ω(err).Should(BeNil())
+
+				js, err := sm.CreateJobSession("eventsession", "")
+				Ω(err).Should(BeNil())
+				defer sm.DestroyJobSession("eventsession")
+				defer js.Close()
+
+				Ω(manager.UnregisterEventNotification(ch)).Should(BeNil())
+				Eventually(ch, time.Second).Should(BeClosed())
+				Ω(manager.UnregisterEventNotification(ch)).ShouldNot(BeNil())
+
+				// jobs are not affected by unregistered channels
+				job, err := js.RunJob(drmaa2interface.JobTemplate{
+					RemoteCommand: "/bin/sleep",
+					Args:          []string{"0"},
+				})
+				Ω(err).Should(BeNil())
+				Ω(job.WaitTerminated(time.Second * 5)).Should(BeNil())
+			})
 		})
 
 	})
@@ -251,9 +327,47 @@ var _ = Describe("Sessionmanager", func() {
 
 	})
 
-	Context("ReservationSession is currently not supported", func() {
+	Context("ReservationSession is supported for the process backend", func() {
+
+		It("should create, open, list, and destroy a reservation session", func() {
+			rs, err := sm.CreateReservationSession("reservationSession", "contact")
+			Ω(err).Should(BeNil())
+			Ω(rs).ShouldNot(BeNil())
+			Ω(rs.Close()).Should(BeNil())
+
+			rs, err = sm.CreateReservationSession("reservationSession", "")
+			Ω(rs).Should(BeNil())
+			Ω(err).ShouldNot(BeNil())
+
+			rs, err = sm.OpenReservationSession("reservationSession")
+			Ω(err).Should(BeNil())
+			Ω(rs).ShouldNot(BeNil())
+			contact, err := rs.GetContact()
+			Ω(err).Should(BeNil())
+			Ω(contact).Should(Equal("contact"))
+
+			names, err := sm.GetReservationSessionNames()
+			Ω(err).Should(BeNil())
+			Ω(names).Should(ConsistOf("reservationSession"))
+
+			err = sm.DestroyReservationSession("reservationSession")
+			Ω(err).Should(BeNil())
+
+			rs, err = sm.OpenReservationSession("reservationSession")
+			Ω(rs).Should(BeNil())
+			Ω(err).ShouldNot(BeNil())
+		})
+
+	})
+
+	Context("ReservationSession is not supported for other backends", func() {
 
 		It("should return an unsupported operation error when using a reservation session", func() {
+			os.Remove("drmaa2ostestdocker")
+			sm, err := drmaa2os.NewDockerSessionManager("drmaa2ostestdocker")
+			Ω(err).Should(BeNil())
+			defer os.Remove("drmaa2ostestdocker")
+
 			rs, err := sm.CreateReservationSession("reservationSession", "")
 			Ω(rs).Should(BeNil())
 			Ω(err).ShouldNot(BeNil())